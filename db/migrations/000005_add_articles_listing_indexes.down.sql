@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_articles_category_created_at_id;
+DROP INDEX IF EXISTS idx_articles_author_created_at_id;
+DROP INDEX IF EXISTS idx_articles_created_at_id;
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_articles_created_at_id ON articles (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_author_created_at_id ON articles (author_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_category_created_at_id ON articles (category_id, created_at DESC, id DESC);
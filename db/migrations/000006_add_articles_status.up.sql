@@ -0,0 +1,10 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'published',
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+-- Уже существующие статьи остаются опубликованными, новые создаются черновиками
+UPDATE articles SET published_at = created_at WHERE published_at IS NULL;
+ALTER TABLE articles ALTER COLUMN status SET DEFAULT 'draft';
+ALTER TABLE articles ADD CONSTRAINT chk_articles_status CHECK (status IN ('draft', 'published', 'archived'));
+
+CREATE INDEX IF NOT EXISTS idx_articles_published_created_at_id ON articles (created_at DESC, id DESC) WHERE status = 'published';
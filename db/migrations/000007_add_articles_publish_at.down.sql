@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_articles_due_publish_at;
+ALTER TABLE articles DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,3 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_articles_due_publish_at ON articles (publish_at) WHERE status = 'draft' AND publish_at IS NOT NULL;
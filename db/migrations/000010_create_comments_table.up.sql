@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    author_id BIGINT REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    parent_id BIGINT REFERENCES comments(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    is_deleted BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_article_root ON comments (article_id, created_at, id) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_parent ON comments (parent_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_author_id ON comments (author_id);
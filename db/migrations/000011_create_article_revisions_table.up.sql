@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    number INT NOT NULL,
+    title VARCHAR(128) NOT NULL,
+    content TEXT NOT NULL,
+    category_id BIGINT REFERENCES categories(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    editor_id BIGINT REFERENCES users(id) ON UPDATE CASCADE ON DELETE SET NULL,
+    UNIQUE (article_id, number)
+);
+
+-- Текущее состояние существующих статей становится их первой ревизией
+INSERT INTO article_revisions (created_at, article_id, number, title, content, category_id, editor_id)
+SELECT updated_at, id, 1, title, content, category_id, author_id FROM articles
+ON CONFLICT (article_id, number) DO NOTHING;
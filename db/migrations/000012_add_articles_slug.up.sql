@@ -0,0 +1,45 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug VARCHAR(128);
+
+-- Slug для существующих статей: та же транслитерация, что и в domain.Slugify,
+-- при совпадении к slug добавляется id статьи. Таблицу замен с domain сверяет
+-- TestSlugifyMatchesMigration. Отличие одно: lower() приводит кириллицу к нижнему
+-- регистру только в базе с UTF-8 локалью, в локали C заглавные буквы станут дефисами
+WITH raw AS (
+    -- Slugify не ставит дефис в начало, поэтому он срезается до обрезки по длине
+    SELECT id, ltrim(regexp_replace(
+        translate(
+            replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(
+                lower(title), 'щ', 'shch'), 'ш', 'sh'), 'ч', 'ch'), 'ц', 'ts'), 'ж', 'zh'), 'х', 'kh'),
+                'ю', 'yu'), 'я', 'ya'), 'ё', 'yo'), 'ї', 'yi'), 'є', 'ye'), '''', ''), '’', ''),
+            'абвгдезийклмнопрстуфыэіґъь', 'abvgdeziyklmnoprstufyeig'),
+        '[^a-z0-9]+', '-', 'g'), '-') AS slug
+    FROM articles
+), cut AS (
+    -- Как truncateSlug: длина MaxArticleSlugLength (100), обрезка по последнему дефису во второй половине
+    SELECT id, left(slug, 100) AS slug, length(slug) > 100 AS truncated FROM raw
+), base AS (
+    SELECT id, COALESCE(NULLIF(trim(BOTH '-' FROM CASE
+        WHEN truncated AND strpos(reverse(slug), '-') > 0 AND length(slug) - strpos(reverse(slug), '-') > 50
+            THEN left(slug, length(slug) - strpos(reverse(slug), '-'))
+        ELSE slug
+    END), ''), 'article') AS slug
+    FROM cut
+), ranked AS (
+    SELECT id, slug, row_number() OVER (PARTITION BY slug ORDER BY id) AS n FROM base
+)
+UPDATE articles
+SET slug = CASE WHEN ranked.n = 1 THEN ranked.slug ELSE ranked.slug || '-' || articles.id END
+FROM ranked
+WHERE ranked.id = articles.id;
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_slug ON articles (slug);
+
+-- Прежние slug статьи, по ним отдаётся редирект на актуальный
+CREATE TABLE IF NOT EXISTS article_slugs (
+    slug VARCHAR(128) PRIMARY KEY,
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_slugs_article_id ON article_slugs (article_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_sessions_user_id_active;
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,8 @@
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip VARCHAR(45) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;
+
+UPDATE sessions SET last_used_at = created_at WHERE last_used_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id_active ON sessions (user_id, last_used_at DESC) WHERE is_revoked = FALSE;
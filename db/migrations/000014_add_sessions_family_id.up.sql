@@ -0,0 +1,7 @@
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS family_id UUID;
+
+-- Каждая существующая сессия становится отдельным семейством
+UPDATE sessions SET family_id = id WHERE family_id IS NULL;
+
+ALTER TABLE sessions ALTER COLUMN family_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions (family_id);
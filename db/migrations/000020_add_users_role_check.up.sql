@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'editor', 'moderator', 'user'));
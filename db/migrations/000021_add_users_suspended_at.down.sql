@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+DROP INDEX IF EXISTS idx_users_username_prefix;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
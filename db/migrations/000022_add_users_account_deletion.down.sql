@@ -0,0 +1,8 @@
+DELETE FROM users WHERE username = 'deleted user' AND NOT EXISTS (
+    SELECT 1 FROM articles WHERE articles.author_id = users.id
+);
+
+DROP INDEX IF EXISTS idx_users_deletion_scheduled_at;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deletion_articles,
+    DROP COLUMN IF EXISTS deletion_scheduled_at;
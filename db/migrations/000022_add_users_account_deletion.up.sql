@@ -0,0 +1,11 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deletion_articles VARCHAR(16) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users (deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
+
+-- Служебный автор обезличенных статей. Пароль не является хэшем, войти под ним нельзя
+INSERT INTO users (username, email, password_hash, role, suspended_at)
+VALUES ('deleted user', 'deleted user@invalid', '!', 'user', NOW())
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_articles_author_published;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS social_links,
+    DROP COLUMN IF EXISTS location,
+    DROP COLUMN IF EXISTS website,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS display_name;
@@ -0,0 +1,11 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bio TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS website VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS location VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS social_links JSONB NOT NULL DEFAULT '[]';
+
+-- Статистика профиля считается по опубликованным статьям автора
+CREATE INDEX IF NOT EXISTS idx_articles_author_published ON articles (author_id, published_at)
+    WHERE status = 'published';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_articles_category_published;
+DROP INDEX IF EXISTS idx_articles_published_at_id;
+DROP TABLE IF EXISTS category_subscriptions;
+DROP TABLE IF EXISTS follows;
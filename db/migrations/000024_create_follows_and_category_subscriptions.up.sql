@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id BIGINT NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    followee_id BIGINT NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CONSTRAINT chk_follows_not_self CHECK (follower_id <> followee_id)
+);
+
+-- Списки подписок и подписчиков отдаются от новых к старым
+CREATE INDEX IF NOT EXISTS idx_follows_follower_created_at ON follows (follower_id, created_at DESC, followee_id DESC);
+CREATE INDEX IF NOT EXISTS idx_follows_followee_created_at ON follows (followee_id, created_at DESC, follower_id DESC);
+
+CREATE TABLE IF NOT EXISTS category_subscriptions (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    category_id BIGINT NOT NULL REFERENCES categories(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, category_id)
+);
+
+-- Лента идёт по времени публикации
+CREATE INDEX IF NOT EXISTS idx_articles_published_at_id ON articles (published_at DESC, id DESC) WHERE status = 'published';
+CREATE INDEX IF NOT EXISTS idx_articles_category_published ON articles (category_id, published_at DESC, id DESC) WHERE status = 'published';
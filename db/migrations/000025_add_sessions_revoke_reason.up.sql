@@ -0,0 +1,13 @@
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS revoke_reason VARCHAR(16) NOT NULL DEFAULT '';
+
+-- Ротированная сессия - та, у которой в семействе есть более поздняя:
+-- новая сессия создаётся в момент ротации. Остальные отозванные считаем
+-- отозванными явно, чтобы их повторный токен не отзывал всё семейство
+UPDATE sessions s SET revoke_reason = CASE
+    WHEN EXISTS (
+        SELECT 1 FROM sessions n
+        WHERE n.family_id = s.family_id AND n.id <> s.id AND n.last_used_at > s.last_used_at
+    ) THEN 'rotated'
+    ELSE 'revoked'
+END
+WHERE s.is_revoked;
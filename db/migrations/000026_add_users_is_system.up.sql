@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_system BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Служебного автора из 000022 находим по всем полям сразу: одно имя могло достаться другому аккаунту
+UPDATE users SET is_system = TRUE
+WHERE username = 'deleted user' AND email = 'deleted user@invalid' AND password_hash = '!';
+
+INSERT INTO users (username, email, password_hash, role, suspended_at, is_system)
+SELECT 'deleted user', 'deleted user@invalid', '!', 'user', NOW(), TRUE
+WHERE NOT EXISTS (SELECT 1 FROM users WHERE is_system)
+ON CONFLICT DO NOTHING;
+
+-- Служебный пользователь ровно один
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_is_system ON users (is_system) WHERE is_system;
@@ -2,22 +2,31 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"my_blog_backend/internal/config"
 	"my_blog_backend/internal/delivery"
 	v1 "my_blog_backend/internal/delivery/v1"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/internal/repository/memory"
 	"my_blog_backend/internal/repository/postgres"
 	"my_blog_backend/internal/server"
 	"my_blog_backend/internal/usecase"
 	"my_blog_backend/pkg/auth/hash"
+	"my_blog_backend/pkg/auth/password"
 	"my_blog_backend/pkg/auth/token"
+	"my_blog_backend/pkg/clock"
+	"my_blog_backend/pkg/mailer"
+	"my_blog_backend/pkg/markdown"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 const (
@@ -48,33 +57,139 @@ func Run() {
 	articleRepo := postgres.NewArticleRepository(pgDatabase.Db)
 	categoryRepo := postgres.NewCategoryRepository(pgDatabase.Db)
 	sessionRepo := postgres.NewSessionRepository(pgDatabase.Db)
+	tagRepo := postgres.NewTagRepository(pgDatabase.Db)
+	commentRepo := postgres.NewCommentRepository(pgDatabase.Db)
+	revisionRepo := postgres.NewRevisionRepository(pgDatabase.Db)
 	userRepo := postgres.NewUserRepository(pgDatabase.Db)
+	passwordResetRepo := postgres.NewPasswordResetRepository(pgDatabase.Db)
+	emailVerificationRepo := postgres.NewEmailVerificationRepository(pgDatabase.Db)
+	recoveryCodeRepo := postgres.NewRecoveryCodeRepository(pgDatabase.Db)
+	accessTokenRepo := postgres.NewAccessTokenRepository(pgDatabase.Db)
+	followRepo := postgres.NewFollowRepository(pgDatabase.Db)
+	categorySubscriptionRepo := postgres.NewCategorySubscriptionRepository(pgDatabase.Db)
+	feedRepo := postgres.NewFeedRepository(pgDatabase.Db)
 
-	tokenManager := token.NewTokenManager(secret, jwtTTL)
-	hashManager, err := hash.NewBcryptHashManager(bcrypt.DefaultCost)
+	jwtCfg := config.LoadJWTConfig()
+	jwtKeys, err := newJWTKeys(jwtCfg, secret)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	articleService := usecase.NewArticleService(articleRepo, userRepo, categoryRepo)
-	categoryService := usecase.NewCategoryService(categoryRepo)
-	userService := usecase.NewUserService(userRepo, articleRepo, sessionRepo, tokenManager, hashManager)
-	services := usecase.NewServices(userService, articleService, categoryService)
+	// Прежние установки хэшировали refresh токены на SECRET. Чтобы не завершать их сессии,
+	// достаточно явно задать REFRESH_TOKEN_SECRET равным SECRET
+	if jwtCfg.RefreshTokenSecret == "" {
+		log.Fatal("REFRESH_TOKEN_SECRET must be set")
+	}
+
+	tokenManager := token.NewTokenManager(jwtKeys, jwtCfg.RefreshTokenSecret, jwtTTL)
+	hashManager, err := newHashManager(config.LoadPasswordHashConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	realClock := clock.New()
+
+	mailSender, err := newMailer(config.LoadMailerConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	resetCfg := config.LoadPasswordResetConfig()
+	verificationCfg := config.LoadEmailVerificationConfig()
+	twoFactorCfg := config.LoadTwoFactorConfig()
+	throttleCfg := config.LoadLoginThrottleConfig()
+
+	loginAttemptRepo, err := newLoginAttemptRepository(throttleCfg.Store, pgDatabase.Db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	loginThrottle := usecase.NewLoginThrottle(loginAttemptRepo, realClock,
+		domain.LockoutPolicy{
+			FreeAttempts: throttleCfg.MaxAttemptsPerEmail,
+			BaseDelay:    throttleCfg.BaseDelay,
+			MaxDelay:     throttleCfg.MaxDelay,
+			Window:       throttleCfg.Window,
+		},
+		domain.LockoutPolicy{
+			FreeAttempts: throttleCfg.MaxAttemptsPerIP,
+			BaseDelay:    throttleCfg.BaseDelay,
+			MaxDelay:     throttleCfg.MaxDelay,
+			Window:       throttleCfg.Window,
+		},
+	)
+
+	passwordPolicyCfg := config.LoadPasswordPolicyConfig()
+	passwordChecker, err := newPasswordChecker(passwordPolicyCfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	passwordValidator := usecase.NewPasswordValidator(domain.PasswordPolicy{
+		MinLength: passwordPolicyCfg.MinLength,
+		MaxLength: passwordPolicyCfg.MaxLength,
+		MinScore:  passwordPolicyCfg.MinScore,
+	}, passwordChecker)
+
+	authorizationService := usecase.NewAuthorizationService(userRepo)
+	markdownRenderer := markdown.New()
+	articleService := usecase.NewArticleService(articleRepo, userRepo, categoryRepo, tagRepo, revisionRepo, authorizationService, markdownRenderer, realClock, verificationCfg.Required)
+	categoryService := usecase.NewCategoryService(categoryRepo, authorizationService)
+	commentService := usecase.NewCommentService(commentRepo, articleRepo, authorizationService)
+	emailVerificationService := usecase.NewEmailVerificationService(userRepo, emailVerificationRepo, tokenManager, mailSender, realClock, verificationCfg.URL, verificationCfg.TokenTTL)
+	twoFactorService := usecase.NewTwoFactorService(userRepo, recoveryCodeRepo, tokenManager, hashManager, realClock, twoFactorCfg.Issuer)
+	userService := usecase.NewUserService(userRepo, articleRepo, sessionRepo, tokenManager, hashManager, emailVerificationService, twoFactorService, loginThrottle, passwordValidator)
+	passwordResetService := usecase.NewPasswordResetService(userRepo, sessionRepo, passwordResetRepo, tokenManager, hashManager, mailSender, realClock, loginThrottle, passwordValidator, resetCfg.URL, resetCfg.TokenTTL)
+	accessTokenService := usecase.NewAccessTokenService(accessTokenRepo, userRepo, tokenManager, realClock)
+	adminUserService := usecase.NewAdminUserService(userRepo, sessionRepo, authorizationService, realClock)
+	accountDeletionCfg := config.LoadAccountDeletionConfig()
+	accountService := usecase.NewAccountService(userRepo, articleRepo, commentRepo, sessionRepo, followRepo, categorySubscriptionRepo, hashManager, realClock, accountDeletionCfg.GracePeriod)
+	followService := usecase.NewFollowService(followRepo, categorySubscriptionRepo, userRepo, categoryRepo)
+	feedService := usecase.NewFeedService(feedRepo, markdownRenderer)
+	services := usecase.NewServices(userService, articleService, categoryService, commentService, passwordResetService, emailVerificationService, twoFactorService, accessTokenService, authorizationService, adminUserService, accountService, followService, feedService)
 
-	middleware := v1.NewMiddleware(tokenManager)
-	handler := v1.NewHandler(services, middleware)
+	cookieCfg, err := newCookieConfig(config.LoadAuthCookieConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	authCookies := v1.NewAuthCookies(cookieCfg)
+
+	middleware := v1.NewMiddleware(userService, accessTokenService, authCookies)
+	handler := v1.NewHandler(services, middleware, authCookies)
 
 	r := gin.Default()
 	api := r.Group("")
 	handler.Init(api)
 
+	// Без HTTP_TRUSTED_PROXIES gin по умолчанию доверяет X-Forwarded-For от любого
+	// адреса, и клиент мог бы подменить IP, по которому считаются попытки входа.
+	// nil отключает доверие заголовкам, и IP берётся из соединения
 	serverCfg := config.LoadHttpServerConfig()
+	var trustedProxies []string
+	if len(serverCfg.TrustedProxies) > 0 {
+		trustedProxies = serverCfg.TrustedProxies
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal(err)
+	}
 	srv := server.NewServer(r, serverCfg)
 
 	// 9. Контекст для graceful shutdown
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
+	publisherCfg := config.LoadPublisherConfig()
+	publisher := usecase.NewScheduledPublisher(articleRepo, realClock, publisherCfg.Interval, publisherCfg.BatchSize)
+	publisherDone := make(chan struct{})
+	go func() {
+		defer close(publisherDone)
+		publisher.Run(ctx)
+	}()
+
+	purger := usecase.NewAccountPurger(userRepo, sessionRepo, realClock, accountDeletionCfg.PurgeInterval, accountDeletionCfg.BatchSize)
+	purgerDone := make(chan struct{})
+	go func() {
+		defer close(purgerDone)
+		purger.Run(ctx)
+	}()
+
 	// 10. Запуск сервера в горутине
 	go func() {
 		log.Printf("starting server on port %s", serverCfg.Port)
@@ -94,5 +209,104 @@ func Run() {
 		log.Fatalf("server forced to shutdown: %v", err)
 	}
 
+	<-publisherDone
+	<-purgerDone
+	passwordResetService.Wait()
+
 	log.Println("server stopped gracefully")
 }
+
+func newMailer(cfg config.Mailer) (usecase.Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	case "file":
+		return mailer.NewFileMailer(cfg.FileDir, cfg.From)
+	case "memory":
+		return mailer.NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer driver %q", cfg.Driver)
+	}
+}
+
+func newHashManager(cfg config.PasswordHash) (*hash.Dispatcher, error) {
+	params := hash.DefaultArgon2Params
+	params.Memory = cfg.Argon2Memory
+	params.Iterations = cfg.Argon2Iterations
+	params.Parallelism = cfg.Argon2Parallelism
+
+	argon2, err := hash.NewArgon2HashManager(params)
+	if err != nil {
+		return nil, err
+	}
+
+	bcrypt, err := hash.NewBcryptHashManager(cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return hash.NewDispatcher(argon2, bcrypt), nil
+}
+
+func newPasswordChecker(cfg config.PasswordPolicy) (*password.Checker, error) {
+	if cfg.BreachedPasswordsDir != "" {
+		breaches, err := password.NewRangeDir(cfg.BreachedPasswordsDir)
+		if err != nil {
+			return nil, err
+		}
+
+		return password.NewChecker(breaches), nil
+	}
+
+	breaches, err := password.BundledHashList()
+	if err != nil {
+		return nil, err
+	}
+
+	return password.NewChecker(breaches), nil
+}
+
+func newJWTKeys(cfg config.JWT, secret string) (*token.KeySet, error) {
+	if cfg.KeysDir == "" {
+		log.Println("JWT_KEYS_DIR is not set, signing access tokens with HS256")
+		return token.NewHMACKeySet(secret), nil
+	}
+
+	return token.LoadKeySet(cfg.KeysDir, cfg.SigningKeyID)
+}
+
+func newLoginAttemptRepository(store string, db *gorm.DB) (repository.LoginAttemptRepository, error) {
+	switch store {
+	case "postgres":
+		return postgres.NewLoginAttemptRepository(db), nil
+	case "memory":
+		return memory.NewLoginAttemptRepository(), nil
+	default:
+		return nil, fmt.Errorf("unknown login attempts store %q", store)
+	}
+}
+
+func newCookieConfig(cfg config.AuthCookie) (v1.CookieConfig, error) {
+	res := v1.CookieConfig{
+		Enabled: cfg.Enabled,
+		Domain:  cfg.Domain,
+		Secure:  cfg.Secure,
+	}
+
+	switch strings.ToLower(cfg.SameSite) {
+	case "lax":
+		res.SameSite = http.SameSiteLaxMode
+	case "strict":
+		res.SameSite = http.SameSiteStrictMode
+	case "none":
+		// Браузеры принимают SameSite=None только вместе с Secure
+		if !cfg.Secure {
+			return res, fmt.Errorf("AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE")
+		}
+		res.SameSite = http.SameSiteNoneMode
+	default:
+		return res, fmt.Errorf("unknown cookie SameSite mode %q", cfg.SameSite)
+	}
+
+	return res, nil
+}
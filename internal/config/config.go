@@ -21,10 +21,14 @@ type HttpServer struct {
 	Port         string        `mapstructure:"HTTP_PORT"`
 	ReadTimeout  time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
 	WriteTimeout time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	// Прокси через запятую, которым можно доверить X-Forwarded-For.
+	// От IP клиента зависит ограничение попыток входа
+	TrustedProxies []string `mapstructure:"HTTP_TRUSTED_PROXIES"`
 }
 
 func LoadHttpServerConfig() HttpServer {
 	v := viper.New()
+	v.SetDefault("HTTP_TRUSTED_PROXIES", "")
 
 	// Берём переменные из окружения (godotenv уже их загрузил)
 	v.AutomaticEnv()
@@ -36,3 +40,278 @@ func LoadHttpServerConfig() HttpServer {
 
 	return cfg
 }
+
+type Publisher struct {
+	Interval  time.Duration `mapstructure:"PUBLISHER_INTERVAL"`
+	BatchSize int           `mapstructure:"PUBLISHER_BATCH_SIZE"`
+}
+
+func LoadPublisherConfig() Publisher {
+	v := viper.New()
+	v.SetDefault("PUBLISHER_INTERVAL", 30*time.Second)
+	v.SetDefault("PUBLISHER_BATCH_SIZE", 100)
+	v.AutomaticEnv()
+
+	var cfg Publisher
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal Publisher config: %v", err)
+	}
+
+	if cfg.Interval <= 0 {
+		log.Fatalf("PUBLISHER_INTERVAL must be positive, got %s", cfg.Interval)
+	}
+
+	return cfg
+}
+
+type Mailer struct {
+	// smtp, file или memory
+	Driver       string `mapstructure:"MAILER_DRIVER"`
+	From         string `mapstructure:"MAIL_FROM"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	FileDir      string `mapstructure:"MAILER_FILE_DIR"`
+}
+
+func LoadMailerConfig() Mailer {
+	v := viper.New()
+	v.SetDefault("MAILER_DRIVER", "file")
+	v.SetDefault("MAIL_FROM", "no-reply@localhost")
+	v.SetDefault("SMTP_HOST", "")
+	v.SetDefault("SMTP_PORT", 587)
+	v.SetDefault("SMTP_USERNAME", "")
+	v.SetDefault("SMTP_PASSWORD", "")
+	v.SetDefault("MAILER_FILE_DIR", "./mail")
+	v.AutomaticEnv()
+
+	var cfg Mailer
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal Mailer config: %v", err)
+	}
+
+	return cfg
+}
+
+type PasswordReset struct {
+	URL      string        `mapstructure:"PASSWORD_RESET_URL"`
+	TokenTTL time.Duration `mapstructure:"PASSWORD_RESET_TOKEN_TTL"`
+}
+
+func LoadPasswordResetConfig() PasswordReset {
+	v := viper.New()
+	v.SetDefault("PASSWORD_RESET_URL", "http://localhost:3000/reset-password")
+	v.SetDefault("PASSWORD_RESET_TOKEN_TTL", time.Hour)
+	v.AutomaticEnv()
+
+	var cfg PasswordReset
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal PasswordReset config: %v", err)
+	}
+
+	return cfg
+}
+
+type EmailVerification struct {
+	URL      string        `mapstructure:"EMAIL_VERIFICATION_URL"`
+	TokenTTL time.Duration `mapstructure:"EMAIL_VERIFICATION_TOKEN_TTL"`
+	// Пользователи с неподтверждённым email могут читать, но не создавать статьи
+	Required bool `mapstructure:"REQUIRE_VERIFIED_EMAIL"`
+}
+
+func LoadEmailVerificationConfig() EmailVerification {
+	v := viper.New()
+	v.SetDefault("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email")
+	v.SetDefault("EMAIL_VERIFICATION_TOKEN_TTL", 24*time.Hour)
+	v.SetDefault("REQUIRE_VERIFIED_EMAIL", false)
+	v.AutomaticEnv()
+
+	var cfg EmailVerification
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal EmailVerification config: %v", err)
+	}
+
+	return cfg
+}
+
+type TwoFactor struct {
+	// Название сервиса в приложении-аутентификаторе
+	Issuer string `mapstructure:"TOTP_ISSUER"`
+}
+
+func LoadTwoFactorConfig() TwoFactor {
+	v := viper.New()
+	v.SetDefault("TOTP_ISSUER", "my_blog")
+	v.AutomaticEnv()
+
+	var cfg TwoFactor
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal TwoFactor config: %v", err)
+	}
+
+	return cfg
+}
+
+type JWT struct {
+	// Каталог с ключами <kid>.pem. Если не задан, токены подписываются HS256 на SECRET
+	KeysDir      string `mapstructure:"JWT_KEYS_DIR"`
+	SigningKeyID string `mapstructure:"JWT_SIGNING_KEY_ID"`
+	// Ключ HMAC для хэшей refresh токенов. Его смена завершает все сессии
+	// и делает недействительными ссылки из писем и коды восстановления
+	RefreshTokenSecret string `mapstructure:"REFRESH_TOKEN_SECRET"`
+}
+
+func LoadJWTConfig() JWT {
+	v := viper.New()
+	v.SetDefault("JWT_KEYS_DIR", "")
+	v.SetDefault("JWT_SIGNING_KEY_ID", "")
+	v.SetDefault("REFRESH_TOKEN_SECRET", "")
+	v.AutomaticEnv()
+
+	var cfg JWT
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal JWT config: %v", err)
+	}
+
+	return cfg
+}
+
+type LoginThrottle struct {
+	// postgres или memory. memory подходит только для одного экземпляра сервиса
+	Store               string        `mapstructure:"LOGIN_ATTEMPTS_STORE"`
+	MaxAttemptsPerEmail int           `mapstructure:"LOGIN_MAX_ATTEMPTS_PER_EMAIL"`
+	MaxAttemptsPerIP    int           `mapstructure:"LOGIN_MAX_ATTEMPTS_PER_IP"`
+	BaseDelay           time.Duration `mapstructure:"LOGIN_LOCKOUT_BASE_DELAY"`
+	MaxDelay            time.Duration `mapstructure:"LOGIN_LOCKOUT_MAX_DELAY"`
+	// Через сколько после последней ошибки счётчик обнуляется
+	Window time.Duration `mapstructure:"LOGIN_ATTEMPTS_WINDOW"`
+}
+
+func LoadLoginThrottleConfig() LoginThrottle {
+	v := viper.New()
+	v.SetDefault("LOGIN_ATTEMPTS_STORE", "postgres")
+	v.SetDefault("LOGIN_MAX_ATTEMPTS_PER_EMAIL", 5)
+	v.SetDefault("LOGIN_MAX_ATTEMPTS_PER_IP", 20)
+	v.SetDefault("LOGIN_LOCKOUT_BASE_DELAY", 30*time.Second)
+	v.SetDefault("LOGIN_LOCKOUT_MAX_DELAY", 15*time.Minute)
+	v.SetDefault("LOGIN_ATTEMPTS_WINDOW", time.Hour)
+	v.AutomaticEnv()
+
+	var cfg LoginThrottle
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal LoginThrottle config: %v", err)
+	}
+
+	return cfg
+}
+
+type AuthCookie struct {
+	// Токены в HttpOnly куках вместо тела ответа, изменяющие запросы требуют CSRF токен
+	Enabled bool   `mapstructure:"AUTH_COOKIE_MODE"`
+	Domain  string `mapstructure:"AUTH_COOKIE_DOMAIN"`
+	Secure  bool   `mapstructure:"AUTH_COOKIE_SECURE"`
+	// lax, strict или none
+	SameSite string `mapstructure:"AUTH_COOKIE_SAMESITE"`
+}
+
+func LoadAuthCookieConfig() AuthCookie {
+	v := viper.New()
+	v.SetDefault("AUTH_COOKIE_MODE", false)
+	v.SetDefault("AUTH_COOKIE_DOMAIN", "")
+	v.SetDefault("AUTH_COOKIE_SECURE", true)
+	v.SetDefault("AUTH_COOKIE_SAMESITE", "lax")
+	v.AutomaticEnv()
+
+	var cfg AuthCookie
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal AuthCookie config: %v", err)
+	}
+
+	return cfg
+}
+
+type PasswordHash struct {
+	// Параметры Argon2id. При их смене хэши обновляются при следующем входе
+	Argon2Memory      uint32 `mapstructure:"ARGON2_MEMORY_KIB"`
+	Argon2Iterations  uint32 `mapstructure:"ARGON2_ITERATIONS"`
+	Argon2Parallelism uint8  `mapstructure:"ARGON2_PARALLELISM"`
+	// Стоимость bcrypt нужна только для проверки старых хэшей
+	BcryptCost int `mapstructure:"BCRYPT_COST"`
+}
+
+func LoadPasswordHashConfig() PasswordHash {
+	v := viper.New()
+	v.SetDefault("ARGON2_MEMORY_KIB", 64*1024)
+	v.SetDefault("ARGON2_ITERATIONS", 3)
+	v.SetDefault("ARGON2_PARALLELISM", 4)
+	v.SetDefault("BCRYPT_COST", 10)
+	v.AutomaticEnv()
+
+	var cfg PasswordHash
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal PasswordHash config: %v", err)
+	}
+
+	return cfg
+}
+
+type PasswordPolicy struct {
+	MinLength int `mapstructure:"PASSWORD_MIN_LENGTH"`
+	MaxLength int `mapstructure:"PASSWORD_MAX_LENGTH"`
+	// Оценка стойкости по шкале zxcvbn от 0 до 4
+	MinScore int `mapstructure:"PASSWORD_MIN_SCORE"`
+	// Каталог диапазонов Have I Been Pwned (<PREFIX>.txt). Если не задан,
+	// используется встроенный список самых частых паролей
+	BreachedPasswordsDir string `mapstructure:"BREACHED_PASSWORDS_DIR"`
+}
+
+func LoadPasswordPolicyConfig() PasswordPolicy {
+	v := viper.New()
+	v.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	v.SetDefault("PASSWORD_MAX_LENGTH", 128)
+	v.SetDefault("PASSWORD_MIN_SCORE", 3)
+	v.SetDefault("BREACHED_PASSWORDS_DIR", "")
+	v.AutomaticEnv()
+
+	var cfg PasswordPolicy
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal PasswordPolicy config: %v", err)
+	}
+	// Оценка стойкости дорожает с длиной пароля, поэтому верхняя граница обязательна.
+	// Запросы с паролем длиннее 1024 символов отклоняются ещё при разборе
+	if cfg.MaxLength <= 0 || cfg.MaxLength > 1024 {
+		log.Fatalf("PASSWORD_MAX_LENGTH must be between 1 and 1024, got %d", cfg.MaxLength)
+	}
+
+	return cfg
+}
+
+type AccountDeletion struct {
+	// Срок, в течение которого удаление аккаунта можно отменить
+	GracePeriod   time.Duration `mapstructure:"ACCOUNT_DELETION_GRACE_PERIOD"`
+	PurgeInterval time.Duration `mapstructure:"ACCOUNT_PURGE_INTERVAL"`
+	BatchSize     int           `mapstructure:"ACCOUNT_PURGE_BATCH_SIZE"`
+}
+
+func LoadAccountDeletionConfig() AccountDeletion {
+	v := viper.New()
+	v.SetDefault("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour)
+	v.SetDefault("ACCOUNT_PURGE_INTERVAL", time.Hour)
+	v.SetDefault("ACCOUNT_PURGE_BATCH_SIZE", 50)
+	v.AutomaticEnv()
+
+	var cfg AccountDeletion
+	if err := v.Unmarshal(&cfg); err != nil {
+		log.Fatalf("failed to unmarshal AccountDeletion config: %v", err)
+	}
+
+	if cfg.PurgeInterval <= 0 {
+		log.Fatalf("ACCOUNT_PURGE_INTERVAL must be positive, got %s", cfg.PurgeInterval)
+	}
+	if cfg.BatchSize <= 0 {
+		log.Fatalf("ACCOUNT_PURGE_BATCH_SIZE must be positive, got %d", cfg.BatchSize)
+	}
+
+	return cfg
+}
@@ -9,19 +9,61 @@ import (
 type CreateUserRequest struct {
 	Username string `json:"username" binding:"required,min=5,max=32,nospaces"`
 	Email    string `json:"email" binding:"required,email,min=3,max=320,nospaces"`
-	Password string `json:"password" binding:"required,min=8,max=128,nospaces"`
+	Password string `json:"password" binding:"required,max=1024"`
 }
 
 type UserRes struct {
-	Id       uint        `json:"id"`
-	Username string      `json:"username"`
-	Email    string      `json:"email"`
-	Role     domain.Role `json:"role"`
+	Id               uint        `json:"id"`
+	Username         string      `json:"username"`
+	Email            string      `json:"email"`
+	EmailVerified    bool        `json:"email_verified"`
+	TwoFactorEnabled bool        `json:"two_factor_enabled"`
+	Role             domain.Role `json:"role"`
+	// Задан, если пользователь запросил удаление аккаунта
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	Profile             ProfileRes `json:"profile"`
+}
+
+type ProfileRes struct {
+	DisplayName string           `json:"display_name"`
+	Bio         string           `json:"bio"`
+	AvatarURL   string           `json:"avatar_url"`
+	Website     string           `json:"website"`
+	Location    string           `json:"location"`
+	Links       []ProfileLinkRes `json:"links"`
+}
+
+type ProfileLinkRes struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// PublicUserRes отдаётся любому посетителю, поэтому в нём нет email, роли и настроек аккаунта
+type PublicUserRes struct {
+	Id       uint         `json:"id"`
+	Username string       `json:"username"`
+	Profile  ProfileRes   `json:"profile"`
+	JoinedAt time.Time    `json:"joined_at"`
+	Stats    UserStatsRes `json:"stats"`
+}
+
+type UserStatsRes struct {
+	ArticleCount     int          `json:"article_count"`
+	FirstPublishedAt *time.Time   `json:"first_published_at"`
+	TopCategory      *CategoryRes `json:"top_category"`
+}
+
+// AuthorRes - автор статьи в публичных ответах
+type AuthorRes struct {
+	Id          uint   `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email,min=3,max=320,nospaces"`
-	Password string `json:"password" binding:"required,min=8,max=128,nospaces"`
+	Password string `json:"password" binding:"required,max=1024"`
 }
 
 type ErrResponse struct {
@@ -31,28 +73,100 @@ type ErrResponse struct {
 
 type LoginUserRes struct {
 	SessionID             string    `json:"session_id"`
-	AccessToken           string    `json:"access_token"`
-	RefreshToken          string    `json:"refresh_token"`
+	AccessToken           string    `json:"access_token,omitempty"`
+	RefreshToken          string    `json:"refresh_token,omitempty"`
 	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
 	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 	User                  UserRes   `json:"user"`
 }
 
+type TwoFactorChallengeRes struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type VerifyTwoFactorReq struct {
+	ChallengeToken string `json:"challenge_token" binding:"required,max=2048"`
+	// Код из приложения или код восстановления
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+type TwoFactorEnrollmentRes struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+type DisableTwoFactorReq struct {
+	Password string `json:"password" binding:"required,max=1024"`
+	Code     string `json:"code" binding:"required,max=32"`
+}
+
+type DeleteAccountReq struct {
+	Password string `json:"password" binding:"required,max=1024"`
+	// Что сделать со статьями: передать служебному пользователю или удалить
+	Articles string `json:"articles" binding:"required,oneof=anonymize delete"`
+}
+
+type RecoveryCodesRes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSRes struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
 type UpdateUserReq struct {
 	Username *string `json:"username" binding:"omitempty,min=5,max=32,nospaces"`
 	Email    *string `json:"email" binding:"omitempty,email,min=3,max=32,nospaces"`
+	// Пустая строка очищает поле, ссылки заменяются целиком
+	DisplayName *string           `json:"display_name" binding:"omitempty,max=64"`
+	Bio         *string           `json:"bio" binding:"omitempty,max=1000"`
+	AvatarURL   *string           `json:"avatar_url" binding:"omitempty,max=2048"`
+	Website     *string           `json:"website" binding:"omitempty,max=2048"`
+	Location    *string           `json:"location" binding:"omitempty,max=64"`
+	Links       *[]ProfileLinkReq `json:"links" binding:"omitempty,max=5,dive"`
+}
+
+type ProfileLinkReq struct {
+	Label string `json:"label" binding:"required,max=32"`
+	URL   string `json:"url" binding:"required,max=2048"`
 }
 
 type ChangePasswordReq struct {
-	OldPassword string `json:"old_password" binding:"required,min=8,max=128,nospaces"`
-	NewPassword string `json:"new_password" binding:"required,min=8,max=128,nospaces"`
+	OldPassword string `json:"old_password" binding:"required,max=1024"`
+	NewPassword string `json:"new_password" binding:"required,max=1024"`
 }
 
-type RefreshTokenReq struct {
-	RefreshToken string `json:"refresh_token"`
+type VerifyEmailReq struct {
+	Token string `json:"token" binding:"required,max=128"`
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" binding:"required,email,min=3,max=320,nospaces"`
 }
 
-type LogoutUserReq struct {
+type ResetPasswordReq struct {
+	Token       string `json:"token" binding:"required,max=128"`
+	NewPassword string `json:"new_password" binding:"required,max=1024"`
+}
+
+type RefreshTokenReq struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
@@ -67,23 +181,57 @@ type UpdateCategoryReq struct {
 }
 
 type CreateArticleReq struct {
-	Title        string `json:"title" binding:"required,min=3,max=100"`
-	Content      string `json:"content" binding:"required,min=10,max=16000"`
-	CategorySlug string `json:"category_slug" binding:"required,min=3,max=128,nospaces"`
+	Title        string   `json:"title" binding:"required,min=3,max=100"`
+	Content      string   `json:"content" binding:"required,min=10,max=16000"`
+	CategorySlug string   `json:"category_slug" binding:"required,min=3,max=128,nospaces"`
+	Tags         []string `json:"tags" binding:"omitempty,max=10,dive,min=1,max=64"`
+	Publish      bool     `json:"publish"`
 }
 
 type CreateArticleRes struct {
-	ArticleId    uint   `json:"article_id"`
-	Title        string `json:"title"`
-	Content      string `json:"content"`
-	CategoryName string `json:"category_name"`
-	CategorySlug string `json:"category_slug"`
+	ArticleId    uint                 `json:"article_id"`
+	Title        string               `json:"title"`
+	Slug         string               `json:"slug"`
+	Content      string               `json:"content"`
+	ContentHTML  string               `json:"content_html"`
+	Status       domain.ArticleStatus `json:"status"`
+	PublishedAt  *time.Time           `json:"published_at"`
+	CategoryName string               `json:"category_name"`
+	CategorySlug string               `json:"category_slug"`
+	Tags         []TagRes             `json:"tags"`
+}
+
+type TagRes struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+func ToTagsRes(res []usecase.TagRes) []TagRes {
+	tags := make([]TagRes, len(res))
+	for i, tag := range res {
+		tags[i] = TagRes{
+			Name: tag.Name,
+			Slug: tag.Slug,
+		}
+	}
+
+	return tags
 }
 
 type UpdateArticleReq struct {
-	Title        *string `json:"title" binding:"omitempty,min=3,max=100"`
-	Content      *string `json:"content" binding:"omitempty,min=10,max=16000"`
-	CategorySlug *string `json:"category_slug" binding:"omitempty,min=3,max=128,nospaces"`
+	Title        *string    `json:"title" binding:"omitempty,min=3,max=100"`
+	Content      *string    `json:"content" binding:"omitempty,min=10,max=16000"`
+	CategorySlug *string    `json:"category_slug" binding:"omitempty,min=3,max=128,nospaces"`
+	Tags         *[]string  `json:"tags" binding:"omitempty,max=10,dive,min=1,max=64"`
+	PublishAt    *time.Time `json:"publish_at"`
+	Unschedule   bool       `json:"unschedule"`
+}
+
+func ToChangeArticleStatusReq(userId, articleId uint) *usecase.ChangeArticleStatusReq {
+	return &usecase.ChangeArticleStatusReq{
+		UserId:    userId,
+		ArticleId: articleId,
+	}
 }
 
 func ToDeleteArticleReq(userId, articleId uint) *usecase.DeleteArticleReq {
@@ -94,30 +242,157 @@ func ToDeleteArticleReq(userId, articleId uint) *usecase.DeleteArticleReq {
 }
 
 type ArticleRes struct {
-	ArticleId uint
-	Title     string
-	Content   string
-	Author    UserRes
-	Category  CategoryRes
+	ArticleId   uint
+	Title       string
+	Slug        string
+	Content     string
+	ContentHTML string `json:"content_html"`
+	Status      domain.ArticleStatus
+	PublishedAt *time.Time
+	PublishAt   *time.Time
+	Author      AuthorRes
+	Category    CategoryRes
+	Tags        []TagRes
 }
 
 type GetArticlesByUserRes struct {
-	Articles []*ArticleRes `json:"articles"`
+	Articles   []*ArticleRes `json:"articles"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+type ListArticlesQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor" binding:"omitempty,max=128"`
+}
+
+type ListFollowsQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor" binding:"omitempty,max=128"`
+}
+
+type FollowRes struct {
+	User       AuthorRes `json:"user"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type GetFollowsRes struct {
+	Follows    []*FollowRes `json:"follows"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+type SubscriptionsRes struct {
+	Categories []*CategoryRes `json:"categories"`
+}
+
+type SearchArticlesQuery struct {
+	Query    string `form:"q" binding:"required,min=2,max=256"`
+	Category string `form:"category" binding:"omitempty,max=128"`
+	Author   string `form:"author" binding:"omitempty,max=32"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor   string `form:"cursor" binding:"omitempty,max=128"`
+}
+
+type ArticleSearchRes struct {
+	Article ArticleRes `json:"article"`
+	Rank    float32    `json:"rank"`
+	Snippet string     `json:"snippet"`
+}
+
+type SearchArticlesRes struct {
+	Results    []*ArticleSearchRes `json:"results"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+func ToSearchArticlesReq(query *SearchArticlesQuery) *usecase.SearchArticlesReq {
+	return &usecase.SearchArticlesReq{
+		Query:          query.Query,
+		CategorySlug:   query.Category,
+		AuthorUsername: query.Author,
+		Limit:          query.Limit,
+		Cursor:         query.Cursor,
+	}
+}
+
+func ToSearchArticlesRes(res *usecase.SearchArticlesRes) *SearchArticlesRes {
+	results := make([]*ArticleSearchRes, len(res.Results))
+	for i, result := range res.Results {
+		results[i] = &ArticleSearchRes{
+			Article: *ToArticleRes(&result.Article),
+			Rank:    result.Rank,
+			Snippet: result.Snippet,
+		}
+	}
+
+	return &SearchArticlesRes{
+		Results:    results,
+		NextCursor: res.NextCursor,
+	}
+}
+
+func ToListFollowsReq(username string, query *ListFollowsQuery) *usecase.ListFollowsReq {
+	return &usecase.ListFollowsReq{
+		Username: username,
+		Limit:    query.Limit,
+		Cursor:   query.Cursor,
+	}
+}
+
+func ToGetFollowsRes(res *usecase.GetFollowsRes) *GetFollowsRes {
+	follows := make([]*FollowRes, len(res.Follows))
+	for i, follow := range res.Follows {
+		follows[i] = &FollowRes{
+			User:       *ToAuthorRes(&follow.User),
+			FollowedAt: follow.FollowedAt,
+		}
+	}
+
+	return &GetFollowsRes{
+		Follows:    follows,
+		NextCursor: res.NextCursor,
+	}
+}
+
+func ToSubscriptionsRes(res []*usecase.CategoryRes) *SubscriptionsRes {
+	categories := make([]*CategoryRes, len(res))
+	for i, category := range res {
+		categories[i] = ToCategoryRes(category)
+	}
+
+	return &SubscriptionsRes{Categories: categories}
+}
+
+func ToListArticlesReq(query *ListArticlesQuery) *usecase.ListArticlesReq {
+	return &usecase.ListArticlesReq{
+		Limit:  query.Limit,
+		Cursor: query.Cursor,
+	}
 }
 
 func ToArticleRes(res *usecase.ArticleRes) *ArticleRes {
 	return &ArticleRes{
-		ArticleId: res.ArticleId,
-		Title:     res.Title,
-		Content:   res.Content,
-		Author:    *ToUserRes(&res.Author),
-		Category:  *ToCategoryRes(&res.Category),
+		ArticleId:   res.ArticleId,
+		Title:       res.Title,
+		Slug:        res.Slug,
+		Content:     res.Content,
+		ContentHTML: res.ContentHTML,
+		Status:      res.Status,
+		PublishedAt: res.PublishedAt,
+		PublishAt:   res.PublishAt,
+		Author:      *ToAuthorRes(&res.Author),
+		Category:    *ToCategoryRes(&res.Category),
+		Tags:        ToTagsRes(res.Tags),
 	}
 }
 
-func ToGetArticlesByUserRes(articles []*ArticleRes) *GetArticlesByUserRes {
+func ToGetArticlesByUserRes(res *usecase.GetArticles) *GetArticlesByUserRes {
+	articles := make([]*ArticleRes, len(res.Articles))
+	for i, article := range res.Articles {
+		articles[i] = ToArticleRes(article)
+	}
+
 	return &GetArticlesByUserRes{
-		Articles: articles,
+		Articles:   articles,
+		NextCursor: res.NextCursor,
 	}
 }
 
@@ -128,16 +403,24 @@ func ToUpdateArticleReq(req *UpdateArticleReq, userId, articleId uint) *usecase.
 		Title:        req.Title,
 		Content:      req.Content,
 		CategorySlug: req.CategorySlug,
+		Tags:         req.Tags,
+		PublishAt:    req.PublishAt,
+		Unschedule:   req.Unschedule,
 	}
 }
 
 type UpdateArticleRes struct {
-	AuthorID  uint        `json:"author_id"`
-	ArticleId uint        `json:"article_id"`
-	Title     string      `json:"title"`
-	Content   string      `json:"content"`
-	Category  CategoryRes `json:"category"`
-	UpdatedAt time.Time   `json:"updated_at"`
+	AuthorID    uint                 `json:"author_id"`
+	ArticleId   uint                 `json:"article_id"`
+	Title       string               `json:"title"`
+	Slug        string               `json:"slug"`
+	Content     string               `json:"content"`
+	ContentHTML string               `json:"content_html"`
+	Status      domain.ArticleStatus `json:"status"`
+	PublishAt   *time.Time           `json:"publish_at"`
+	Category    CategoryRes          `json:"category"`
+	Tags        []TagRes             `json:"tags"`
+	UpdatedAt   time.Time            `json:"updated_at"`
 }
 
 type CategoryRes struct {
@@ -148,12 +431,17 @@ type CategoryRes struct {
 
 func ToUpdateArticleRes(res *usecase.UpdateArticleRes) *UpdateArticleRes {
 	return &UpdateArticleRes{
-		AuthorID:  res.AuthorID,
-		ArticleId: res.ArticleId,
-		Title:     res.Title,
-		Content:   res.Content,
-		Category:  *ToCategoryRes(&res.Category),
-		UpdatedAt: res.UpdatedAt,
+		AuthorID:    res.AuthorID,
+		ArticleId:   res.ArticleId,
+		Title:       res.Title,
+		Slug:        res.Slug,
+		Content:     res.Content,
+		ContentHTML: res.ContentHTML,
+		Status:      res.Status,
+		PublishAt:   res.PublishAt,
+		Category:    *ToCategoryRes(&res.Category),
+		Tags:        ToTagsRes(res.Tags),
+		UpdatedAt:   res.UpdatedAt,
 	}
 }
 
@@ -173,10 +461,25 @@ func ToChangePasswordReq(req *ChangePasswordReq) *usecase.ChangePasswordReq {
 }
 
 func ToUpdateUserReq(req *UpdateUserReq) *usecase.UpdateUserReq {
-	return &usecase.UpdateUserReq{
-		Username: req.Username,
-		Email:    req.Email,
+	res := &usecase.UpdateUserReq{
+		Username:    req.Username,
+		Email:       req.Email,
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		AvatarURL:   req.AvatarURL,
+		Website:     req.Website,
+		Location:    req.Location,
 	}
+
+	if req.Links != nil {
+		links := make([]domain.ProfileLink, len(*req.Links))
+		for i, link := range *req.Links {
+			links[i] = domain.ProfileLink{Label: link.Label, URL: link.URL}
+		}
+		res.Links = &links
+	}
+
+	return res
 }
 
 func ToLoginUserRes(res *usecase.LoginUserRes) *LoginUserRes {
@@ -200,38 +503,154 @@ func ToServiceCreateUserReq(request *CreateUserRequest) *usecase.CreateUserReq {
 
 func ToUserRes(res *usecase.UserRes) *UserRes {
 	return &UserRes{
+		Id:                  res.Id,
+		Username:            res.Username,
+		Email:               res.Email,
+		EmailVerified:       res.EmailVerified,
+		TwoFactorEnabled:    res.TwoFactorEnabled,
+		Role:                res.Role,
+		DeletionScheduledAt: res.DeletionScheduledAt,
+		Profile:             ToProfileRes(&res.Profile),
+	}
+}
+
+func ToProfileRes(profile *domain.Profile) ProfileRes {
+	links := make([]ProfileLinkRes, len(profile.Links))
+	for i, link := range profile.Links {
+		links[i] = ProfileLinkRes{Label: link.Label, URL: link.URL}
+	}
+
+	return ProfileRes{
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Bio,
+		AvatarURL:   profile.AvatarURL,
+		Website:     profile.Website,
+		Location:    profile.Location,
+		Links:       links,
+	}
+}
+
+func ToPublicUserRes(res *usecase.PublicProfileRes) *PublicUserRes {
+	stats := UserStatsRes{
+		ArticleCount:     res.Stats.ArticleCount,
+		FirstPublishedAt: res.Stats.FirstPublishedAt,
+	}
+	if res.Stats.TopCategory != nil {
+		stats.TopCategory = ToCategoryRes(res.Stats.TopCategory)
+	}
+
+	return &PublicUserRes{
 		Id:       res.Id,
 		Username: res.Username,
-		Email:    res.Email,
-		Role:     res.Role,
+		Profile:  ToProfileRes(&res.Profile),
+		JoinedAt: res.JoinedAt,
+		Stats:    stats,
+	}
+}
+
+func ToAuthorRes(res *usecase.UserRes) *AuthorRes {
+	return &AuthorRes{
+		Id:          res.Id,
+		Username:    res.Username,
+		DisplayName: res.Profile.DisplayName,
+		AvatarURL:   res.Profile.AvatarURL,
+	}
+}
+
+func ToJWKSRes(keys []usecase.JSONWebKey) *JWKSRes {
+	res := &JWKSRes{Keys: make([]JSONWebKey, len(keys))}
+	for i, key := range keys {
+		res.Keys[i] = JSONWebKey{
+			KeyType:   key.KeyType,
+			KeyID:     key.KeyID,
+			Use:       key.Use,
+			Algorithm: key.Algorithm,
+			N:         key.N,
+			E:         key.E,
+			Curve:     key.Curve,
+			X:         key.X,
+		}
+	}
+
+	return res
+}
+
+func ToTwoFactorChallengeRes(res *usecase.TokenResponse) *TwoFactorChallengeRes {
+	return &TwoFactorChallengeRes{
+		TwoFactorRequired: true,
+		ChallengeToken:    res.Token,
+		ExpiresAt:         res.ExpiresAt,
+	}
+}
+
+func ToVerifyTwoFactorReq(req *VerifyTwoFactorReq, client usecase.ClientInfo) *usecase.VerifyTwoFactorReq {
+	return &usecase.VerifyTwoFactorReq{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		Client:         client,
+	}
+}
+
+func ToTwoFactorEnrollmentRes(res *usecase.TwoFactorEnrollmentRes) *TwoFactorEnrollmentRes {
+	return &TwoFactorEnrollmentRes{
+		Secret:     res.Secret,
+		OtpauthURI: res.URI,
+	}
+}
+
+func ToDisableTwoFactorReq(req *DisableTwoFactorReq) *usecase.DisableTwoFactorReq {
+	return &usecase.DisableTwoFactorReq{
+		Password: req.Password,
+		Code:     req.Code,
+	}
+}
+
+func ToDeleteAccountReq(req *DeleteAccountReq) *usecase.DeleteAccountReq {
+	return &usecase.DeleteAccountReq{
+		Password: req.Password,
+		Articles: req.Articles,
 	}
 }
 
-func ToLoginUserReq(req *LoginRequest) *usecase.LoginUserReq {
+func ToRecoveryCodesRes(res *usecase.RecoveryCodesRes) *RecoveryCodesRes {
+	return &RecoveryCodesRes{
+		RecoveryCodes: res.Codes,
+	}
+}
+
+func ToResetPasswordReq(req *ResetPasswordReq) *usecase.ResetPasswordReq {
+	return &usecase.ResetPasswordReq{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	}
+}
+
+func ToLoginUserReq(req *LoginRequest, client usecase.ClientInfo) *usecase.LoginUserReq {
 	return &usecase.LoginUserReq{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   client,
 	}
 }
 
-func ToCreateCategoryReq(req *CreateCategoryReq, userRole domain.Role) *usecase.CreateCategoryReq {
+func ToCreateCategoryReq(req *CreateCategoryReq, userId uint) *usecase.CreateCategoryReq {
 	return &usecase.CreateCategoryReq{
 		CategoryName: req.CategoryName,
 		CategorySlug: req.CategorySlug,
-		UserRole:     userRole,
+		UserId:       userId,
 	}
 }
 
-func ToDeleteCategoryReq(categorySlug string, userRole domain.Role) *usecase.DeleteCategoryReq {
+func ToDeleteCategoryReq(categorySlug string, userId uint) *usecase.DeleteCategoryReq {
 	return &usecase.DeleteCategoryReq{
-		UserRole:     userRole,
+		UserId:       userId,
 		CategorySlug: categorySlug,
 	}
 }
 
-func ToUpdateCategoryReq(req UpdateCategoryReq, userRole domain.Role, categorySlug string) *usecase.UpdateCategoryReq {
+func ToUpdateCategoryReq(req UpdateCategoryReq, userId uint, categorySlug string) *usecase.UpdateCategoryReq {
 	return &usecase.UpdateCategoryReq{
-		UserRole:        userRole,
+		UserId:          userId,
 		CategorySlug:    categorySlug,
 		NewCategoryName: req.NewCategoryName,
 		NewCategorySlug: req.NewCategorySlug,
@@ -244,14 +663,438 @@ func ToCreateArticleReq(req *CreateArticleReq, userId uint) *usecase.CreateArtic
 		Title:        req.Title,
 		Content:      req.Content,
 		CategorySlug: req.CategorySlug,
+		Tags:         req.Tags,
+		Publish:      req.Publish,
 	}
 }
 func ToCreateArticleRes(res *usecase.CreateArticleRes) *CreateArticleRes {
 	return &CreateArticleRes{
 		ArticleId:    res.ArticleId,
 		Title:        res.Title,
+		Slug:         res.Slug,
 		Content:      res.Content,
+		ContentHTML:  res.ContentHTML,
+		Status:       res.Status,
+		PublishedAt:  res.PublishedAt,
 		CategoryName: res.CategoryName,
 		CategorySlug: res.CategorySlug,
+		Tags:         ToTagsRes(res.Tags),
+	}
+}
+
+type CreateCommentReq struct {
+	ParentId *uint  `json:"parent_id" binding:"omitempty,min=1"`
+	Content  string `json:"content" binding:"required,min=1,max=4000"`
+}
+
+type UpdateCommentReq struct {
+	Content string `json:"content" binding:"required,min=1,max=4000"`
+}
+
+type ListCommentsQuery struct {
+	ParentId *uint  `form:"parent_id" binding:"omitempty,min=1"`
+	Depth    int    `form:"depth" binding:"omitempty,min=1,max=10"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor   string `form:"cursor" binding:"omitempty,max=128"`
+}
+
+type CommentAuthorRes struct {
+	Id       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+type CommentRes struct {
+	Id             uint              `json:"id"`
+	ArticleId      uint              `json:"article_id"`
+	ParentId       *uint             `json:"parent_id"`
+	Author         *CommentAuthorRes `json:"author"`
+	Content        string            `json:"content"`
+	IsDeleted      bool              `json:"is_deleted"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	Replies        []*CommentRes     `json:"replies"`
+	HasMoreReplies bool              `json:"has_more_replies"`
+}
+
+type GetCommentsRes struct {
+	Comments   []*CommentRes `json:"comments"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+func ToCreateCommentReq(req *CreateCommentReq, userId, articleId uint) *usecase.CreateCommentReq {
+	return &usecase.CreateCommentReq{
+		UserId:    userId,
+		ArticleId: articleId,
+		ParentId:  req.ParentId,
+		Content:   req.Content,
+	}
+}
+
+func ToUpdateCommentReq(req *UpdateCommentReq, userId, articleId, commentId uint) *usecase.UpdateCommentReq {
+	return &usecase.UpdateCommentReq{
+		UserId:    userId,
+		ArticleId: articleId,
+		CommentId: commentId,
+		Content:   req.Content,
+	}
+}
+
+func ToDeleteCommentReq(userId, articleId, commentId uint) *usecase.DeleteCommentReq {
+	return &usecase.DeleteCommentReq{
+		UserId:    userId,
+		ArticleId: articleId,
+		CommentId: commentId,
+	}
+}
+
+func ToListCommentsReq(query *ListCommentsQuery, viewerId, articleId uint) *usecase.ListCommentsReq {
+	return &usecase.ListCommentsReq{
+		ViewerId:  viewerId,
+		ArticleId: articleId,
+		ParentId:  query.ParentId,
+		Depth:     query.Depth,
+		Limit:     query.Limit,
+		Cursor:    query.Cursor,
+	}
+}
+
+func ToCommentRes(res *usecase.CommentRes) *CommentRes {
+	comment := &CommentRes{
+		Id:             res.Id,
+		ArticleId:      res.ArticleId,
+		ParentId:       res.ParentId,
+		Content:        res.Content,
+		IsDeleted:      res.IsDeleted,
+		CreatedAt:      res.CreatedAt,
+		UpdatedAt:      res.UpdatedAt,
+		Replies:        make([]*CommentRes, len(res.Replies)),
+		HasMoreReplies: res.HasMoreReplies,
+	}
+
+	if res.Author != nil {
+		comment.Author = &CommentAuthorRes{
+			Id:       res.Author.Id,
+			Username: res.Author.Username,
+		}
+	}
+
+	for i, reply := range res.Replies {
+		comment.Replies[i] = ToCommentRes(reply)
+	}
+
+	return comment
+}
+
+func ToGetCommentsRes(res *usecase.GetCommentsRes) *GetCommentsRes {
+	comments := make([]*CommentRes, len(res.Comments))
+	for i, comment := range res.Comments {
+		comments[i] = ToCommentRes(comment)
+	}
+
+	return &GetCommentsRes{
+		Comments:   comments,
+		NextCursor: res.NextCursor,
+	}
+}
+
+type DiffRevisionsQuery struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
+type RevisionRes struct {
+	Number     int               `json:"number"`
+	Title      string            `json:"title"`
+	Content    string            `json:"content,omitempty"`
+	CategoryId uint              `json:"category_id"`
+	Editor     *CommentAuthorRes `json:"editor"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+type GetRevisionsRes struct {
+	Revisions []*RevisionRes `json:"revisions"`
+}
+
+type RevisionDiffRes struct {
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Diff string `json:"diff"`
+}
+
+func ToListRevisionsReq(userId, articleId uint) *usecase.ListRevisionsReq {
+	return &usecase.ListRevisionsReq{
+		UserId:    userId,
+		ArticleId: articleId,
+	}
+}
+
+func ToGetRevisionReq(userId, articleId uint, number int) *usecase.GetRevisionReq {
+	return &usecase.GetRevisionReq{
+		UserId:    userId,
+		ArticleId: articleId,
+		Number:    number,
+	}
+}
+
+func ToDiffRevisionsReq(query *DiffRevisionsQuery, userId, articleId uint) *usecase.DiffRevisionsReq {
+	return &usecase.DiffRevisionsReq{
+		UserId:    userId,
+		ArticleId: articleId,
+		From:      query.From,
+		To:        query.To,
+	}
+}
+
+func ToRestoreRevisionReq(userId, articleId uint, number int) *usecase.RestoreRevisionReq {
+	return &usecase.RestoreRevisionReq{
+		UserId:    userId,
+		ArticleId: articleId,
+		Number:    number,
+	}
+}
+
+func ToRevisionRes(res *usecase.RevisionRes) *RevisionRes {
+	revision := &RevisionRes{
+		Number:     res.Number,
+		Title:      res.Title,
+		Content:    res.Content,
+		CategoryId: res.CategoryId,
+		CreatedAt:  res.CreatedAt,
+	}
+
+	if res.Editor != nil {
+		revision.Editor = &CommentAuthorRes{
+			Id:       res.Editor.Id,
+			Username: res.Editor.Username,
+		}
+	}
+
+	return revision
+}
+
+func ToGetRevisionsRes(res []*usecase.RevisionRes) *GetRevisionsRes {
+	revisions := make([]*RevisionRes, len(res))
+	for i, revision := range res {
+		revisions[i] = ToRevisionRes(revision)
+	}
+
+	return &GetRevisionsRes{Revisions: revisions}
+}
+
+func ToRevisionDiffRes(res *usecase.RevisionDiffRes) *RevisionDiffRes {
+	return &RevisionDiffRes{
+		From: res.From,
+		To:   res.To,
+		Diff: res.Diff,
+	}
+}
+
+type SessionRes struct {
+	Id         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	IsCurrent  bool      `json:"is_current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type GetSessionsRes struct {
+	Sessions []*SessionRes `json:"sessions"`
+}
+
+func ToGetSessionsRes(res []*usecase.SessionRes) *GetSessionsRes {
+	sessions := make([]*SessionRes, len(res))
+	for i, session := range res {
+		sessions[i] = &SessionRes{
+			Id:         session.Id.String(),
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			IsCurrent:  session.IsCurrent,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+
+	return &GetSessionsRes{Sessions: sessions}
+}
+
+type CreateAccessTokenReq struct {
+	Name   string   `json:"name" binding:"required,max=64"`
+	Scopes []string `json:"scopes" binding:"required,min=1,max=16,dive,max=64"`
+	// Без срока токен действует 90 дней
+	ExpiresInDays *int `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+type UpdateAccessTokenReq struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+type AccessTokenRes struct {
+	Id         uint           `json:"id"`
+	Name       string         `json:"name"`
+	Scopes     []domain.Scope `json:"scopes"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+}
+
+type CreatedAccessTokenRes struct {
+	// Показывается один раз, после создания восстановить токен нельзя
+	Token string `json:"token"`
+	AccessTokenRes
+}
+
+type GetAccessTokensRes struct {
+	Tokens []*AccessTokenRes `json:"tokens"`
+}
+
+func ToCreateAccessTokenReq(req *CreateAccessTokenReq) *usecase.CreateAccessTokenReq {
+	res := &usecase.CreateAccessTokenReq{
+		Name:   req.Name,
+		Scopes: req.Scopes,
+	}
+
+	if req.ExpiresInDays != nil {
+		expiresIn := time.Duration(*req.ExpiresInDays) * 24 * time.Hour
+		res.ExpiresIn = &expiresIn
+	}
+
+	return res
+}
+
+func ToUpdateAccessTokenReq(req *UpdateAccessTokenReq) *usecase.UpdateAccessTokenReq {
+	return &usecase.UpdateAccessTokenReq{
+		Name: req.Name,
+	}
+}
+
+func ToAccessTokenRes(res *usecase.AccessTokenRes) *AccessTokenRes {
+	return &AccessTokenRes{
+		Id:         res.Id,
+		Name:       res.Name,
+		Scopes:     res.Scopes,
+		CreatedAt:  res.CreatedAt,
+		ExpiresAt:  res.ExpiresAt,
+		LastUsedAt: res.LastUsedAt,
+	}
+}
+
+func ToCreatedAccessTokenRes(res *usecase.CreatedAccessTokenRes) *CreatedAccessTokenRes {
+	return &CreatedAccessTokenRes{
+		Token:          res.Token,
+		AccessTokenRes: *ToAccessTokenRes(&res.AccessToken),
+	}
+}
+
+func ToGetAccessTokensRes(res []*usecase.AccessTokenRes) *GetAccessTokensRes {
+	tokens := make([]*AccessTokenRes, len(res))
+	for i, token := range res {
+		tokens[i] = ToAccessTokenRes(token)
+	}
+
+	return &GetAccessTokensRes{Tokens: tokens}
+}
+
+type ChangeRoleReq struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type RoleRes struct {
+	Role        domain.Role         `json:"role"`
+	Permissions []domain.Permission `json:"permissions"`
+}
+
+type GetRolesRes struct {
+	Roles []*RoleRes `json:"roles"`
+}
+
+func ToChangeRoleReq(req *ChangeRoleReq, actorId, userId uint) *usecase.ChangeRoleReq {
+	return &usecase.ChangeRoleReq{
+		ActorId: actorId,
+		UserId:  userId,
+		Role:    req.Role,
+	}
+}
+
+func ToGetRolesRes(res []*usecase.RoleRes) *GetRolesRes {
+	roles := make([]*RoleRes, len(res))
+	for i, role := range res {
+		roles[i] = &RoleRes{
+			Role:        role.Role,
+			Permissions: role.Permissions,
+		}
+	}
+
+	return &GetRolesRes{Roles: roles}
+}
+
+type ListUsersQuery struct {
+	Role           string     `form:"role" binding:"omitempty,max=32"`
+	UsernamePrefix string     `form:"username" binding:"omitempty,max=32"`
+	CreatedAfter   *time.Time `form:"created_after"`
+	CreatedBefore  *time.Time `form:"created_before"`
+	Suspended      *bool      `form:"suspended"`
+	Limit          int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor         string     `form:"cursor" binding:"omitempty,max=128"`
+}
+
+type DeleteUserQuery struct {
+	// Что делать со статьями пользователя: reassign или purge
+	Articles   string `form:"articles" binding:"omitempty,oneof=reassign purge"`
+	ReassignTo *uint  `form:"reassign_to" binding:"omitempty,min=1"`
+}
+
+type AdminUserRes struct {
+	UserRes
+	CreatedAt   time.Time  `json:"created_at"`
+	SuspendedAt *time.Time `json:"suspended_at"`
+}
+
+type GetUsersRes struct {
+	Users      []*AdminUserRes `json:"users"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func ToListUsersReq(query *ListUsersQuery, actorId uint) *usecase.ListUsersReq {
+	return &usecase.ListUsersReq{
+		ActorId:        actorId,
+		Role:           query.Role,
+		UsernamePrefix: query.UsernamePrefix,
+		CreatedAfter:   query.CreatedAfter,
+		CreatedBefore:  query.CreatedBefore,
+		Suspended:      query.Suspended,
+		Limit:          query.Limit,
+		Cursor:         query.Cursor,
+	}
+}
+
+func ToDeleteUserReq(query *DeleteUserQuery, actorId, userId uint) *usecase.DeleteUserReq {
+	return &usecase.DeleteUserReq{
+		ActorId:    actorId,
+		UserId:     userId,
+		Articles:   query.Articles,
+		ReassignTo: query.ReassignTo,
+	}
+}
+
+func ToAdminUserRes(res *usecase.AdminUserRes) *AdminUserRes {
+	return &AdminUserRes{
+		UserRes:     *ToUserRes(&res.User),
+		CreatedAt:   res.CreatedAt,
+		SuspendedAt: res.SuspendedAt,
+	}
+}
+
+func ToGetUsersRes(res *usecase.GetUsersRes) *GetUsersRes {
+	users := make([]*AdminUserRes, len(res.Users))
+	for i, user := range res.Users {
+		users[i] = ToAdminUserRes(user)
+	}
+
+	return &GetUsersRes{
+		Users:      users,
+		NextCursor: res.NextCursor,
 	}
 }
@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"log"
+	"my_blog_backend/internal/delivery"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) createAccessToken(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	var req delivery.CreateAccessTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := h.services.AccessTokenService.Create(c.Request.Context(), userId.(uint), delivery.ToCreateAccessTokenReq(&req))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, delivery.ToCreatedAccessTokenRes(res))
+}
+
+func (h *Handler) getAccessTokens(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	res, err := h.services.AccessTokenService.List(c.Request.Context(), userId.(uint))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToGetAccessTokensRes(res))
+}
+
+func (h *Handler) getAccessToken(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	tokenId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.AccessTokenService.Get(c.Request.Context(), userId.(uint), uint(tokenId))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToAccessTokenRes(res))
+}
+
+func (h *Handler) updateAccessToken(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	tokenId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	var req delivery.UpdateAccessTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := h.services.AccessTokenService.Update(c.Request.Context(), userId.(uint), uint(tokenId), delivery.ToUpdateAccessTokenReq(&req))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToAccessTokenRes(res))
+}
+
+func (h *Handler) deleteAccessToken(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	tokenId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	if err := h.services.AccessTokenService.Delete(c.Request.Context(), userId.(uint), uint(tokenId)); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
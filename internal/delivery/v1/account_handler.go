@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"fmt"
+	"log"
+	"my_blog_backend/internal/delivery"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportAccount отдаёт ZIP архив со всеми данными пользователя. Архив пишется
+// потоком, поэтому после начала записи ошибку можно только залогировать
+func (h *Handler) exportAccount(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	filename := fmt.Sprintf("account-export-%s.zip", time.Now().UTC().Format("20060102"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if err := h.services.AccountService.Export(c.Request.Context(), userId.(uint), c.Writer); err != nil {
+		if c.Writer.Written() {
+			log.Printf("account export for user %d interrupted: %v", userId.(uint), err)
+			return
+		}
+
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		ErrorToHttpRes(err, c)
+		return
+	}
+}
+
+func (h *Handler) deleteAccount(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	var req delivery.DeleteAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := h.services.AccountService.ScheduleDeletion(c.Request.Context(), userId.(uint), delivery.ToDeleteAccountReq(&req))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery.ToUserRes(res))
+}
+
+func (h *Handler) cancelAccountDeletion(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	res, err := h.services.AccountService.CancelDeletion(c.Request.Context(), userId.(uint))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToUserRes(res))
+}
@@ -0,0 +1,202 @@
+package v1
+
+import (
+	"log"
+	"my_blog_backend/internal/delivery"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) getRoles(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	res, err := h.services.AuthorizationService.ListRoles(c.Request.Context(), userId.(uint))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToGetRolesRes(res))
+}
+
+func (h *Handler) changeUserRole(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	targetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	var req delivery.ChangeRoleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := h.services.AuthorizationService.ChangeRole(c.Request.Context(), delivery.ToChangeRoleReq(&req, userId.(uint), uint(targetId)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToUserRes(res))
+}
+
+func (h *Handler) getUsers(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	var query delivery.ListUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.AdminUserService.List(c.Request.Context(), delivery.ToListUsersReq(&query, userId.(uint)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToGetUsersRes(res))
+}
+
+func (h *Handler) getUser(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	targetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.AdminUserService.Get(c.Request.Context(), userId.(uint), uint(targetId))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToAdminUserRes(res))
+}
+
+func (h *Handler) suspendUser(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	targetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.AdminUserService.Suspend(c.Request.Context(), userId.(uint), uint(targetId))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToAdminUserRes(res))
+}
+
+func (h *Handler) unsuspendUser(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	targetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.AdminUserService.Unsuspend(c.Request.Context(), userId.(uint), uint(targetId))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToAdminUserRes(res))
+}
+
+func (h *Handler) deleteUser(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	targetId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	var query delivery.DeleteUserQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	if err := h.services.AdminUserService.Delete(c.Request.Context(), delivery.ToDeleteUserReq(&query, userId.(uint), uint(targetId))); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, gin.H{})
+}
@@ -1,9 +1,12 @@
 package v1
 
 import (
+	"context"
 	"log"
 	"my_blog_backend/internal/delivery"
+	"my_blog_backend/internal/usecase"
 	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -98,7 +101,54 @@ func (h *Handler) deleteArticle(c *gin.Context) {
 	c.JSON(http.StatusNoContent, gin.H{})
 }
 
+func (h *Handler) publishArticle(c *gin.Context) {
+	h.changeArticleStatus(c, h.services.ArticleService.Publish)
+}
+
+func (h *Handler) unpublishArticle(c *gin.Context) {
+	h.changeArticleStatus(c, h.services.ArticleService.Unpublish)
+}
+
+func (h *Handler) archiveArticle(c *gin.Context) {
+	h.changeArticleStatus(c, h.services.ArticleService.Archive)
+}
+
+func (h *Handler) changeArticleStatus(c *gin.Context, change func(ctx context.Context, req *usecase.ChangeArticleStatusReq) (*usecase.ArticleRes, error)) {
+	strUserId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	strArticleId := c.Param("id")
+	articleId, err := strconv.Atoi(strArticleId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := change(c.Request.Context(), delivery.ToChangeArticleStatusReq(strUserId.(uint), uint(articleId)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToArticleRes(res))
+}
+
 func (h *Handler) getArticlesByUsername(c *gin.Context) {
+	var query delivery.ListArticlesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
 	username := c.Param("username")
 	user, err := h.services.UserService.GetUserByUsername(c.Request.Context(), username)
 	if err != nil {
@@ -106,18 +156,14 @@ func (h *Handler) getArticlesByUsername(c *gin.Context) {
 		return
 	}
 
-	dto, err := h.services.ArticleService.GetAllArticlesByUserId(c.Request.Context(), user.Id)
+	viewerId := c.GetUint("user_id")
+	dto, err := h.services.ArticleService.GetAllArticlesByUserId(c.Request.Context(), user.Id, viewerId, delivery.ToListArticlesReq(&query))
 	if err != nil {
 		ErrorToHttpRes(err, c)
+		return
 	}
 
-	articles := make([]*delivery.ArticleRes, len(dto.Articles))
-	for i, article := range dto.Articles {
-		articles[i] = delivery.ToArticleRes(article)
-	}
-
-	res := delivery.ToGetArticlesByUserRes(articles)
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusOK, delivery.ToGetArticlesByUserRes(dto))
 }
 
 func (h *Handler) getArticlesByUserId(c *gin.Context) {
@@ -132,19 +178,20 @@ func (h *Handler) getArticlesByUserId(c *gin.Context) {
 		return
 	}
 
-	dto, err := h.services.ArticleService.GetAllArticlesByUserId(c.Request.Context(), strUserId.(uint))
-	if err != nil {
-		ErrorToHttpRes(err, c)
+	var query delivery.ListArticlesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
 		return
 	}
 
-	articles := make([]*delivery.ArticleRes, len(dto.Articles))
-	for i, article := range dto.Articles {
-		articles[i] = delivery.ToArticleRes(article)
+	dto, err := h.services.ArticleService.GetAllArticlesByUserId(c.Request.Context(), strUserId.(uint), strUserId.(uint), delivery.ToListArticlesReq(&query))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
 	}
 
-	res := delivery.ToGetArticlesByUserRes(articles)
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusOK, delivery.ToGetArticlesByUserRes(dto))
 }
 
 func (h *Handler) getArticleByID(c *gin.Context) {
@@ -155,7 +202,8 @@ func (h *Handler) getArticleByID(c *gin.Context) {
 		return
 	}
 
-	article, err := h.services.ArticleService.GetById(c.Request.Context(), uint(articleId))
+	viewerId := c.GetUint("user_id")
+	article, err := h.services.ArticleService.GetById(c.Request.Context(), uint(articleId), viewerId)
 	if err != nil {
 		ErrorToHttpRes(err, c)
 		return
@@ -164,35 +212,90 @@ func (h *Handler) getArticleByID(c *gin.Context) {
 	c.JSON(http.StatusOK, delivery.ToArticleRes(article))
 }
 
+func (h *Handler) getArticleBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	viewerId := c.GetUint("user_id")
+	article, err := h.services.ArticleService.GetBySlug(c.Request.Context(), slug, viewerId)
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	// Устаревший slug: отправляем на актуальный адрес
+	if article.Slug != slug {
+		c.Redirect(http.StatusMovedPermanently, path.Join(path.Dir(c.Request.URL.Path), article.Slug))
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToArticleRes(article))
+}
+
 func (h *Handler) getArticlesByCategorySlug(c *gin.Context) {
+	var query delivery.ListArticlesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
 	slug := c.Param("slug")
-	dto, err := h.services.ArticleService.GetAllArticlesByCategory(c.Request.Context(), slug)
+	dto, err := h.services.ArticleService.GetAllArticlesByCategory(c.Request.Context(), slug, delivery.ToListArticlesReq(&query))
 	if err != nil {
 		ErrorToHttpRes(err, c)
 		return
 	}
 
-	articles := make([]*delivery.ArticleRes, len(dto.Articles))
-	for i, article := range dto.Articles {
-		articles[i] = delivery.ToArticleRes(article)
+	c.JSON(http.StatusOK, delivery.ToGetArticlesByUserRes(dto))
+}
+
+func (h *Handler) getArticlesByTagSlug(c *gin.Context) {
+	var query delivery.ListArticlesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	slug := c.Param("slug")
+	dto, err := h.services.ArticleService.GetAllArticlesByTag(c.Request.Context(), slug, delivery.ToListArticlesReq(&query))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
 	}
 
-	res := delivery.ToGetArticlesByUserRes(articles)
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusOK, delivery.ToGetArticlesByUserRes(dto))
 }
 
 func (h *Handler) getAllArticles(c *gin.Context) {
-	dto, err := h.services.ArticleService.GetAll(c.Request.Context())
+	var query delivery.ListArticlesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	dto, err := h.services.ArticleService.GetAll(c.Request.Context(), delivery.ToListArticlesReq(&query))
 	if err != nil {
 		ErrorToHttpRes(err, c)
 		return
 	}
 
-	articles := make([]*delivery.ArticleRes, len(dto.Articles))
-	for i, article := range dto.Articles {
-		articles[i] = delivery.ToArticleRes(article)
+	c.JSON(http.StatusOK, delivery.ToGetArticlesByUserRes(dto))
+}
+
+func (h *Handler) searchArticles(c *gin.Context) {
+	var query delivery.SearchArticlesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.ArticleService.Search(c.Request.Context(), delivery.ToSearchArticlesReq(&query))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
 	}
 
-	res := delivery.ToGetArticlesByUserRes(articles)
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusOK, delivery.ToSearchArticlesRes(res))
 }
@@ -19,12 +19,6 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	user, err := h.services.UserService.GetUserById(c.Request.Context(), userId.(uint))
-	if err != nil {
-		ErrorToHttpRes(err, c)
-		return
-	}
-
 	var req delivery.CreateCategoryReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Println(err)
@@ -32,7 +26,7 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	newCategory, err := h.services.CategoryService.Create(c.Request.Context(), delivery.ToCreateCategoryReq(&req, user.Role))
+	newCategory, err := h.services.CategoryService.Create(c.Request.Context(), delivery.ToCreateCategoryReq(&req, userId.(uint)))
 	if err != nil {
 		ErrorToHttpRes(err, c)
 		return
@@ -52,14 +46,8 @@ func (h *Handler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	user, err := h.services.UserService.GetUserById(c.Request.Context(), userId.(uint))
-	if err != nil {
-		ErrorToHttpRes(err, c)
-		return
-	}
-
 	categorySlug := c.Param("slug")
-	if err := h.services.CategoryService.Delete(c.Request.Context(), delivery.ToDeleteCategoryReq(categorySlug, user.Role)); err != nil {
+	if err := h.services.CategoryService.Delete(c.Request.Context(), delivery.ToDeleteCategoryReq(categorySlug, userId.(uint))); err != nil {
 		ErrorToHttpRes(err, c)
 		return
 	}
@@ -78,12 +66,6 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	user, err := h.services.UserService.GetUserById(c.Request.Context(), userId.(uint))
-	if err != nil {
-		ErrorToHttpRes(err, c)
-		return
-	}
-
 	var req delivery.UpdateCategoryReq
 	categorySlug := c.Param("slug")
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -92,7 +74,7 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	category, err := h.services.CategoryService.Update(c.Request.Context(), delivery.ToUpdateCategoryReq(req, user.Role, categorySlug))
+	category, err := h.services.CategoryService.Update(c.Request.Context(), delivery.ToUpdateCategoryReq(req, userId.(uint), categorySlug))
 	if err != nil {
 		ErrorToHttpRes(err, c)
 		return
@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"log"
+	"my_blog_backend/internal/delivery"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) createComment(c *gin.Context) {
+	strUserId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	articleId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	var req delivery.CreateCommentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.CommentService.Create(c.Request.Context(), delivery.ToCreateCommentReq(&req, strUserId.(uint), uint(articleId)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, delivery.ToCommentRes(res))
+}
+
+func (h *Handler) getComments(c *gin.Context) {
+	articleId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	var query delivery.ListCommentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	viewerId := c.GetUint("user_id")
+	res, err := h.services.CommentService.List(c.Request.Context(), delivery.ToListCommentsReq(&query, viewerId, uint(articleId)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToGetCommentsRes(res))
+}
+
+func (h *Handler) updateComment(c *gin.Context) {
+	strUserId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	articleId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	commentId, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	var req delivery.UpdateCommentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.CommentService.Update(c.Request.Context(), delivery.ToUpdateCommentReq(&req, strUserId.(uint), uint(articleId), uint(commentId)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToCommentRes(res))
+}
+
+func (h *Handler) deleteComment(c *gin.Context) {
+	strUserId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	articleId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	commentId, err := strconv.Atoi(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	if err := h.services.CommentService.Delete(c.Request.Context(), delivery.ToDeleteCommentReq(strUserId.(uint), uint(articleId), uint(commentId))); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, gin.H{})
+}
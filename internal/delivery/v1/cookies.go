@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"my_blog_backend/internal/usecase"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	accessTokenCookie  = "access_token"
+	refreshTokenCookie = "refresh_token"
+	csrfTokenCookie    = "csrf_token"
+	csrfTokenHeader    = "X-CSRF-Token"
+
+	// Refresh токен нужен только ручкам refresh и logout
+	refreshTokenCookiePath = "/v1/auth"
+)
+
+// CookieConfig - режим, в котором токены хранятся в HttpOnly куках, а не в теле ответа
+type CookieConfig struct {
+	Enabled  bool
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// AuthCookies выставляет и читает куки сессии. CSRF защищён double-submit
+// токеном: кука csrf_token доступна SPA, и её значение нужно повторить в
+// заголовке X-CSRF-Token. Чужой сайт прочитать куку не может
+type AuthCookies struct {
+	cfg CookieConfig
+}
+
+func NewAuthCookies(cfg CookieConfig) *AuthCookies {
+	return &AuthCookies{cfg: cfg}
+}
+
+func (a *AuthCookies) Enabled() bool {
+	return a.cfg.Enabled
+}
+
+func (a *AuthCookies) SetSession(c *gin.Context, res *usecase.LoginUserRes) error {
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	a.set(c, accessTokenCookie, res.AccessToken, "/", res.AccessTokenExpiresAt, true)
+	a.set(c, refreshTokenCookie, res.RefreshToken, refreshTokenCookiePath, res.RefreshTokenExpiresAt, true)
+	a.set(c, csrfTokenCookie, csrfToken, "/", res.RefreshTokenExpiresAt, false)
+
+	return nil
+}
+
+func (a *AuthCookies) Clear(c *gin.Context) {
+	if !a.cfg.Enabled {
+		return
+	}
+
+	a.set(c, accessTokenCookie, "", "/", time.Time{}, true)
+	a.set(c, refreshTokenCookie, "", refreshTokenCookiePath, time.Time{}, true)
+	a.set(c, csrfTokenCookie, "", "/", time.Time{}, false)
+}
+
+func (a *AuthCookies) AccessToken(c *gin.Context) string {
+	return a.get(c, accessTokenCookie)
+}
+
+func (a *AuthCookies) RefreshToken(c *gin.Context) string {
+	return a.get(c, refreshTokenCookie)
+}
+
+func (a *AuthCookies) ValidCSRF(c *gin.Context) bool {
+	cookie := a.get(c, csrfTokenCookie)
+	header := c.GetHeader(csrfTokenHeader)
+	if cookie == "" || header == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+func (a *AuthCookies) get(c *gin.Context, name string) string {
+	if !a.cfg.Enabled {
+		return ""
+	}
+
+	value, err := c.Cookie(name)
+	if err != nil {
+		return ""
+	}
+
+	return value
+}
+
+// set с нулевым expiresAt удаляет куку
+func (a *AuthCookies) set(c *gin.Context, name, value, path string, expiresAt time.Time, httpOnly bool) {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   a.cfg.Domain,
+		Secure:   a.cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: a.cfg.SameSite,
+	}
+
+	if expiresAt.IsZero() {
+		cookie.MaxAge = -1
+	} else {
+		cookie.Expires = expiresAt
+		cookie.MaxAge = int(time.Until(expiresAt).Seconds())
+	}
+
+	http.SetCookie(c.Writer, cookie)
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Безопасные методы не меняют состояние и не требуют CSRF токена
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
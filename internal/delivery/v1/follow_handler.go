@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"log"
+	"my_blog_backend/internal/delivery"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) followUser(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	if err := h.services.FollowService.Follow(c.Request.Context(), userId.(uint), c.Param("username")); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (h *Handler) unfollowUser(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	if err := h.services.FollowService.Unfollow(c.Request.Context(), userId.(uint), c.Param("username")); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (h *Handler) getFollowers(c *gin.Context) {
+	var query delivery.ListFollowsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.FollowService.ListFollowers(c.Request.Context(), delivery.ToListFollowsReq(c.Param("username"), &query))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToGetFollowsRes(res))
+}
+
+func (h *Handler) getFollowing(c *gin.Context) {
+	var query delivery.ListFollowsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.FollowService.ListFollowing(c.Request.Context(), delivery.ToListFollowsReq(c.Param("username"), &query))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToGetFollowsRes(res))
+}
+
+func (h *Handler) subscribeCategory(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	if err := h.services.FollowService.SubscribeCategory(c.Request.Context(), userId.(uint), c.Param("slug")); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (h *Handler) unsubscribeCategory(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	if err := h.services.FollowService.UnsubscribeCategory(c.Request.Context(), userId.(uint), c.Param("slug")); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, gin.H{})
+}
+
+func (h *Handler) getSubscriptions(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	res, err := h.services.FollowService.ListSubscriptions(c.Request.Context(), userId.(uint))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToSubscriptionsRes(res))
+}
+
+func (h *Handler) getFeed(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	var query delivery.ListArticlesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.FeedService.Get(c.Request.Context(), userId.(uint), delivery.ToListArticlesReq(&query))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToGetArticlesByUserRes(res))
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"my_blog_backend/internal/domain"
 	"my_blog_backend/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -9,16 +10,20 @@ import (
 type Handler struct {
 	services   *usecase.Services
 	middleware *Middleware
+	cookies    *AuthCookies
 }
 
-func NewHandler(services *usecase.Services, middleware *Middleware) *Handler {
+func NewHandler(services *usecase.Services, middleware *Middleware, cookies *AuthCookies) *Handler {
 	return &Handler{
 		services:   services,
 		middleware: middleware,
+		cookies:    cookies,
 	}
 }
 
 func (h *Handler) Init(api *gin.RouterGroup) {
+	api.GET("/.well-known/jwks.json", h.getJWKS)
+
 	v1 := api.Group("/v1")
 	{
 		auth := v1.Group("/auth")
@@ -27,10 +32,22 @@ func (h *Handler) Init(api *gin.RouterGroup) {
 			auth.POST("/sign-in", h.signIn)
 			auth.POST("/refresh", h.refreshSession)
 			auth.POST("/logout", h.logout)
+			auth.POST("/password/forgot", h.forgotPassword)
+			auth.POST("/password/reset", h.resetPassword)
+			auth.POST("/email/verify", h.verifyEmail)
+			auth.POST("/2fa/verify", h.verifyTwoFactor)
 
 			auth.Use(h.middleware.AuthMiddleware())
 			{
 				auth.POST("/password/change", h.changePassword)
+				auth.GET("/sessions", h.getSessions)
+				auth.DELETE("/sessions/:id", h.revokeSession)
+				auth.POST("/sessions/revoke-others", h.revokeOtherSessions)
+				auth.POST("/email/resend", h.resendVerificationEmail)
+				auth.POST("/2fa/enroll", h.enrollTwoFactor)
+				auth.POST("/2fa/confirm", h.confirmTwoFactor)
+				auth.POST("/2fa/recovery-codes", h.regenerateRecoveryCodes)
+				auth.POST("/2fa/disable", h.disableTwoFactor)
 			}
 		}
 
@@ -38,14 +55,26 @@ func (h *Handler) Init(api *gin.RouterGroup) {
 		{
 			// users.GET("/:id", h.getUserById)
 			users.GET("/:username", h.getUserByUsername)
-			users.GET("/:username/articles", h.getArticlesByUsername)
+			users.GET("/:username/articles", h.middleware.OptionalAuthMiddleware(), h.getArticlesByUsername)
+			users.GET("/me", h.middleware.AuthMiddleware(domain.ScopeProfileRead), h.getCurrentUser)
+			users.GET("/me/articles", h.middleware.AuthMiddleware(domain.ScopeArticlesRead), h.getArticlesByUserId)
+			users.GET("/:username/followers", h.getFollowers)
+			users.GET("/:username/following", h.getFollowing)
 
 			users.Use(h.middleware.AuthMiddleware())
 			{
-				users.GET("/me", h.getCurrentUser)
-				users.GET("/me/articles", h.getArticlesByUserId)
 				users.PATCH("/me/update", h.updateUser)
-				// users.PATCH("me/admin", h.setAdminRole)
+				users.POST("/me/tokens", h.createAccessToken)
+				users.GET("/me/tokens", h.getAccessTokens)
+				users.GET("/me/tokens/:id", h.getAccessToken)
+				users.PATCH("/me/tokens/:id", h.updateAccessToken)
+				users.DELETE("/me/tokens/:id", h.deleteAccessToken)
+				users.GET("/me/export", h.exportAccount)
+				users.DELETE("/me", h.deleteAccount)
+				users.POST("/me/deletion/cancel", h.cancelAccountDeletion)
+				users.GET("/me/subscriptions", h.getSubscriptions)
+				users.POST("/:username/follow", h.followUser)
+				users.DELETE("/:username/follow", h.unfollowUser)
 			}
 		}
 
@@ -54,25 +83,61 @@ func (h *Handler) Init(api *gin.RouterGroup) {
 			categories.GET("", h.GetAllCategories)
 			categories.GET("/:slug/articles", h.getArticlesByCategorySlug)
 
-			categories.Use(h.middleware.AuthMiddleware())
-			{
-				categories.POST("", h.CreateCategory)
-				categories.PATCH("/:slug", h.UpdateCategory)
-				categories.DELETE("/:slug", h.DeleteCategory)
-			}
+			adminCategories := h.middleware.AuthMiddleware(domain.ScopeCategoriesAdmin)
+			categories.POST("", adminCategories, h.CreateCategory)
+			categories.PATCH("/:slug", adminCategories, h.UpdateCategory)
+			categories.DELETE("/:slug", adminCategories, h.DeleteCategory)
+
+			categories.POST("/:slug/subscription", h.middleware.AuthMiddleware(), h.subscribeCategory)
+			categories.DELETE("/:slug/subscription", h.middleware.AuthMiddleware(), h.unsubscribeCategory)
+		}
+
+		// Права проверяет AuthorizationService по роли из базы
+		admin := v1.Group("/admin", h.middleware.AuthMiddleware())
+		{
+			admin.GET("/roles", h.getRoles)
+			admin.GET("/users", h.getUsers)
+			admin.GET("/users/:id", h.getUser)
+			admin.PUT("/users/:id/role", h.changeUserRole)
+			admin.POST("/users/:id/suspend", h.suspendUser)
+			admin.POST("/users/:id/unsuspend", h.unsuspendUser)
+			admin.DELETE("/users/:id", h.deleteUser)
+		}
+
+		// Лента из подписок на авторов и категории
+		v1.GET("/feed", h.middleware.AuthMiddleware(domain.ScopeArticlesRead), h.getFeed)
+
+		tags := v1.Group("/tags")
+		{
+			tags.GET("/:slug/articles", h.getArticlesByTagSlug)
 		}
 
 		articles := v1.Group("/articles")
 		{
-			articles.GET("/:id", h.getArticleByID)
+			articles.GET("/search", h.searchArticles)
+			articles.GET("/by-slug/:slug", h.middleware.OptionalAuthMiddleware(), h.getArticleBySlug)
+			articles.GET("/:id", h.middleware.OptionalAuthMiddleware(), h.getArticleByID)
 			articles.GET("", h.getAllArticles)
+			articles.GET("/:id/comments", h.middleware.OptionalAuthMiddleware(), h.getComments)
 
-			articles.Use(h.middleware.AuthMiddleware())
-			{
-				articles.POST("", h.createArticle)
-				articles.PATCH("/:id", h.updateArticle)
-				articles.DELETE("/:id", h.deleteArticle)
-			}
+			// Маршруты со скоупами принимают и персональные токены
+			readArticles := h.middleware.AuthMiddleware(domain.ScopeArticlesRead)
+			writeArticles := h.middleware.AuthMiddleware(domain.ScopeArticlesWrite)
+			writeComments := h.middleware.AuthMiddleware(domain.ScopeCommentsWrite)
+
+			articles.POST("", writeArticles, h.createArticle)
+			articles.PATCH("/:id", writeArticles, h.updateArticle)
+			articles.DELETE("/:id", writeArticles, h.deleteArticle)
+			articles.POST("/:id/publish", writeArticles, h.publishArticle)
+			articles.POST("/:id/unpublish", writeArticles, h.unpublishArticle)
+			articles.POST("/:id/archive", writeArticles, h.archiveArticle)
+			articles.POST("/:id/comments", writeComments, h.createComment)
+			articles.PATCH("/:id/comments/:commentId", writeComments, h.updateComment)
+			articles.DELETE("/:id/comments/:commentId", writeComments, h.deleteComment)
+			articles.GET("/:id/revisions", readArticles, h.getRevisions)
+			articles.GET("/:id/revisions/diff", readArticles, h.diffRevisions)
+			articles.GET("/:id/revisions/:rev", readArticles, h.getRevision)
+			articles.POST("/:id/revisions/:rev/restore", writeArticles, h.restoreRevision)
 		}
 	}
 }
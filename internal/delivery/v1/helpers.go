@@ -3,8 +3,12 @@ package v1
 import (
 	"errors"
 	"log"
+	"math"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/usecase"
 	"my_blog_backend/pkg/e"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,6 +18,7 @@ func ErrorToHttpRes(err error, c *gin.Context) {
 
 	var code int
 	var message string
+	var details gin.H
 
 	switch {
 	case errors.Is(err, e.ErrCategoryNotFound):
@@ -55,6 +60,18 @@ func ErrorToHttpRes(err error, c *gin.Context) {
 	case errors.Is(err, e.ErrUsernameIsForbidden):
 		code = http.StatusForbidden
 		message = "username is forbidden"
+	case errors.Is(err, e.ErrProfileFieldTooLong):
+		code = http.StatusUnprocessableEntity
+		message = "profile field is too long"
+	case errors.Is(err, e.ErrProfileURLInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "profile url must be an absolute http or https url"
+	case errors.Is(err, e.ErrProfileTooManyLinks):
+		code = http.StatusUnprocessableEntity
+		message = "profile has too many links"
+	case errors.Is(err, e.ErrProfileLinkLabelEmpty):
+		code = http.StatusUnprocessableEntity
+		message = "profile link label is empty"
 	case errors.Is(err, e.ErrPasswordIsSame):
 		code = http.StatusUnprocessableEntity
 		message = "password is same"
@@ -64,12 +81,21 @@ func ErrorToHttpRes(err error, c *gin.Context) {
 	case errors.Is(err, e.ErrUnauthorized):
 		code = http.StatusUnauthorized
 		message = "unauthorized"
+	case errors.Is(err, e.ErrRefreshTokenReused):
+		code = http.StatusUnauthorized
+		message = "refresh token has already been used, the session was revoked"
+	case errors.Is(err, e.ErrRefreshTokenInvalid):
+		code = http.StatusUnauthorized
+		message = "refresh token is invalid"
 	case errors.Is(err, e.ErrArticleCategoryIsExists):
 		code = http.StatusUnprocessableEntity
 		message = "the category of the article is not changed"
-	case errors.Is(err, e.ErrUserAlreadyAdmin):
+	case errors.Is(err, e.ErrRoleIsSame):
 		code = http.StatusUnprocessableEntity
-		message = "user is already admin"
+		message = "role is same"
+	case errors.Is(err, e.ErrInvalidRole):
+		code = http.StatusUnprocessableEntity
+		message = "role is invalid"
 	case errors.Is(err, e.ErrPermissionDenied):
 		code = http.StatusForbidden
 		message = "permission denied"
@@ -82,10 +108,182 @@ func ErrorToHttpRes(err error, c *gin.Context) {
 	case errors.Is(err, e.ErrCategorySlugIsExists):
 		code = http.StatusUnprocessableEntity
 		message = "category slug is exists"
+	case errors.Is(err, e.ErrArticleInvalidStatusTransition):
+		code = http.StatusConflict
+		message = "article status transition is not allowed"
+	case errors.Is(err, e.ErrPublishAtInPast):
+		code = http.StatusUnprocessableEntity
+		message = "publish time must be in the future"
+	case errors.Is(err, e.ErrArticleNotScheduled):
+		code = http.StatusUnprocessableEntity
+		message = "article is not scheduled"
+	case errors.Is(err, e.ErrTagNotFound):
+		code = http.StatusNotFound
+		message = "tag not found"
+	case errors.Is(err, e.ErrTagInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "tag is invalid"
+	case errors.Is(err, e.ErrTooManyTags):
+		code = http.StatusUnprocessableEntity
+		message = "too many tags"
+	case errors.Is(err, e.ErrCommentNotFound):
+		code = http.StatusNotFound
+		message = "comment not found"
+	case errors.Is(err, e.ErrCommentInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "comment is invalid"
+	case errors.Is(err, e.ErrCommentDeleted):
+		code = http.StatusUnprocessableEntity
+		message = "comment is deleted"
+	case errors.Is(err, e.ErrCommentContentIsSame):
+		code = http.StatusUnprocessableEntity
+		message = "the content of the comment is not changed"
+	case errors.Is(err, e.ErrUserNotCommentAuthor):
+		code = http.StatusForbidden
+		message = "you are not the author of the comment"
+	case errors.Is(err, e.ErrRevisionNotFound):
+		code = http.StatusNotFound
+		message = "revision not found"
+	case errors.Is(err, e.ErrPasswordResetTokenInvalid):
+		code = http.StatusBadRequest
+		message = "password reset token is invalid or expired"
+	case errors.Is(err, e.ErrEmailVerificationTokenInvalid):
+		code = http.StatusBadRequest
+		message = "email verification token is invalid or expired"
+	case errors.Is(err, e.ErrEmailAlreadyVerified):
+		code = http.StatusConflict
+		message = "email is already verified"
+	case errors.Is(err, e.ErrEmailNotVerified):
+		code = http.StatusForbidden
+		message = "email is not verified"
+	case errors.Is(err, e.ErrTwoFactorCodeInvalid):
+		code = http.StatusUnauthorized
+		message = "two-factor code is invalid"
+	case errors.Is(err, e.ErrTwoFactorChallengeInvalid):
+		code = http.StatusUnauthorized
+		message = "two-factor challenge is invalid or expired"
+	case errors.Is(err, e.ErrTwoFactorAlreadyEnabled):
+		code = http.StatusConflict
+		message = "two-factor authentication is already enabled"
+	case errors.Is(err, e.ErrTwoFactorNotEnabled):
+		code = http.StatusConflict
+		message = "two-factor authentication is not enabled"
+	case errors.Is(err, e.ErrTwoFactorNotEnrolled):
+		code = http.StatusConflict
+		message = "two-factor enrollment is not started"
+	case errors.Is(err, e.ErrAccessTokenNotFound):
+		code = http.StatusNotFound
+		message = "access token not found"
+	case errors.Is(err, e.ErrAccessTokenTTLInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "access token expiry is invalid"
+	case errors.Is(err, e.ErrAccessTokenNameInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "access token name is invalid"
+	case errors.Is(err, e.ErrAccessTokenScopeInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "access token scope is invalid"
+	case errors.Is(err, e.ErrTooManyAccessTokens):
+		code = http.StatusUnprocessableEntity
+		message = "too many access tokens"
+	case errors.Is(err, e.ErrPasswordRejected):
+		code = http.StatusUnprocessableEntity
+		message = "password does not meet the policy"
+
+		var policyErr *e.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			details = gin.H{"reasons": passwordReasons(policyErr.Reasons)}
+		}
+	case errors.Is(err, e.ErrUserSuspended):
+		code = http.StatusForbidden
+		message = "account is suspended"
+	case errors.Is(err, e.ErrUserAlreadySuspended):
+		code = http.StatusConflict
+		message = "user is already suspended"
+	case errors.Is(err, e.ErrUserNotSuspended):
+		code = http.StatusConflict
+		message = "user is not suspended"
+	case errors.Is(err, e.ErrUserHasArticles):
+		code = http.StatusConflict
+		message = "user has articles, reassign or purge them"
+	case errors.Is(err, e.ErrReassignTargetInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "user to reassign articles to is invalid"
+	case errors.Is(err, e.ErrDeleteUserModeInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "articles handling mode is invalid"
+	case errors.Is(err, e.ErrAccountDeletionScheduled):
+		code = http.StatusConflict
+		message = "account deletion is already scheduled"
+	case errors.Is(err, e.ErrAccountDeletionNotScheduled):
+		code = http.StatusConflict
+		message = "account deletion is not scheduled"
+	case errors.Is(err, e.ErrArticleDisposalInvalid):
+		code = http.StatusUnprocessableEntity
+		message = "articles handling mode is invalid"
+	case errors.Is(err, e.ErrCannotFollowSelf):
+		code = http.StatusUnprocessableEntity
+		message = "user cannot follow themselves"
+	case errors.Is(err, e.ErrAlreadyFollowing):
+		code = http.StatusConflict
+		message = "user is already followed"
+	case errors.Is(err, e.ErrNotFollowing):
+		code = http.StatusNotFound
+		message = "user is not followed"
+	case errors.Is(err, e.ErrAlreadySubscribed):
+		code = http.StatusConflict
+		message = "category is already subscribed"
+	case errors.Is(err, e.ErrNotSubscribed):
+		code = http.StatusNotFound
+		message = "category is not subscribed"
+	case errors.Is(err, e.ErrSessionNotFound):
+		code = http.StatusNotFound
+		message = "session not found"
+	case errors.Is(err, e.ErrTooManyAttempts):
+		code = http.StatusTooManyRequests
+		message = "too many attempts, try again later"
+
+		var retryErr *e.RetryError
+		if errors.As(err, &retryErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+		}
+	case errors.Is(err, e.ErrInvalidCursor):
+		code = http.StatusBadRequest
+		message = "invalid cursor"
 	default:
 		code = http.StatusInternalServerError
 		message = "internal server error"
 	}
 
-	c.JSON(code, gin.H{"error": message})
+	res := gin.H{"error": message}
+	for key, value := range details {
+		res[key] = value
+	}
+
+	c.JSON(code, res)
+}
+
+var passwordReasonMessages = map[string]string{
+	domain.PasswordTooShort:         "password is too short",
+	domain.PasswordTooLong:          "password is too long",
+	domain.PasswordTooWeak:          "password is too easy to guess",
+	domain.PasswordContainsUsername: "password must not contain the username",
+	domain.PasswordContainsEmail:    "password must not contain the email",
+	domain.PasswordBreached:         "password has appeared in a data breach",
+}
+
+func passwordReasons(codes []string) []gin.H {
+	reasons := make([]gin.H, len(codes))
+	for i, code := range codes {
+		reasons[i] = gin.H{"code": code, "message": passwordReasonMessages[code]}
+	}
+
+	return reasons
+}
+
+func clientInfo(c *gin.Context) usecase.ClientInfo {
+	return usecase.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
 }
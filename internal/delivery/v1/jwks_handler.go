@@ -0,0 +1,15 @@
+package v1
+
+import (
+	"my_blog_backend/internal/delivery"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) getJWKS(c *gin.Context) {
+	// Ключи меняются только при ротации, но кэш должен быть короче
+	// периода, в течение которого старый ключ ещё принимается
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, delivery.ToJWKSRes(h.services.UserService.PublicKeys()))
+}
@@ -2,59 +2,167 @@ package v1
 
 import (
 	"errors"
+	"my_blog_backend/internal/domain"
 	"my_blog_backend/internal/usecase"
 	"my_blog_backend/pkg/e"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Middleware struct {
-	tokenManager usecase.TokenManager
+	users        *usecase.UserService
+	accessTokens *usecase.AccessTokenService
+	cookies      *AuthCookies
 }
 
-func NewMiddleware(tokenManager usecase.TokenManager) *Middleware {
-	return &Middleware{tokenManager: tokenManager}
+func NewMiddleware(users *usecase.UserService, accessTokens *usecase.AccessTokenService, cookies *AuthCookies) *Middleware {
+	return &Middleware{
+		users:        users,
+		accessTokens: accessTokens,
+		cookies:      cookies,
+	}
 }
 
-func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware требует JWT. Если переданы scopes, маршрут принимает и
+// персональные токены, у которых есть все эти скоупы
+func (m *Middleware) AuthMiddleware(scopes ...domain.Scope) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		if !m.hasCredentials(c) {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": e.ErrUnauthorized.Error(),
 			})
 			return
 		}
 
-		const prefix = "Bearer "
-		if !strings.HasPrefix(authHeader, prefix) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid authorization header format",
-			})
+		if !m.authenticate(c, scopes) {
 			return
 		}
-		jwtToken := strings.TrimPrefix(authHeader, prefix)
 
-		authenticatedUser, err := m.tokenManager.VerifyJWT(jwtToken)
-		if err != nil {
-			if errors.Is(err, e.ErrTokenInvalid) || errors.Is(err, e.ErrParseFailed) {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-					"error": e.ErrUnauthorized.Error(),
-				})
-				return
-			}
+		c.Next()
+	}
+}
 
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": e.ErrInternalServer.Error(),
-			})
+// OptionalAuthMiddleware пропускает анонимные запросы,
+// но если токен передан, он должен быть валидным
+func (m *Middleware) OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.hasCredentials(c) {
+			c.Next()
 			return
 		}
 
-		c.Set("user_id", authenticatedUser.ID)
-		c.Set("role", authenticatedUser.Role)
+		if !m.authenticate(c, nil) {
+			return
+		}
 
 		c.Next()
 	}
 }
+
+func (m *Middleware) hasCredentials(c *gin.Context) bool {
+	return c.GetHeader("Authorization") != "" || m.cookies.AccessToken(c) != ""
+}
+
+func (m *Middleware) authenticate(c *gin.Context, scopes []domain.Scope) bool {
+	token, ok := m.requestToken(c)
+	if !ok {
+		return false
+	}
+
+	if usecase.IsAccessToken(token) {
+		return m.authenticateAccessToken(c, token, scopes)
+	}
+
+	authenticatedUser, err := m.users.AuthenticateJWT(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, e.ErrTokenInvalid) || errors.Is(err, e.ErrParseFailed) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": e.ErrUnauthorized.Error(),
+			})
+			return false
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": e.ErrInternalServer.Error(),
+		})
+		return false
+	}
+
+	setAuthenticatedUser(c, authenticatedUser)
+	return true
+}
+
+// authenticateAccessToken пускает персональный токен только на маршруты,
+// где явно перечислены скоупы. Управление аккаунтом остаётся за JWT
+func (m *Middleware) authenticateAccessToken(c *gin.Context, token string, scopes []domain.Scope) bool {
+	if len(scopes) == 0 {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "personal access tokens are not allowed for this route",
+		})
+		return false
+	}
+
+	authenticatedUser, err := m.accessTokens.Authenticate(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, e.ErrAccessTokenInvalid) || errors.Is(err, e.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": e.ErrUnauthorized.Error(),
+			})
+			return false
+		}
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": e.ErrInternalServer.Error(),
+		})
+		return false
+	}
+
+	for _, required := range scopes {
+		if !slices.Contains(authenticatedUser.Scopes, required) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "insufficient scope",
+				"scope": required,
+			})
+			return false
+		}
+	}
+
+	setAuthenticatedUser(c, authenticatedUser)
+	return true
+}
+
+// requestToken берёт токен из заголовка Authorization, а без него - из куки.
+// Куку браузер отправляет сам, поэтому изменяющий запрос с ней должен
+// подтвердить CSRF токеном
+func (m *Middleware) requestToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		if !isSafeMethod(c.Request.Method) && !m.cookies.ValidCSRF(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "invalid csrf token",
+			})
+			return "", false
+		}
+
+		return m.cookies.AccessToken(c), true
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(authHeader, prefix) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "invalid authorization header format",
+		})
+		return "", false
+	}
+
+	return strings.TrimPrefix(authHeader, prefix), true
+}
+
+func setAuthenticatedUser(c *gin.Context, user *usecase.AuthenticatedUser) {
+	c.Set("user_id", user.ID)
+	c.Set("session_id", user.SessionID)
+	c.Set("role", user.Role)
+}
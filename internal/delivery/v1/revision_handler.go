@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"log"
+	"my_blog_backend/internal/delivery"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) getRevisions(c *gin.Context) {
+	strUserId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	articleId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.ArticleService.ListRevisions(c.Request.Context(), delivery.ToListRevisionsReq(strUserId.(uint), uint(articleId)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToGetRevisionsRes(res))
+}
+
+func (h *Handler) getRevision(c *gin.Context) {
+	strUserId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	articleId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.ArticleService.GetRevision(c.Request.Context(), delivery.ToGetRevisionReq(strUserId.(uint), uint(articleId), number))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToRevisionRes(res))
+}
+
+func (h *Handler) diffRevisions(c *gin.Context) {
+	strUserId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	articleId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	var query delivery.DiffRevisionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.ArticleService.DiffRevisions(c.Request.Context(), delivery.ToDiffRevisionsReq(&query, strUserId.(uint), uint(articleId)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToRevisionDiffRes(res))
+}
+
+func (h *Handler) restoreRevision(c *gin.Context) {
+	strUserId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	articleId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad request"})
+		return
+	}
+
+	res, err := h.services.ArticleService.RestoreRevision(c.Request.Context(), delivery.ToRestoreRevisionReq(strUserId.(uint), uint(articleId), number))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToUpdateArticleRes(res))
+}
@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"log"
+	"my_blog_backend/internal/delivery"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) verifyTwoFactor(c *gin.Context) {
+	var req delivery.VerifyTwoFactorReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := h.services.UserService.VerifyTwoFactor(c.Request.Context(), delivery.ToVerifyTwoFactorReq(&req, clientInfo(c)))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	h.writeSession(c, res)
+}
+
+func (h *Handler) enrollTwoFactor(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	res, err := h.services.TwoFactorService.Enroll(c.Request.Context(), userId.(uint))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToTwoFactorEnrollmentRes(res))
+}
+
+func (h *Handler) confirmTwoFactor(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	var req delivery.TwoFactorCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := h.services.TwoFactorService.Confirm(c.Request.Context(), userId.(uint), req.Code)
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToRecoveryCodesRes(res))
+}
+
+func (h *Handler) regenerateRecoveryCodes(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	var req delivery.TwoFactorCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := h.services.TwoFactorService.RegenerateRecoveryCodes(c.Request.Context(), userId.(uint), req.Code)
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery.ToRecoveryCodesRes(res))
+}
+
+func (h *Handler) disableTwoFactor(c *gin.Context) {
+	userId, exists := c.Get("user_id")
+	if !exists {
+		if c.GetHeader("Authorization") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "user ID not found in context"})
+		}
+		return
+	}
+
+	var req delivery.DisableTwoFactorReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	if err := h.services.TwoFactorService.Disable(c.Request.Context(), userId.(uint), delivery.ToDisableTwoFactorReq(&req)); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -3,6 +3,7 @@ package v1
 import (
 	"log"
 	"my_blog_backend/internal/delivery"
+	"my_blog_backend/internal/usecase"
 	"net/http"
 	"strconv"
 
@@ -29,7 +30,6 @@ func (h *Handler) signUp(c *gin.Context) {
 	c.JSON(http.StatusCreated, delivery.ToUserRes(user))
 }
 
-// TODO: возвращать данные в куки
 func (h *Handler) signIn(c *gin.Context) {
 	var req delivery.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -40,13 +40,18 @@ func (h *Handler) signIn(c *gin.Context) {
 		return
 	}
 
-	res, err := h.services.UserService.LoginUser(c.Request.Context(), delivery.ToLoginUserReq(&req))
+	res, err := h.services.UserService.LoginUser(c.Request.Context(), delivery.ToLoginUserReq(&req, clientInfo(c)))
 	if err != nil {
 		ErrorToHttpRes(err, c)
 		return
 	}
 
-	c.JSON(http.StatusOK, delivery.ToLoginUserRes(res))
+	if res.TwoFactorChallenge != nil {
+		c.JSON(http.StatusOK, delivery.ToTwoFactorChallengeRes(res.TwoFactorChallenge))
+		return
+	}
+
+	h.writeSession(c, res)
 }
 
 func (h *Handler) getCurrentUser(c *gin.Context) {
@@ -89,13 +94,13 @@ func (h *Handler) getUserById(c *gin.Context) {
 
 func (h *Handler) getUserByUsername(c *gin.Context) {
 	username := c.Param("username")
-	user, err := h.services.UserService.GetUserByUsername(c.Request.Context(), username)
+	profile, err := h.services.UserService.GetProfile(c.Request.Context(), username)
 	if err != nil {
 		ErrorToHttpRes(err, c)
 		return
 	}
 
-	c.JSON(http.StatusOK, delivery.ToUserRes(user))
+	c.JSON(http.StatusOK, delivery.ToPublicUserRes(profile))
 }
 
 func (h *Handler) updateUser(c *gin.Context) {
@@ -155,9 +160,8 @@ func (h *Handler) changePassword(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{})
 }
 
-// TODO: возвращать данные в куки
-func (h *Handler) refreshSession(c *gin.Context) {
-	var req delivery.RefreshTokenReq
+func (h *Handler) forgotPassword(c *gin.Context) {
+	var req delivery.ForgotPasswordReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Println(err)
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -166,17 +170,32 @@ func (h *Handler) refreshSession(c *gin.Context) {
 		return
 	}
 
-	res, err := h.services.UserService.RefreshSession(c.Request.Context(), req.RefreshToken)
-	if err != nil {
+	h.services.PasswordResetService.ForgotPassword(c.Request.Context(), req.Email)
+
+	// Ответ одинаковый вне зависимости от того, есть ли такой пользователь
+	c.JSON(http.StatusAccepted, gin.H{})
+}
+
+func (h *Handler) resetPassword(c *gin.Context) {
+	var req delivery.ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	if err := h.services.PasswordResetService.ResetPassword(c.Request.Context(), delivery.ToResetPasswordReq(&req)); err != nil {
 		ErrorToHttpRes(err, c)
 		return
 	}
 
-	c.JSON(http.StatusOK, delivery.ToLoginUserRes(res))
+	c.JSON(http.StatusOK, gin.H{})
 }
 
-func (h *Handler) logout(c *gin.Context) {
-	var req delivery.LogoutUserReq
+func (h *Handler) verifyEmail(c *gin.Context) {
+	var req delivery.VerifyEmailReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Println(err)
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -185,15 +204,16 @@ func (h *Handler) logout(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.UserService.LogoutUser(c.Request.Context(), req.RefreshToken); err != nil {
+	user, err := h.services.EmailVerificationService.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
 		ErrorToHttpRes(err, c)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{})
+	c.JSON(http.StatusOK, delivery.ToUserRes(user))
 }
 
-func (h *Handler) setAdminRole(c *gin.Context) {
+func (h *Handler) resendVerificationEmail(c *gin.Context) {
 	userId, exists := c.Get("user_id")
 	if !exists {
 		if c.GetHeader("Authorization") == "" {
@@ -204,10 +224,83 @@ func (h *Handler) setAdminRole(c *gin.Context) {
 		return
 	}
 
-	if err := h.services.UserService.SetAdminRole(c.Request.Context(), userId.(uint)); err != nil {
+	if err := h.services.EmailVerificationService.Resend(c.Request.Context(), userId.(uint)); err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{})
+}
+
+func (h *Handler) refreshSession(c *gin.Context) {
+	refreshToken, ok := h.requestRefreshToken(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.services.UserService.RefreshSession(c.Request.Context(), refreshToken, clientInfo(c))
+	if err != nil {
+		ErrorToHttpRes(err, c)
+		return
+	}
+
+	h.writeSession(c, res)
+}
+
+func (h *Handler) logout(c *gin.Context) {
+	refreshToken, ok := h.requestRefreshToken(c)
+	if !ok {
+		return
+	}
+
+	// Куки чистим в любом случае: с недействительным токеном они уже бесполезны
+	h.cookies.Clear(c)
+
+	if err := h.services.UserService.LogoutUser(c.Request.Context(), refreshToken); err != nil {
 		ErrorToHttpRes(err, c)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{})
 }
+
+// writeSession отдаёт выданную сессию. В cookie режиме токены уходят только
+// в HttpOnly куки, чтобы SPA не хранила их в localStorage
+func (h *Handler) writeSession(c *gin.Context, res *usecase.LoginUserRes) {
+	body := delivery.ToLoginUserRes(res)
+
+	if h.cookies.Enabled() {
+		if err := h.cookies.SetSession(c, res); err != nil {
+			ErrorToHttpRes(err, c)
+			return
+		}
+
+		body.AccessToken = ""
+		body.RefreshToken = ""
+	}
+
+	c.JSON(http.StatusOK, body)
+}
+
+// requestRefreshToken берёт refresh токен из куки, а без неё - из тела запроса
+func (h *Handler) requestRefreshToken(c *gin.Context) (string, bool) {
+	if refreshToken := h.cookies.RefreshToken(c); refreshToken != "" {
+		if !h.cookies.ValidCSRF(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid csrf token"})
+			return "", false
+		}
+
+		return refreshToken, true
+	}
+
+	var req delivery.RefreshTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Println(err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return "", false
+	}
+
+	return req.RefreshToken, true
+}
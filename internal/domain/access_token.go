@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// AccessTokenPrefix отличает персональные токены от JWT в заголовке Authorization
+const AccessTokenPrefix = "mbp_"
+
+const (
+	MaxAccessTokenNameLength = 64
+	MaxAccessTokensPerUser   = 50
+)
+
+type Scope string
+
+const (
+	ScopeProfileRead     Scope = "profile:read"
+	ScopeArticlesRead    Scope = "articles:read"
+	ScopeArticlesWrite   Scope = "articles:write"
+	ScopeCommentsWrite   Scope = "comments:write"
+	ScopeCategoriesAdmin Scope = "categories:admin"
+)
+
+var knownScopes = map[Scope]struct{}{
+	ScopeProfileRead:     {},
+	ScopeArticlesRead:    {},
+	ScopeArticlesWrite:   {},
+	ScopeCommentsWrite:   {},
+	ScopeCategoriesAdmin: {},
+}
+
+// AccessToken - долгоживущий персональный токен для скриптов и CI.
+// Хранится только хэш, сам токен показывается один раз при создании
+type AccessToken struct {
+	ID         uint
+	UserID     uint
+	Name       string
+	TokenHash  string
+	Scopes     []Scope
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+}
+
+func NewAccessToken(userID uint, name, tokenHash string, scopes []Scope, expiresAt time.Time) (*AccessToken, error) {
+	token := &AccessToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := token.Rename(name); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (t *AccessToken) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxAccessTokenNameLength {
+		return e.ErrAccessTokenNameInvalid
+	}
+
+	t.Name = name
+	return nil
+}
+
+func (t *AccessToken) ValidateState(now time.Time) error {
+	if !now.Before(t.ExpiresAt) {
+		return e.ErrAccessTokenInvalid
+	}
+
+	return nil
+}
+
+// ParseScopes проверяет, что все скоупы известны, и убирает повторы
+func ParseScopes(raw []string) ([]Scope, error) {
+	if len(raw) == 0 {
+		return nil, e.ErrAccessTokenScopeInvalid
+	}
+
+	seen := make(map[Scope]struct{}, len(raw))
+	scopes := make([]Scope, 0, len(raw))
+	for _, r := range raw {
+		scope := Scope(strings.TrimSpace(r))
+		if _, ok := knownScopes[scope]; !ok {
+			return nil, e.ErrAccessTokenScopeInvalid
+		}
+
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+
+		seen[scope] = struct{}{}
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
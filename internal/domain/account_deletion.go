@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"time"
+)
+
+// ArticleDisposal - что сделать со статьями, когда аккаунт будет удалён
+type ArticleDisposal string
+
+const (
+	ArticlesAnonymize ArticleDisposal = "anonymize"
+	ArticlesDelete    ArticleDisposal = "delete"
+)
+
+func ParseArticleDisposal(value string) (ArticleDisposal, error) {
+	switch disposal := ArticleDisposal(value); disposal {
+	case ArticlesAnonymize, ArticlesDelete:
+		return disposal, nil
+	default:
+		return "", e.ErrArticleDisposalInvalid
+	}
+}
+
+// IsGhost сообщает, что это служебный пользователь, которому передаются обезличенные
+// статьи удалённых аккаунтов. Он отмечен флагом, а не именем: имя можно сменить
+func (u *User) IsGhost() bool {
+	return u.IsSystem
+}
+
+func (u *User) IsDeletionScheduled() bool {
+	return u.DeletionScheduledAt != nil
+}
+
+// ScheduleDeletion откладывает удаление до deleteAt. До этого момента
+// пользователь может войти и отменить удаление
+func (u *User) ScheduleDeletion(deleteAt time.Time, articles ArticleDisposal) error {
+	if u.IsDeletionScheduled() {
+		return e.ErrAccountDeletionScheduled
+	}
+
+	u.DeletionScheduledAt = &deleteAt
+	u.DeletionArticles = articles
+	return nil
+}
+
+func (u *User) CancelDeletion() error {
+	if !u.IsDeletionScheduled() {
+		return e.ErrAccountDeletionNotScheduled
+	}
+
+	u.DeletionScheduledAt = nil
+	u.DeletionArticles = ""
+	return nil
+}
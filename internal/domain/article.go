@@ -4,26 +4,45 @@ import (
 	"my_blog_backend/pkg/e"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Article struct {
-	ID         uint
-	Title      string
-	Content    string
-	AuthorID   uint
-	CategoryID uint
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	Author     *User
-	Category   *Category
+	ID          uint
+	Title       string
+	Slug        string
+	Content     string // Markdown
+	ContentHTML string // отрендеренный и очищенный Content, в базе не хранится
+	AuthorID    uint
+	CategoryID  uint
+	Status      ArticleStatus
+	PublishedAt *time.Time
+	PublishAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Author      *User
+	Category    *Category
+	Tags        []Tag
 }
 
+// Жизненный цикл статьи: draft -> published -> archived.
+// Опубликованную или архивную статью можно вернуть в черновики
+type ArticleStatus string
+
+const (
+	ArticleStatusDraft     ArticleStatus = "draft"
+	ArticleStatusPublished ArticleStatus = "published"
+	ArticleStatusArchived  ArticleStatus = "archived"
+)
+
 func NewArticle(title, content string, authorId, CategoryId uint) *Article {
 	return &Article{
 		Title:      title,
 		Content:    content,
 		AuthorID:   authorId,
 		CategoryID: CategoryId,
+		Status:     ArticleStatusDraft,
 	}
 }
 
@@ -39,17 +58,26 @@ func (a *Article) Validate() error {
 	return nil
 }
 
+// Заголовок - обычный текст, разметка в нём не рендерится
 func ValidateTitle(title string) error {
-	if strings.Contains(title, "<") || strings.Contains(title, ">") {
-		return e.ErrTitleHasHTML
+	if strings.TrimSpace(title) == "" || !utf8.ValidString(title) {
+		return e.ErrTitleInvalid
+	}
+
+	for _, r := range title {
+		if r == '<' || r == '>' || unicode.IsControl(r) {
+			return e.ErrTitleInvalid
+		}
 	}
 
 	return nil
 }
 
+// Content хранится как Markdown. От XSS защищает санитайзер при рендеринге,
+// здесь отсекаются только данные, которые нельзя корректно сохранить и отрендерить
 func ValidateContent(content string) error {
-	if strings.Contains(content, "<script") {
-		return e.ErrContentHasScript
+	if strings.TrimSpace(content) == "" || !utf8.ValidString(content) || strings.ContainsRune(content, 0) {
+		return e.ErrContentInvalid
 	}
 
 	return nil
@@ -79,9 +107,100 @@ func (a *Article) ChangeCategory(newCategory *Category) error {
 	}
 
 	a.Category = newCategory
+	a.CategoryID = newCategory.ID
 	return nil
 }
 
+// Restore переносит в статью состояние ревизии. Если категория ревизии удалена, остаётся текущая
+func (a *Article) Restore(revision *ArticleRevision, category *Category) error {
+	if revision.ArticleID != a.ID {
+		return e.ErrRevisionNotFound
+	}
+
+	sameCategory := category == nil || category.ID == a.CategoryID
+	if revision.Title == a.Title && revision.Content == a.Content && sameCategory {
+		return e.ErrNoDataToUpdate
+	}
+
+	a.Title = revision.Title
+	a.Content = revision.Content
+	if category != nil {
+		a.CategoryID = category.ID
+		a.Category = category
+	}
+
+	return nil
+}
+
+func (a *Article) Publish(now time.Time) error {
+	if a.Status != ArticleStatusDraft {
+		return e.ErrArticleInvalidStatusTransition
+	}
+
+	a.Status = ArticleStatusPublished
+	a.PublishAt = nil
+	if a.PublishedAt == nil {
+		a.PublishedAt = &now
+	}
+
+	return nil
+}
+
+// Schedule откладывает публикацию черновика до момента at
+func (a *Article) Schedule(at, now time.Time) error {
+	if a.Status != ArticleStatusDraft {
+		return e.ErrArticleInvalidStatusTransition
+	}
+
+	if !at.After(now) {
+		return e.ErrPublishAtInPast
+	}
+
+	at = at.UTC()
+	a.PublishAt = &at
+	return nil
+}
+
+func (a *Article) Unschedule() error {
+	if a.PublishAt == nil {
+		return e.ErrArticleNotScheduled
+	}
+
+	a.PublishAt = nil
+	return nil
+}
+
+func (a *Article) IsDue(now time.Time) bool {
+	return a.Status == ArticleStatusDraft && a.PublishAt != nil && !a.PublishAt.After(now)
+}
+
+func (a *Article) Unpublish() error {
+	if a.Status != ArticleStatusPublished && a.Status != ArticleStatusArchived {
+		return e.ErrArticleInvalidStatusTransition
+	}
+
+	a.Status = ArticleStatusDraft
+	return nil
+}
+
+func (a *Article) Archive() error {
+	if a.Status != ArticleStatusPublished {
+		return e.ErrArticleInvalidStatusTransition
+	}
+
+	a.Status = ArticleStatusArchived
+	return nil
+}
+
+func (a *Article) IsPublished() bool {
+	return a.Status == ArticleStatusPublished
+}
+
+// Неопубликованные статьи видит только автор
+func (a *Article) IsVisibleTo(userId uint) bool {
+	return a.IsPublished() || (userId != 0 && a.AuthorID == userId)
+}
+
 func (a *Article) CheckAuthor(userId uint) error {
 	if a.AuthorID != userId {
 		return e.ErrUserNotAuthor
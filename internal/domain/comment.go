@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"strings"
+	"time"
+)
+
+const (
+	MaxCommentLength    = 4000
+	DefaultCommentDepth = 3
+	MaxCommentDepth     = 10
+)
+
+type Comment struct {
+	ID        uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ArticleID uint
+	// AuthorID равен 0, если автор комментария удалил аккаунт
+	AuthorID  uint
+	ParentID  *uint
+	Content   string
+	IsDeleted bool
+	Author    *User
+}
+
+func NewComment(articleId, authorId uint, parentId *uint, content string) *Comment {
+	return &Comment{
+		ArticleID: articleId,
+		AuthorID:  authorId,
+		ParentID:  parentId,
+		Content:   strings.TrimSpace(content),
+	}
+}
+
+func (c *Comment) Validate() error {
+	return ValidateCommentContent(c.Content)
+}
+
+func ValidateCommentContent(content string) error {
+	content = strings.TrimSpace(content)
+	if content == "" || len([]rune(content)) > MaxCommentLength {
+		return e.ErrCommentInvalid
+	}
+
+	return nil
+}
+
+func (c *Comment) ChangeContent(newContent string) error {
+	if c.IsDeleted {
+		return e.ErrCommentDeleted
+	}
+
+	newContent = strings.TrimSpace(newContent)
+	if newContent == c.Content {
+		return e.ErrCommentContentIsSame
+	}
+
+	if err := ValidateCommentContent(newContent); err != nil {
+		return err
+	}
+
+	c.Content = newContent
+	return nil
+}
+
+// Tombstone скрывает текст и автора, но оставляет комментарий в дереве, чтобы не потерять ответы на него
+func (c *Comment) Tombstone() {
+	c.IsDeleted = true
+	c.Content = ""
+}
+
+// Отвечать можно только на комментарии той же статьи, которые ещё не удалены
+func (c *Comment) CanBeRepliedIn(articleId uint) error {
+	if c.ArticleID != articleId {
+		return e.ErrCommentNotFound
+	}
+
+	if c.IsDeleted {
+		return e.ErrCommentDeleted
+	}
+
+	return nil
+}
+
+func (c *Comment) CheckAuthor(userId uint) error {
+	if c.AuthorID == 0 || c.AuthorID != userId {
+		return e.ErrUserNotCommentAuthor
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"time"
+)
+
+// EmailVerificationToken подтверждает конкретный адрес: при смене email
+// токен выдаётся на новый адрес, а старый остаётся рабочим до подтверждения
+type EmailVerificationToken struct {
+	ID        uint
+	UserID    uint
+	Email     string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+func NewEmailVerificationToken(userID uint, email, tokenHash string, expiresAt time.Time) *EmailVerificationToken {
+	return &EmailVerificationToken{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (t *EmailVerificationToken) ValidateState(now time.Time) error {
+	if t.UsedAt != nil || !now.Before(t.ExpiresAt) {
+		return e.ErrEmailVerificationTokenInvalid
+	}
+
+	return nil
+}
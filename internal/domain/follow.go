@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"time"
+)
+
+// Follow - подписка пользователя FollowerID на автора FolloweeID
+type Follow struct {
+	FollowerID uint
+	FolloweeID uint
+	CreatedAt  time.Time
+	Follower   *User
+	Followee   *User
+}
+
+// CategorySubscription добавляет в ленту пользователя все статьи категории
+type CategorySubscription struct {
+	UserID     uint
+	CategoryID uint
+	CreatedAt  time.Time
+	Category   *Category
+}
+
+func NewFollow(followerID, followeeID uint) (*Follow, error) {
+	if followerID == followeeID {
+		return nil, e.ErrCannotFollowSelf
+	}
+
+	return &Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	}, nil
+}
+
+func NewCategorySubscription(userID, categoryID uint) *CategorySubscription {
+	return &CategorySubscription{
+		UserID:     userID,
+		CategoryID: categoryID,
+	}
+}
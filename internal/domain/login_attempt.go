@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// LoginAttempts - счётчик неудачных попыток входа по ключу (email или IP)
+type LoginAttempts struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// LockoutPolicy: первые FreeAttempts ошибок проходят без задержки, после этого
+// каждая следующая ошибка удваивает паузу от BaseDelay до MaxDelay.
+// Счётчик сбрасывается, если ошибок не было дольше Window
+type LockoutPolicy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Window       time.Duration
+}
+
+// RetryAfter возвращает, сколько ещё ждать до следующей попытки, или 0
+func (p LockoutPolicy) RetryAfter(a *LoginAttempts, now time.Time) time.Duration {
+	if a == nil || a.Failures < p.FreeAttempts || !p.IsActive(a, now) {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts; i < a.Failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	until := a.LastFailureAt.Add(delay)
+	if !now.Before(until) {
+		return 0
+	}
+
+	return until.Sub(now)
+}
+
+// IsActive - счётчик ещё не сброшен по истечении Window
+func (p LockoutPolicy) IsActive(a *LoginAttempts, now time.Time) bool {
+	return now.Sub(a.LastFailureAt) < p.Window
+}
+
+// Attempt учитывает новую попытку входа как неудачную, если блокировка не действует.
+// Иначе счётчик не меняется и возвращается время до конца блокировки
+func (p LockoutPolicy) Attempt(a *LoginAttempts, now time.Time) time.Duration {
+	if retryAfter := p.RetryAfter(a, now); retryAfter > 0 {
+		return retryAfter
+	}
+
+	if !p.IsActive(a, now) {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.LastFailureAt = now
+
+	return 0
+}
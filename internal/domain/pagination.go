@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"my_blog_backend/pkg/e"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Cursor указывает на последнюю отданную запись, выборка продолжается строго после неё
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+type Page struct {
+	Limit int
+	After *Cursor
+}
+
+func NewPage(limit int, cursor string) (Page, error) {
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	page := Page{Limit: limit}
+	if cursor == "" {
+		return page, nil
+	}
+
+	after, err := DecodeCursor(cursor)
+	if err != nil {
+		return Page{}, err
+	}
+
+	page.After = after
+	return page, nil
+}
+
+func (c *Cursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(cursor string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, e.ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 2 {
+		return nil, e.ErrInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, e.ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || id == 0 {
+		return nil, e.ErrInvalidCursor
+	}
+
+	return &Cursor{
+		CreatedAt: time.Unix(0, nanos).UTC(),
+		ID:        uint(id),
+	}, nil
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"strings"
+	"unicode/utf8"
+)
+
+// Коды причин отказа. Клиент показывает по ним подсказку пользователю
+const (
+	PasswordTooShort         = "too_short"
+	PasswordTooLong          = "too_long"
+	PasswordTooWeak          = "too_weak"
+	PasswordContainsUsername = "contains_username"
+	PasswordContainsEmail    = "contains_email"
+	PasswordBreached         = "breached"
+)
+
+// Части имени и email короче этого в пароле не ищем: слишком много совпадений
+const minPersonalInfoLength = 3
+
+type PasswordPolicy struct {
+	MinLength int
+	MaxLength int
+	// Минимальная оценка стойкости от 0 до 4
+	MinScore int
+}
+
+// PasswordCandidate - новый пароль вместе с тем, что о нём известно заранее
+type PasswordCandidate struct {
+	Password string
+	Username string
+	Email    string
+	Score    int
+	Breached bool
+}
+
+// Check возвращает *e.PasswordPolicyError со всеми нарушенными правилами
+func (p PasswordPolicy) Check(c PasswordCandidate) error {
+	var reasons []string
+
+	if utf8.RuneCountInString(c.Password) < p.MinLength {
+		reasons = append(reasons, PasswordTooShort)
+	}
+	if p.TooLong(c.Password) {
+		reasons = append(reasons, PasswordTooLong)
+	}
+
+	if c.Score < p.MinScore {
+		reasons = append(reasons, PasswordTooWeak)
+	}
+
+	password := strings.ToLower(c.Password)
+	if containsPersonalInfo(password, c.Username) {
+		reasons = append(reasons, PasswordContainsUsername)
+	}
+
+	localPart, _, _ := strings.Cut(c.Email, "@")
+	if containsPersonalInfo(password, c.Email) || containsPersonalInfo(password, localPart) {
+		reasons = append(reasons, PasswordContainsEmail)
+	}
+
+	if c.Breached {
+		reasons = append(reasons, PasswordBreached)
+	}
+
+	if len(reasons) > 0 {
+		return &e.PasswordPolicyError{Reasons: reasons}
+	}
+
+	return nil
+}
+
+// TooLong проверяет только длину. Слишком длинный пароль отклоняется до оценки
+// стойкости и проверки утечек: их стоимость растёт с длиной пароля
+func (p PasswordPolicy) TooLong(password string) bool {
+	return p.MaxLength > 0 && utf8.RuneCountInString(password) > p.MaxLength
+}
+
+func containsPersonalInfo(password, value string) bool {
+	value = strings.ToLower(value)
+	return utf8.RuneCountInString(value) >= minPersonalInfoLength && strings.Contains(password, value)
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"my_blog_backend/pkg/e"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8, MaxLength: 16, MinScore: 3}
+
+	tests := []struct {
+		name      string
+		candidate PasswordCandidate
+		want      []string
+	}{
+		{
+			name:      "acceptable",
+			candidate: PasswordCandidate{Password: "x7#Kq9!mZ2", Username: "ivan", Email: "ivan@example.com", Score: 4},
+		},
+		{
+			name:      "too short",
+			candidate: PasswordCandidate{Password: "x7#Kq9", Score: 4},
+			want:      []string{PasswordTooShort},
+		},
+		{
+			name:      "too long",
+			candidate: PasswordCandidate{Password: strings.Repeat("x7#Kq9!m", 3), Score: 4},
+			want:      []string{PasswordTooLong},
+		},
+		{
+			// Длина считается в символах, а не в байтах
+			name:      "multibyte at max length",
+			candidate: PasswordCandidate{Password: strings.Repeat("я", 16), Score: 4},
+		},
+		{
+			name:      "too weak",
+			candidate: PasswordCandidate{Password: "x7#Kq9!mZ2", Score: 2},
+			want:      []string{PasswordTooWeak},
+		},
+		{
+			name:      "contains username in another case",
+			candidate: PasswordCandidate{Password: "IvanPetrov#91", Username: "ivanpetrov", Score: 4},
+			want:      []string{PasswordContainsUsername},
+		},
+		{
+			name:      "contains email local part",
+			candidate: PasswordCandidate{Password: "petrov#91xq", Email: "petrov@example.com", Score: 4},
+			want:      []string{PasswordContainsEmail},
+		},
+		{
+			name:      "short username is ignored",
+			candidate: PasswordCandidate{Password: "x7#Kq9!mZ2", Username: "x7", Score: 4},
+		},
+		{
+			name:      "breached",
+			candidate: PasswordCandidate{Password: "x7#Kq9!mZ2", Score: 4, Breached: true},
+			want:      []string{PasswordBreached},
+		},
+		{
+			name:      "all violations are reported",
+			candidate: PasswordCandidate{Password: "ivan1", Username: "ivan", Score: 0, Breached: true},
+			want:      []string{PasswordTooShort, PasswordTooWeak, PasswordContainsUsername, PasswordBreached},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.candidate)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Check() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var policyErr *e.PasswordPolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("Check() error = %v, want *e.PasswordPolicyError", err)
+			}
+			if !errors.Is(err, e.ErrPasswordRejected) {
+				t.Errorf("Check() error does not match e.ErrPasswordRejected")
+			}
+			if !slices.Equal(policyErr.Reasons, tt.want) {
+				t.Errorf("Reasons = %v, want %v", policyErr.Reasons, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"time"
+)
+
+// PasswordResetToken - одноразовый токен сброса пароля. Хранится только хэш,
+// сам токен уходит пользователю в письме
+type PasswordResetToken struct {
+	ID        uint
+	UserID    uint
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+func NewPasswordResetToken(userID uint, tokenHash string, expiresAt time.Time) *PasswordResetToken {
+	return &PasswordResetToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (t *PasswordResetToken) ValidateState(now time.Time) error {
+	if t.UsedAt != nil || !now.Before(t.ExpiresAt) {
+		return e.ErrPasswordResetTokenInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "my_blog_backend/pkg/e"
+
+type Role string
+
+const (
+	RoleAdmin     Role = "admin"
+	RoleEditor    Role = "editor"
+	RoleModerator Role = "moderator"
+	RoleUser      Role = "user"
+)
+
+// Permission - действие над чужими данными. Со своими статьями и комментариями
+// пользователь работает без отдельных прав
+type Permission string
+
+const (
+	PermCategoryManage  Permission = "category.manage"
+	PermArticleEditAny  Permission = "article.edit_any"
+	PermCommentModerate Permission = "comment.moderate"
+	PermUserManageRoles Permission = "user.manage_roles"
+	PermUserManage      Permission = "user.manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermCategoryManage,
+		PermArticleEditAny,
+		PermCommentModerate,
+		PermUserManageRoles,
+		PermUserManage,
+	},
+	RoleEditor: {
+		PermCategoryManage,
+		PermArticleEditAny,
+		PermCommentModerate,
+	},
+	RoleModerator: {
+		PermCommentModerate,
+	},
+	RoleUser: {},
+}
+
+func ParseRole(value string) (Role, error) {
+	role := Role(value)
+	if _, ok := rolePermissions[role]; !ok {
+		return "", e.ErrInvalidRole
+	}
+
+	return role, nil
+}
+
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Role) Permissions() []Permission {
+	return append([]Permission(nil), rolePermissions[r]...)
+}
+
+// Roles возвращает роли в порядке убывания прав
+func Roles() []Role {
+	return []Role{RoleAdmin, RoleEditor, RoleModerator, RoleUser}
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxDisplayNameLength = 64
+	MaxBioLength         = 1000
+	MaxLocationLength    = 64
+	MaxProfileURLLength  = 2048
+	MaxProfileLinks      = 5
+	MaxLinkLabelLength   = 32
+)
+
+// Profile - публичная информация о пользователе, все поля необязательны
+type Profile struct {
+	DisplayName string
+	Bio         string
+	AvatarURL   string
+	Website     string
+	Location    string
+	Links       []ProfileLink
+}
+
+// ProfileLink - ссылка на профиль в другой сети, Label задаёт пользователь ("GitHub", "Mastodon")
+type ProfileLink struct {
+	Label string
+	URL   string
+}
+
+// AuthorStats считается только по опубликованным статьям
+type AuthorStats struct {
+	ArticleCount     int
+	FirstPublishedAt *time.Time
+	// Категория, в которой у автора больше всего статей
+	TopCategory *Category
+}
+
+// ChangeProfile заменяет профиль целиком, пробелы по краям полей отбрасываются
+func (u *User) ChangeProfile(profile Profile) error {
+	profile.normalize()
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	u.Profile = profile
+	return nil
+}
+
+func (p *Profile) Validate() error {
+	if utf8.RuneCountInString(p.DisplayName) > MaxDisplayNameLength ||
+		utf8.RuneCountInString(p.Bio) > MaxBioLength ||
+		utf8.RuneCountInString(p.Location) > MaxLocationLength {
+		return e.ErrProfileFieldTooLong
+	}
+
+	for _, link := range []string{p.AvatarURL, p.Website} {
+		if link != "" && !isProfileURL(link) {
+			return e.ErrProfileURLInvalid
+		}
+	}
+
+	if len(p.Links) > MaxProfileLinks {
+		return e.ErrProfileTooManyLinks
+	}
+
+	for _, link := range p.Links {
+		if link.Label == "" {
+			return e.ErrProfileLinkLabelEmpty
+		}
+
+		if utf8.RuneCountInString(link.Label) > MaxLinkLabelLength {
+			return e.ErrProfileFieldTooLong
+		}
+
+		if !isProfileURL(link.URL) {
+			return e.ErrProfileURLInvalid
+		}
+	}
+
+	return nil
+}
+
+func (p *Profile) normalize() {
+	p.DisplayName = strings.TrimSpace(p.DisplayName)
+	p.Bio = strings.TrimSpace(p.Bio)
+	p.AvatarURL = strings.TrimSpace(p.AvatarURL)
+	p.Website = strings.TrimSpace(p.Website)
+	p.Location = strings.TrimSpace(p.Location)
+	for i := range p.Links {
+		p.Links[i].Label = strings.TrimSpace(p.Links[i].Label)
+		p.Links[i].URL = strings.TrimSpace(p.Links[i].URL)
+	}
+}
+
+// Ссылки из профиля попадают в href на фронтенде, поэтому допускаются только http(s)
+func isProfileURL(raw string) bool {
+	if len(raw) > MaxProfileURLLength {
+		return false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// ArticleRevision - снимок заголовка, содержимого и категории статьи после очередной правки
+type ArticleRevision struct {
+	ID        uint
+	CreatedAt time.Time
+	ArticleID uint
+	Number    int
+	Title     string
+	Content   string
+	// CategoryID равен 0, если категория с тех пор удалена
+	CategoryID uint
+	// EditorID равен 0, если редактор удалил аккаунт
+	EditorID uint
+	Editor   *User
+}
+
+func NewArticleRevision(article *Article, editorId uint) *ArticleRevision {
+	return &ArticleRevision{
+		ArticleID:  article.ID,
+		Title:      article.Title,
+		Content:    article.Content,
+		CategoryID: article.CategoryID,
+		EditorID:   editorId,
+	}
+}
+
+func (r *ArticleRevision) SameContent(other *ArticleRevision) bool {
+	return r.Title == other.Title && r.Content == other.Content && r.CategoryID == other.CategoryID
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArticleRevisionSameContent(t *testing.T) {
+	original := Article{ID: 1, Title: "Title", Content: "Content", CategoryID: 1, Tags: []Tag{{ID: 1, Name: "go"}}}
+	publishAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		edit     func(*Article)
+		wantSame bool
+	}{
+		{name: "tags only", edit: func(a *Article) { a.Tags = []Tag{{ID: 2, Name: "rust"}} }, wantSame: true},
+		{name: "publish date only", edit: func(a *Article) { a.PublishAt = &publishAt }, wantSame: true},
+		{name: "title", edit: func(a *Article) { a.Title = "New title" }},
+		{name: "content", edit: func(a *Article) { a.Content = "New content" }},
+		{name: "category", edit: func(a *Article) { a.CategoryID = 2 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edited := original
+			tt.edit(&edited)
+
+			previous := NewArticleRevision(&original, 1)
+			// Ревизию может создать другой редактор, на сравнение это не влияет
+			next := NewArticleRevision(&edited, 2)
+			if got := previous.SameContent(next); got != tt.wantSame {
+				t.Errorf("SameContent() = %v, want %v", got, tt.wantSame)
+			}
+		})
+	}
+}
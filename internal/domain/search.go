@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"my_blog_backend/pkg/e"
+	"strconv"
+	"strings"
+)
+
+type ArticleSearch struct {
+	Query      string
+	CategoryID *uint
+	AuthorID   *uint
+}
+
+type ArticleSearchHit struct {
+	Article Article
+	Rank    float32
+	Snippet string
+}
+
+// Результаты поиска упорядочены по релевантности, поэтому курсор хранит ранг, а не дату
+type SearchCursor struct {
+	Rank float32
+	ID   uint
+}
+
+type SearchPage struct {
+	Limit int
+	After *SearchCursor
+}
+
+func NewSearchPage(limit int, cursor string) (SearchPage, error) {
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	page := SearchPage{Limit: limit}
+	if cursor == "" {
+		return page, nil
+	}
+
+	after, err := DecodeSearchCursor(cursor)
+	if err != nil {
+		return SearchPage{}, err
+	}
+
+	page.After = after
+	return page, nil
+}
+
+func (c *SearchCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", math.Float32bits(c.Rank), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeSearchCursor(cursor string) (*SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, e.ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 2 {
+		return nil, e.ErrInvalidCursor
+	}
+
+	bits, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, e.ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || id == 0 {
+		return nil, e.ErrInvalidCursor
+	}
+
+	return &SearchCursor{
+		Rank: math.Float32frombits(uint32(bits)),
+		ID:   uint(id),
+	}, nil
+}
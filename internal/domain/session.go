@@ -2,31 +2,94 @@ package domain
 
 import (
 	"my_blog_backend/pkg/e"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
+const maxUserAgentLength = 512
+
+// SessionRevokeReason - почему сессия отозвана
+type SessionRevokeReason string
+
+const (
+	// Refresh токен обменян на новую сессию того же семейства
+	SessionRotated   SessionRevokeReason = "rotated"
+	SessionLoggedOut SessionRevokeReason = "logout"
+	// Отозвана пользователем, администратором или при смене пароля
+	SessionRevoked SessionRevokeReason = "revoked"
+	// Отозвана вместе с семейством после повторного использования токена
+	SessionFamilyRevoked SessionRevokeReason = "reuse"
+)
+
+// Сессии, полученные ротацией refresh токена, образуют семейство с общим FamilyId.
+// Повторное предъявление уже ротированного токена означает его кражу,
+// и тогда отзывается всё семейство
 type Session struct {
 	Id               uuid.UUID
+	FamilyId         uuid.UUID
 	UserId           uint
 	RefreshTokenHash string
 	IsRevoked        bool
+	RevokeReason     SessionRevokeReason
+	UserAgent        string
+	IP               string
 	CreatedAt        time.Time
+	LastUsedAt       time.Time
 	ExpiresAt        time.Time
 }
 
-func NewSession(userID uint, refreshTokenHash string, expiresAt time.Time) *Session {
+func NewSession(userID uint, refreshTokenHash string, expiresAt time.Time, userAgent, ip string) *Session {
+	now := time.Now().UTC()
+
+	id := uuid.New()
 	return &Session{
-		Id:               uuid.New(),
+		Id:               id,
+		FamilyId:         id,
 		UserId:           userID,
 		RefreshTokenHash: refreshTokenHash,
 		IsRevoked:        false,
-		CreatedAt:        time.Now().UTC(),
+		UserAgent:        truncateUserAgent(userAgent),
+		IP:               ip,
+		CreatedAt:        now,
+		LastUsedAt:       now,
 		ExpiresAt:        expiresAt,
 	}
 }
 
+// ContinueFamily делает сессию продолжением ротированной сессии prev. Время входа
+// наследуется от семейства, а LastUsedAt остаётся временем ротации, то есть последнего
+// использования refresh токена
+func (s *Session) ContinueFamily(prev *Session) {
+	s.FamilyId = prev.FamilyId
+	s.CreatedAt = prev.CreatedAt
+}
+
+// truncateUserAgent обрезает User-Agent по границе символа. Невалидный UTF-8
+// выбрасывается заранее: Postgres не примет его в text-колонку
+func truncateUserAgent(userAgent string) string {
+	userAgent = strings.ToValidUTF8(userAgent, "")
+	if len(userAgent) <= maxUserAgentLength {
+		return userAgent
+	}
+
+	cut := maxUserAgentLength
+	for cut > 0 && !utf8.RuneStart(userAgent[cut]) {
+		cut--
+	}
+
+	return userAgent[:cut]
+}
+
+// WasRotated сообщает, что токен сессии уже обменян на новый. Только такой токен,
+// предъявленный повторно, считается украденным: токен после выхода или отзыва
+// сессии клиент мог просто отправить ещё раз
+func (s *Session) WasRotated() bool {
+	return s.IsRevoked && s.RevokeReason == SessionRotated
+}
+
 func (s *Session) ValidateState() error {
 	// Отозвана ли сессия?
 	if s.IsRevoked {
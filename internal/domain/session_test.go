@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestNewSessionTruncatesUserAgentOnRuneBoundary(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		wantLen   int
+	}{
+		{"short", "curl/8.0", len("curl/8.0")},
+		{"ascii over limit", strings.Repeat("a", maxUserAgentLength+10), maxUserAgentLength},
+		// 'я' занимает два байта, граница 512 попадает на начало символа
+		{"two-byte runes", strings.Repeat("я", maxUserAgentLength), maxUserAgentLength},
+		// Префикс сдвигает символы на байт, и граница 512 попадает в середину символа
+		{"split rune", "a" + strings.Repeat("я", maxUserAgentLength), maxUserAgentLength - 1},
+		{"invalid utf8", "agent\xff\xfe", len("agent")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSession(1, "hash", time.Now().Add(time.Hour), tt.userAgent, "127.0.0.1")
+
+			if !utf8.ValidString(s.UserAgent) {
+				t.Fatalf("UserAgent %q is not valid UTF-8", s.UserAgent)
+			}
+			if len(s.UserAgent) != tt.wantLen {
+				t.Errorf("len(UserAgent) = %d, want %d", len(s.UserAgent), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestContinueFamilyKeepsLoginTime(t *testing.T) {
+	prev := NewSession(1, "old", time.Now().Add(time.Hour), "ua", "ip")
+	prev.CreatedAt = prev.CreatedAt.Add(-24 * time.Hour)
+
+	next := NewSession(1, "new", time.Now().Add(time.Hour), "ua", "ip")
+	next.ContinueFamily(prev)
+
+	if next.FamilyId != prev.FamilyId {
+		t.Errorf("FamilyId = %s, want %s", next.FamilyId, prev.FamilyId)
+	}
+	if !next.CreatedAt.Equal(prev.CreatedAt) {
+		t.Errorf("CreatedAt = %s, want %s", next.CreatedAt, prev.CreatedAt)
+	}
+	if !next.LastUsedAt.After(prev.CreatedAt) {
+		t.Errorf("LastUsedAt = %s, want the rotation time", next.LastUsedAt)
+	}
+}
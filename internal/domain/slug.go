@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	MaxArticleSlugLength = 100
+	defaultArticleSlug   = "article"
+)
+
+// Транслитерация по упрощённой схеме загранпаспорта, чтобы slug читался без словаря
+var cyrillicTranslit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// Slugify строит из заголовка slug вида [a-z0-9-]. Символы, для которых нет
+// транслитерации, выбрасываются
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case cyrillicTranslit[r] != "":
+			b.WriteString(cyrillicTranslit[r])
+			dash = false
+		case r == 'ъ' || r == 'ь' || r == '\'' || r == '’':
+		default:
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	slug := truncateSlug(b.String(), MaxArticleSlugLength)
+	if slug == "" {
+		return defaultArticleSlug
+	}
+
+	return slug
+}
+
+// UniqueSlug возвращает base или первый свободный вариант base-2, base-3...
+func UniqueSlug(base string, taken []string) string {
+	busy := make(map[string]struct{}, len(taken))
+	for _, slug := range taken {
+		busy[slug] = struct{}{}
+	}
+
+	if _, ok := busy[base]; !ok {
+		return base
+	}
+
+	for n := 2; ; n++ {
+		suffix := "-" + strconv.Itoa(n)
+		candidate := truncateSlug(base, MaxArticleSlugLength-len(suffix)) + suffix
+		if _, ok := busy[candidate]; !ok {
+			return candidate
+		}
+	}
+}
+
+func truncateSlug(slug string, max int) string {
+	if len(slug) > max {
+		slug = slug[:max]
+		if i := strings.LastIndexByte(slug, '-'); i > max/2 {
+			slug = slug[:i]
+		}
+	}
+
+	return strings.Trim(slug, "-")
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "latin", title: "Hello, World!", want: "hello-world"},
+		{name: "cyrillic", title: "Привет, мир", want: "privet-mir"},
+		{name: "multi-letter transliteration in upper case", title: "Щука и ЁЖ", want: "shchuka-i-yozh"},
+		{name: "hard and soft signs dropped", title: "Объявление", want: "obyavlenie"},
+		{name: "ukrainian letters", title: "Їжак і ґанок", want: "yizhak-i-ganok"},
+		{name: "apostrophes dropped", title: "Don't stop", want: "dont-stop"},
+		{name: "typographic apostrophe dropped", title: "It’s here", want: "its-here"},
+		{name: "separators collapsed and trimmed", title: "  --Go   1.22--  ", want: "go-1-22"},
+		{name: "letters without transliteration", title: "Café au lait", want: "caf-au-lait"},
+		{name: "empty", title: "", want: defaultArticleSlug},
+		{name: "symbols only", title: "!!!", want: defaultArticleSlug},
+		{name: "no transliteration at all", title: "日本語", want: defaultArticleSlug},
+		{
+			name:  "cut at the last dash",
+			title: strings.Repeat("word ", 30),
+			want:  strings.TrimSuffix(strings.Repeat("word-", 20), "-"),
+		},
+		{
+			name:  "cut without a dash in the second half",
+			title: "a" + strings.Repeat("b", 120),
+			want:  "a" + strings.Repeat("b", MaxArticleSlugLength-1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.title); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	long := strings.Repeat("a", MaxArticleSlugLength)
+
+	tests := []struct {
+		name  string
+		base  string
+		taken []string
+		want  string
+	}{
+		{name: "free", base: "go", taken: []string{"rust"}, want: "go"},
+		{name: "first suffix", base: "go", taken: []string{"go"}, want: "go-2"},
+		{name: "next free suffix", base: "go", taken: []string{"go", "go-2", "go-3"}, want: "go-4"},
+		{name: "suffix fits max length", base: long, taken: []string{long}, want: long[:MaxArticleSlugLength-2] + "-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UniqueSlug(tt.base, tt.taken); got != tt.want {
+				t.Errorf("UniqueSlug() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// migrationSlugify повторяет SQL из миграции 000012 с заменами, взятыми из самого
+// файла миграции. lower() считается работающим в UTF-8 локали
+type migrationSlugify struct {
+	// Замены из цепочки replace() и translate(): символ -> строка, "" - удалить
+	replacements map[rune]string
+	// Срезается ли дефис в начале до обрезки по длине
+	trimLeadingDash bool
+}
+
+func loadMigrationSlugify(t *testing.T) *migrationSlugify {
+	t.Helper()
+
+	sql, err := os.ReadFile("../../db/migrations/000012_add_articles_slug.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &migrationSlugify{
+		replacements:    make(map[rune]string),
+		trimLeadingDash: strings.Contains(string(sql), "ltrim(regexp_replace("),
+	}
+
+	// replace(..., 'щ', 'shch') и replace(..., '''', '')
+	for _, match := range regexp.MustCompile(`[,\s]\s*'(''|’|\p{Cyrillic})', '([a-z]*)'\)`).FindAllStringSubmatch(string(sql), -1) {
+		from := strings.ReplaceAll(match[1], "''", "'")
+		m.replacements[[]rune(from)[0]] = match[2]
+	}
+
+	// translate(..., 'абв...', 'abv...'): символы без пары удаляются
+	translate := regexp.MustCompile(`'(\p{Cyrillic}{2,})', '([a-z]+)'\)`).FindStringSubmatch(string(sql))
+	if translate == nil {
+		t.Fatal("translate() not found in migration 000012")
+	}
+	from, to := []rune(translate[1]), []rune(translate[2])
+	for i, r := range from {
+		m.replacements[r] = ""
+		if i < len(to) {
+			m.replacements[r] = string(to[i])
+		}
+	}
+
+	return m
+}
+
+func (m *migrationSlugify) slugify(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if replacement, ok := m.replacements[r]; ok {
+			b.WriteString(replacement)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	slug := regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(b.String(), "-")
+	if m.trimLeadingDash {
+		slug = strings.TrimLeft(slug, "-")
+	}
+
+	if len(slug) > MaxArticleSlugLength {
+		slug = slug[:MaxArticleSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > MaxArticleSlugLength/2 {
+			slug = slug[:i]
+		}
+	}
+
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return defaultArticleSlug
+	}
+
+	return slug
+}
+
+func TestSlugifyMatchesMigration(t *testing.T) {
+	m := loadMigrationSlugify(t)
+
+	// Каждая буква из cyrillicTranslit, плюс знаки, которые Slugify удаляет
+	want := make(map[rune]string, len(cyrillicTranslit)+2)
+	for r, translit := range cyrillicTranslit {
+		want[r] = translit
+	}
+	want['\''] = ""
+	want['’'] = ""
+
+	for r, translit := range want {
+		got, ok := m.replacements[r]
+		if !ok {
+			t.Errorf("migration has no replacement for %q", r)
+			continue
+		}
+		if got != translit {
+			t.Errorf("migration replaces %q with %q, Slugify with %q", r, got, translit)
+		}
+	}
+	for r := range m.replacements {
+		if _, ok := want[r]; !ok {
+			t.Errorf("migration replaces %q, Slugify does not", r)
+		}
+	}
+
+	titles := []string{
+		"Hello, World!",
+		"Привет, мир",
+		"Щука и ЁЖ",
+		"Объявление",
+		"Їжак і ґанок",
+		"Don't stop",
+		"It’s here",
+		"  --Go   1.22--  ",
+		"Café au lait",
+		"",
+		"!!!",
+		"日本語",
+		strings.Repeat("word ", 30),
+		"— " + strings.Repeat("слово ", 20),
+		// Ровно MaxArticleSlugLength символов без ведущего дефиса
+		"— " + strings.Repeat("a", 49) + " " + strings.Repeat("b", 50),
+		"a" + strings.Repeat("b", 120),
+	}
+	for _, title := range titles {
+		if got, want := m.slugify(title), Slugify(title); got != want {
+			t.Errorf("migration slug for %q = %q, Slugify = %q", title, got, want)
+		}
+	}
+}
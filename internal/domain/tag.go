@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	MaxTagsPerArticle = 10
+	maxTagSlugLength  = 64
+)
+
+type Tag struct {
+	ID        uint
+	CreatedAt time.Time
+	Name      string
+	Slug      string
+}
+
+// Синонимы сводятся к одному тегу, чтобы "Go" и "golang" не расползались по разным страницам
+var tagAliases = map[string]string{
+	"golang":   "go",
+	"js":       "javascript",
+	"ts":       "typescript",
+	"postgres": "postgresql",
+	"k8s":      "kubernetes",
+	"py":       "python",
+	"python3":  "python",
+	"csharp":   "c-sharp",
+	"c#":       "c-sharp",
+	"cpp":      "c-plus-plus",
+	"c++":      "c-plus-plus",
+	"reactjs":  "react",
+	"nodejs":   "node",
+	"node-js":  "node",
+	"vuejs":    "vue",
+}
+
+func NewTag(name string) (*Tag, error) {
+	name = strings.TrimSpace(name)
+	slug := NormalizeTagSlug(name)
+	if slug == "" {
+		return nil, e.ErrTagInvalid
+	}
+
+	return &Tag{
+		Name: name,
+		Slug: slug,
+	}, nil
+}
+
+// NewTags нормализует список тегов и убирает дубликаты, сохраняя порядок
+func NewTags(names []string) ([]*Tag, error) {
+	tags := make([]*Tag, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		tag, err := NewTag(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[tag.Slug]; ok {
+			continue
+		}
+
+		seen[tag.Slug] = struct{}{}
+		tags = append(tags, tag)
+	}
+
+	if len(tags) > MaxTagsPerArticle {
+		return nil, e.ErrTooManyTags
+	}
+
+	return tags, nil
+}
+
+func NormalizeTagSlug(name string) string {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := tagAliases[lower]; ok {
+		return alias
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range lower {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case r == '+':
+			b.WriteString("-plus")
+			dash = false
+		case r == '#':
+			b.WriteString("-sharp")
+			dash = false
+		default:
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if alias, ok := tagAliases[slug]; ok {
+		return alias
+	}
+
+	if runes := []rune(slug); len(runes) > maxTagSlugLength {
+		slug = strings.Trim(string(runes[:maxTagSlugLength]), "-")
+	}
+
+	return slug
+}
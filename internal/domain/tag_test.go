@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"my_blog_backend/pkg/e"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNormalizeTagSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "lower case", in: "Go", want: "go"},
+		{name: "alias", in: "golang", want: "go"},
+		{name: "alias in mixed case", in: "GoLang", want: "go"},
+		{name: "alias with surrounding spaces", in: " GOLANG ", want: "go"},
+		{name: "alias after punctuation", in: "Node.js", want: "node"},
+		{name: "alias after spaces", in: "node js", want: "node"},
+		{name: "plus signs", in: "C++", want: "c-plus-plus"},
+		{name: "sharp sign", in: "C#", want: "c-sharp"},
+		{name: "inner whitespace collapsed", in: "  Machine   Learning ", want: "machine-learning"},
+		{name: "edge dashes trimmed", in: "--rust--", want: "rust"},
+		{name: "digits kept", in: "Go 1.22", want: "go-1-22"},
+		{name: "cyrillic kept", in: "Привет Мир", want: "привет-мир"},
+		{name: "symbols only", in: "!!!", want: ""},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTagSlug(tt.in); got != tt.want {
+				t.Errorf("NormalizeTagSlug(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// Столбец tags.slug - VARCHAR(64), Postgres считает длину в символах
+func TestNormalizeTagSlugLength(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "exactly max length", in: strings.Repeat("a", maxTagSlugLength), want: strings.Repeat("a", maxTagSlugLength)},
+		{name: "multibyte runes are counted as characters", in: strings.Repeat("я", maxTagSlugLength+6), want: strings.Repeat("я", maxTagSlugLength)},
+		{name: "dash at the cut is trimmed", in: strings.Repeat("a", maxTagSlugLength-1) + " bcc", want: strings.Repeat("a", maxTagSlugLength-1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeTagSlug(tt.in)
+			if got != tt.want {
+				t.Errorf("NormalizeTagSlug() = %q, want %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > maxTagSlugLength {
+				t.Errorf("slug has %d characters, max %d", n, maxTagSlugLength)
+			}
+		})
+	}
+}
+
+func TestNewTagsMergesAliases(t *testing.T) {
+	tags, err := NewTags([]string{"Go", "golang", "GoLang", "Rust"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tags) != 2 || tags[0].Slug != "go" || tags[1].Slug != "rust" {
+		t.Fatalf("NewTags() = %v, want tags go and rust", tags)
+	}
+	// Имя берётся у первого упоминания
+	if tags[0].Name != "Go" {
+		t.Errorf("Name = %q, want %q", tags[0].Name, "Go")
+	}
+
+	if _, err := NewTags([]string{"go", "!!!"}); !errors.Is(err, e.ErrTagInvalid) {
+		t.Errorf("NewTags() with a symbol-only tag error = %v, want %v", err, e.ErrTagInvalid)
+	}
+}
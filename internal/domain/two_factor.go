@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"my_blog_backend/pkg/e"
+	"time"
+)
+
+// RecoveryCode - одноразовый код для входа без приложения-аутентификатора.
+// Хранится только хэш, сами коды показываются пользователю один раз
+type RecoveryCode struct {
+	ID        uint
+	UserID    uint
+	CodeHash  string
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+func NewRecoveryCode(userID uint, codeHash string) *RecoveryCode {
+	return &RecoveryCode{
+		UserID:   userID,
+		CodeHash: codeHash,
+	}
+}
+
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// StartTOTPEnrollment сохраняет новый секрет. 2FA включится только после
+// подтверждения кодом из приложения, повторный вызов заменяет секрет
+func (u *User) StartTOTPEnrollment(secret string) error {
+	if u.IsTwoFactorEnabled() {
+		return e.ErrTwoFactorAlreadyEnabled
+	}
+
+	u.TOTPSecret = &secret
+	return nil
+}
+
+func (u *User) EnableTOTP(now time.Time) error {
+	if u.IsTwoFactorEnabled() {
+		return e.ErrTwoFactorAlreadyEnabled
+	}
+
+	if u.TOTPSecret == nil {
+		return e.ErrTwoFactorNotEnrolled
+	}
+
+	u.TOTPEnabledAt = &now
+	return nil
+}
+
+func (u *User) DisableTOTP() error {
+	if !u.IsTwoFactorEnabled() {
+		return e.ErrTwoFactorNotEnabled
+	}
+
+	u.TOTPSecret = nil
+	u.TOTPEnabledAt = nil
+	return nil
+}
@@ -14,14 +14,30 @@ type User struct {
 	Username     string
 	Email        string
 	PasswordHash string
+	// EmailVerifiedAt относится к Email. PendingEmail - новый адрес, ожидающий подтверждения
+	EmailVerifiedAt *time.Time
+	PendingEmail    *string
+	// TOTPSecret задан с начала подключения 2FA, TOTPEnabledAt - после подтверждения кодом
+	TOTPSecret    *string
+	TOTPEnabledAt *time.Time
+	// Заблокированный администратором пользователь не может войти
+	SuspendedAt *time.Time
+	// Момент, после которого аккаунт будет удалён, и судьба его статей
+	DeletionScheduledAt *time.Time
+	DeletionArticles    ArticleDisposal
+	Profile             Profile
+	// Служебный аккаунт из миграции, под ним нельзя войти
+	IsSystem bool
 }
 
-type Role string
-
-const (
-	RoleAdmin Role = "admin"
-	RoleUser  Role = "user"
-)
+// UserListFilter - условия выборки пользователей для администратора, пустые поля не ограничивают
+type UserListFilter struct {
+	Role           *Role
+	UsernamePrefix string
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	Suspended      *bool
+}
 
 func NewUser(username, email, passwordHash string) *User {
 	return &User{
@@ -52,19 +68,79 @@ func (u *User) ChangeUsername(newUsername string) error {
 	return nil
 }
 
+// ChangeEmail не меняет адрес сразу: новый email становится рабочим только после подтверждения
 func (u *User) ChangeEmail(newEmail string) error {
 	if u.Email == newEmail {
 		return e.ErrEmailIsSame
 	}
 
-	u.Email = newEmail
+	u.PendingEmail = &newEmail
 	return nil
 }
 
-func (u *User) SetAdminRole() error {
-	if u.Role == RoleAdmin {
-		return e.ErrUserAlreadyAdmin
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// EmailToVerify - адрес, который ждёт подтверждения
+func (u *User) EmailToVerify() (string, error) {
+	if u.PendingEmail != nil {
+		return *u.PendingEmail, nil
+	}
+
+	if !u.IsEmailVerified() {
+		return u.Email, nil
+	}
+
+	return "", e.ErrEmailAlreadyVerified
+}
+
+// ConfirmEmail подтверждает email, на который был выдан токен
+func (u *User) ConfirmEmail(email string, now time.Time) error {
+	switch {
+	case u.PendingEmail != nil && *u.PendingEmail == email:
+		u.Email = email
+		u.PendingEmail = nil
+	case u.PendingEmail == nil && u.Email == email && !u.IsEmailVerified():
+	default:
+		return e.ErrEmailVerificationTokenInvalid
 	}
-	u.Role = RoleAdmin
+
+	u.EmailVerifiedAt = &now
 	return nil
 }
+
+func (u *User) ChangeRole(role Role) error {
+	if u.Role == role {
+		return e.ErrRoleIsSame
+	}
+
+	u.Role = role
+	return nil
+}
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+func (u *User) Suspend(now time.Time) error {
+	if u.IsSuspended() {
+		return e.ErrUserAlreadySuspended
+	}
+
+	u.SuspendedAt = &now
+	return nil
+}
+
+func (u *User) Unsuspend() error {
+	if !u.IsSuspended() {
+		return e.ErrUserNotSuspended
+	}
+
+	u.SuspendedAt = nil
+	return nil
+}
+
+func (u *User) Can(permission Permission) bool {
+	return u.Role.Can(permission)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"my_blog_backend/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -12,20 +13,46 @@ type UserRepository interface {
 	GetById(ctx context.Context, id uint) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
+	// GetGhost возвращает служебного автора обезличенных статей
+	GetGhost(ctx context.Context) (*domain.User, error)
+	// Update сохраняет учётные данные и профиль. Остальные поля меняются методами Set*
 	Update(ctx context.Context, user *domain.User) (*domain.User, error)
+	SetRole(ctx context.Context, id uint, role domain.Role) (*domain.User, error)
+	SetPasswordHash(ctx context.Context, id uint, hash string) error
+	SetTOTP(ctx context.Context, id uint, secret *string, enabledAt *time.Time) error
+	SetSuspendedAt(ctx context.Context, id uint, suspendedAt *time.Time) (*domain.User, error)
+	SetDeletionSchedule(ctx context.Context, id uint, scheduledAt *time.Time, articles domain.ArticleDisposal) (*domain.User, error)
 	Delete(ctx context.Context, id uint) error
+	DeleteWithArticles(ctx context.Context, id uint, reassignTo *uint) error
+	List(ctx context.Context, filter domain.UserListFilter, page domain.Page) ([]domain.User, *domain.Cursor, error)
 	ExistsByEmailOrUsername(ctx context.Context, email, username string) error
+	UseTOTPStep(ctx context.Context, userID uint, step int64) error
+	// ReplacePasswordHash меняет хэш, только если он всё ещё равен oldHash
+	ReplacePasswordHash(ctx context.Context, userID uint, oldHash, newHash string) error
+	ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]domain.User, error)
+	// PurgeScheduled работает как DeleteWithArticles, но в той же транзакции проверяет,
+	// что удаление всё ещё запланировано и срок наступил. Иначе ErrAccountDeletionNotScheduled
+	PurgeScheduled(ctx context.Context, id uint, reassignTo *uint, now time.Time) error
 }
 
 type ArticleRepository interface {
-	Create(ctx context.Context, article *domain.Article) (*domain.Article, error)
+	Create(ctx context.Context, article *domain.Article, tags []*domain.Tag) (*domain.Article, error)
 	GetByID(ctx context.Context, id uint) (*domain.Article, error)
-	Update(ctx context.Context, article *domain.Article) (*domain.Article, error)
+	GetBySlug(ctx context.Context, slug string) (*domain.Article, error)
+	// tags == nil оставляет набор тегов статьи без изменений
+	Update(ctx context.Context, article *domain.Article, editorID uint, tags []*domain.Tag) (*domain.Article, error)
+	UpdateStatus(ctx context.Context, article *domain.Article) (*domain.Article, error)
 	Delete(ctx context.Context, id uint) error
-	ListAll(ctx context.Context) ([]domain.Article, error)
-	ListByAuthor(ctx context.Context, authorID uint) ([]domain.Article, error)
-	ListByCategory(ctx context.Context, categoryID uint) ([]domain.Article, error)
+	// ListAll и ListByCategory возвращают только опубликованные статьи
+	ListAll(ctx context.Context, page domain.Page) ([]domain.Article, *domain.Cursor, error)
+	ListByAuthor(ctx context.Context, authorID uint, onlyPublished bool, page domain.Page) ([]domain.Article, *domain.Cursor, error)
+	AuthorStats(ctx context.Context, authorID uint) (*domain.AuthorStats, error)
+	ListByCategory(ctx context.Context, categoryID uint, page domain.Page) ([]domain.Article, *domain.Cursor, error)
+	ListByTag(ctx context.Context, tagID uint, page domain.Page) ([]domain.Article, *domain.Cursor, error)
 	ExistsByTitleContentAuthor(ctx context.Context, article *domain.Article) error
+	Search(ctx context.Context, search domain.ArticleSearch, page domain.SearchPage) ([]domain.ArticleSearchHit, *domain.SearchCursor, error)
+	ListDueForPublishing(ctx context.Context, now time.Time, limit int) ([]domain.Article, error)
+	PublishScheduled(ctx context.Context, article *domain.Article, scheduledAt, now time.Time) error
 }
 
 type CategoryRepository interface {
@@ -38,10 +65,97 @@ type CategoryRepository interface {
 	ListAll(ctx context.Context) ([]domain.Category, error)
 }
 
+type RevisionRepository interface {
+	ListByArticle(ctx context.Context, articleID uint) ([]domain.ArticleRevision, error)
+	GetByNumber(ctx context.Context, articleID uint, number int) (*domain.ArticleRevision, error)
+}
+
+type TagRepository interface {
+	GetBySlug(ctx context.Context, slug string) (*domain.Tag, error)
+}
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *domain.Comment) (*domain.Comment, error)
+	GetByID(ctx context.Context, id uint) (*domain.Comment, error)
+	Update(ctx context.Context, comment *domain.Comment) (*domain.Comment, error)
+	Delete(ctx context.Context, id uint) error
+	HasReplies(ctx context.Context, id uint) (bool, error)
+	ListByParent(ctx context.Context, articleID uint, parentID *uint, page domain.Page) ([]domain.Comment, *domain.Cursor, error)
+	ListDescendants(ctx context.Context, parentIDs []uint, maxDepth int) ([]domain.Comment, error)
+	ListByAuthor(ctx context.Context, authorID uint) ([]domain.Comment, error)
+}
+
+type FollowRepository interface {
+	Create(ctx context.Context, follow *domain.Follow) error
+	Delete(ctx context.Context, followerID, followeeID uint) error
+	ListFollowers(ctx context.Context, userID uint, page domain.Page) ([]domain.Follow, *domain.Cursor, error)
+	ListFollowing(ctx context.Context, userID uint, page domain.Page) ([]domain.Follow, *domain.Cursor, error)
+}
+
+type CategorySubscriptionRepository interface {
+	Create(ctx context.Context, subscription *domain.CategorySubscription) error
+	Delete(ctx context.Context, userID, categoryID uint) error
+	ListByUser(ctx context.Context, userID uint) ([]domain.CategorySubscription, error)
+}
+
+// FeedRepository собирает ленту пользователя из подписок на авторов и категории
+type FeedRepository interface {
+	List(ctx context.Context, userID uint, page domain.Page) ([]domain.Article, *domain.Cursor, error)
+}
+
 type SessionRepository interface {
 	Create(ctx context.Context, session *domain.Session) (*domain.Session, error)
-	GetByID(ctx context.Context, id uint) (*domain.Session, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Session, error)
 	GetByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (*domain.Session, error)
-	RevokeSession(ctx context.Context, id uuid.UUID) error
+	ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]domain.Session, error)
+	ListByUser(ctx context.Context, userID uint) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, id uuid.UUID, reason domain.SessionRevokeReason) error
+	RevokeUserSession(ctx context.Context, userID uint, id uuid.UUID) error
+	RevokeAllByUser(ctx context.Context, userID uint, exceptID uuid.UUID) (int64, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
 	DeleteSession(ctx context.Context, id uuid.UUID) error
 }
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *domain.PasswordResetToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id uint, usedAt time.Time) error
+	InvalidateByUser(ctx context.Context, userID uint, at time.Time) error
+}
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *domain.EmailVerificationToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error)
+	MarkUsed(ctx context.Context, id uint, usedAt time.Time) error
+	InvalidateByUser(ctx context.Context, userID uint, at time.Time) error
+}
+
+type RecoveryCodeRepository interface {
+	// ReplaceByUser удаляет старые коды пользователя и сохраняет новые
+	ReplaceByUser(ctx context.Context, userID uint, codes []*domain.RecoveryCode) error
+	Use(ctx context.Context, userID uint, codeHash string, usedAt time.Time) error
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+type AccessTokenRepository interface {
+	Create(ctx context.Context, token *domain.AccessToken) (*domain.AccessToken, error)
+	GetByID(ctx context.Context, userID, id uint) (*domain.AccessToken, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.AccessToken, error)
+	ListByUser(ctx context.Context, userID uint) ([]domain.AccessToken, error)
+	CountActiveByUser(ctx context.Context, userID uint, now time.Time) (int64, error)
+	Update(ctx context.Context, token *domain.AccessToken) (*domain.AccessToken, error)
+	// TouchLastUsed обновляет время использования не чаще, чем раз в interval
+	TouchLastUsed(ctx context.Context, id uint, now time.Time, interval time.Duration) error
+	Delete(ctx context.Context, userID, id uint) error
+}
+
+type LoginAttemptRepository interface {
+	// TryAttempt атомарно проверяет блокировку по policy и, если её нет, сразу учитывает
+	// попытку как неудачную (см. LockoutPolicy.Attempt), поэтому параллельные попытки
+	// видят уже увеличенный счётчик. При блокировке возвращает время до её конца
+	TryAttempt(ctx context.Context, key string, now time.Time, policy domain.LockoutPolicy) (time.Duration, error)
+	// Forgive снимает с счётчика одну попытку, учтённую TryAttempt, которая оказалась успешной
+	Forgive(ctx context.Context, key string) error
+	Reset(ctx context.Context, keys ...string) error
+	DeleteStale(ctx context.Context, staleBefore time.Time) error
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"sync"
+	"time"
+)
+
+// LoginAttemptRepository хранит счётчики в памяти процесса. Подходит для одного
+// экземпляра сервиса: при нескольких репликах у каждой будут свои счётчики
+type LoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempts
+}
+
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		attempts: make(map[string]domain.LoginAttempts),
+	}
+}
+
+func (r *LoginAttemptRepository) TryAttempt(_ context.Context, key string, now time.Time, policy domain.LockoutPolicy) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.attempts[key]
+	if !ok {
+		attempts = domain.LoginAttempts{Key: key}
+	}
+
+	if retryAfter := policy.Attempt(&attempts, now); retryAfter > 0 {
+		return retryAfter, nil
+	}
+	r.attempts[key] = attempts
+
+	return 0, nil
+}
+
+func (r *LoginAttemptRepository) Forgive(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.attempts[key]
+	if !ok || attempts.Failures == 0 {
+		return nil
+	}
+
+	attempts.Failures--
+	r.attempts[key] = attempts
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) Reset(_ context.Context, keys ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		delete(r.attempts, key)
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) DeleteStale(_ context.Context, staleBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, attempts := range r.attempts {
+		if attempts.LastFailureAt.Before(staleBefore) {
+			delete(r.attempts, key)
+		}
+	}
+
+	return nil
+}
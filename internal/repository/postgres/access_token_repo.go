@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AccessTokenRepository struct {
+	DB *gorm.DB
+}
+
+func NewAccessTokenRepository(db *gorm.DB) *AccessTokenRepository {
+	return &AccessTokenRepository{
+		DB: db,
+	}
+}
+
+func (r *AccessTokenRepository) Create(ctx context.Context, token *domain.AccessToken) (*domain.AccessToken, error) {
+	const op = "AccessTokenRepository.Create"
+	tokenModel := toAccessTokenModel(token)
+	result := r.DB.WithContext(ctx).Create(tokenModel)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toAccessTokenEntity(tokenModel), nil
+}
+
+func (r *AccessTokenRepository) GetByID(ctx context.Context, userID, id uint) (*domain.AccessToken, error) {
+	const op = "AccessTokenRepository.GetByID"
+	var tokenModel AccessTokenModel
+	result := r.DB.WithContext(ctx).First(&tokenModel, "id = ? AND user_id = ?", id, userID)
+	if err := checkGetQueryResult(result, e.ErrAccessTokenNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toAccessTokenEntity(&tokenModel), nil
+}
+
+func (r *AccessTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.AccessToken, error) {
+	const op = "AccessTokenRepository.GetByTokenHash"
+	var tokenModel AccessTokenModel
+	result := r.DB.WithContext(ctx).First(&tokenModel, "token_hash = ?", tokenHash)
+	if err := checkGetQueryResult(result, e.ErrAccessTokenInvalid); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toAccessTokenEntity(&tokenModel), nil
+}
+
+func (r *AccessTokenRepository) ListByUser(ctx context.Context, userID uint) ([]domain.AccessToken, error) {
+	const op = "AccessTokenRepository.ListByUser"
+	var tokenModels []AccessTokenModel
+	result := r.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&tokenModels)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	tokens := make([]domain.AccessToken, len(tokenModels))
+	for i := range tokenModels {
+		tokens[i] = *toAccessTokenEntity(&tokenModels[i])
+	}
+
+	return tokens, nil
+}
+
+// CountActiveByUser не учитывает истёкшие токены: ими уже нельзя воспользоваться
+func (r *AccessTokenRepository) CountActiveByUser(ctx context.Context, userID uint, now time.Time) (int64, error) {
+	const op = "AccessTokenRepository.CountActiveByUser"
+	var count int64
+	result := r.DB.WithContext(ctx).
+		Model(&AccessTokenModel{}).
+		Where("user_id = ? AND expires_at > ?", userID, now).
+		Count(&count)
+	if err := result.Error; err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return count, nil
+}
+
+func (r *AccessTokenRepository) Update(ctx context.Context, token *domain.AccessToken) (*domain.AccessToken, error) {
+	const op = "AccessTokenRepository.Update"
+	result := r.DB.WithContext(ctx).
+		Model(&AccessTokenModel{}).
+		Where("id = ? AND user_id = ?", token.ID, token.UserID).
+		Update("name", token.Name)
+	if err := checkChangeQueryResult(result, e.ErrAccessTokenNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updated, err := r.GetByID(ctx, token.UserID, token.ID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return updated, nil
+}
+
+// TouchLastUsed не пишет в базу на каждый запрос: токен из CI может
+// использоваться десятки раз в минуту
+func (r *AccessTokenRepository) TouchLastUsed(ctx context.Context, id uint, now time.Time, interval time.Duration) error {
+	const op = "AccessTokenRepository.TouchLastUsed"
+	result := r.DB.WithContext(ctx).
+		Model(&AccessTokenModel{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-interval)).
+		Update("last_used_at", now)
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (r *AccessTokenRepository) Delete(ctx context.Context, userID, id uint) error {
+	const op = "AccessTokenRepository.Delete"
+	result := r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&AccessTokenModel{})
+	if err := checkChangeQueryResult(result, e.ErrAccessTokenNotFound); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func toAccessTokenModel(t *domain.AccessToken) *AccessTokenModel {
+	scopes := make([]string, len(t.Scopes))
+	for i, scope := range t.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &AccessTokenModel{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		TokenHash:  t.TokenHash,
+		Scopes:     strings.Join(scopes, " "),
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
+
+func toAccessTokenEntity(t *AccessTokenModel) *domain.AccessToken {
+	fields := strings.Fields(t.Scopes)
+	scopes := make([]domain.Scope, len(fields))
+	for i, field := range fields {
+		scopes[i] = domain.Scope(field)
+	}
+
+	return &domain.AccessToken{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		TokenHash:  t.TokenHash,
+		Scopes:     scopes,
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"my_blog_backend/internal/domain"
 	"my_blog_backend/pkg/e"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ArticleRepository struct {
@@ -19,16 +21,37 @@ func NewArticleRepository(db *gorm.DB) *ArticleRepository {
 	}
 }
 
-func (a *ArticleRepository) Create(ctx context.Context, article *domain.Article) (*domain.Article, error) {
+func (a *ArticleRepository) Create(ctx context.Context, article *domain.Article, tags []*domain.Tag) (*domain.Article, error) {
 	const op = "ArticleRepository.Create"
 
 	articleModel := toArticleModel(article)
-	result := a.DB.WithContext(ctx).Create(articleModel)
-	if err := result.Error; err != nil {
+	var storedTags []domain.Tag
+	err := a.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		slug, err := uniqueArticleSlug(tx, domain.Slugify(articleModel.Title), 0)
+		if err != nil {
+			return err
+		}
+		articleModel.Slug = slug
+
+		if err := tx.Create(articleModel).Error; err != nil {
+			return err
+		}
+
+		revision := domain.NewArticleRevision(toArticleEntity(articleModel), articleModel.AuthorID)
+		if err := createRevision(tx, revision); err != nil {
+			return err
+		}
+
+		storedTags, err = replaceArticleTags(tx, articleModel.ID, tags)
+		return err
+	})
+	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	return toArticleEntity(articleModel), nil
+	newArticle := toArticleEntity(articleModel)
+	newArticle.Tags = storedTags
+	return newArticle, nil
 }
 
 func (a *ArticleRepository) GetByID(ctx context.Context, id uint) (*domain.Article, error) {
@@ -38,6 +61,7 @@ func (a *ArticleRepository) GetByID(ctx context.Context, id uint) (*domain.Artic
 	result := a.DB.WithContext(ctx).
 		Preload("Author").
 		Preload("Category").
+		Preload("Tags").
 		First(&articleModel, "id = ?", id)
 
 	if err := checkGetQueryResult(result, e.ErrArticleNotFound); err != nil {
@@ -47,16 +71,78 @@ func (a *ArticleRepository) GetByID(ctx context.Context, id uint) (*domain.Artic
 	return toArticleEntity(&articleModel), nil
 }
 
-func (a *ArticleRepository) Update(ctx context.Context, article *domain.Article) (*domain.Article, error) {
+// GetBySlug ищет статью по текущему или одному из прежних slug
+func (a *ArticleRepository) GetBySlug(ctx context.Context, slug string) (*domain.Article, error) {
+	const op = "ArticleRepository.GetBySlug"
+	var articleModel ArticleModel
+
+	result := a.DB.WithContext(ctx).
+		Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Where("slug = ?", slug).
+		Or("id = (?)", a.DB.Model(&ArticleSlugModel{}).Select("article_id").Where("slug = ?", slug)).
+		First(&articleModel)
+
+	if err := checkGetQueryResult(result, e.ErrArticleNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArticleEntity(&articleModel), nil
+}
+
+// Update сохраняет правку и в той же транзакции записывает новую ревизию,
+// если изменились заголовок, содержимое или категория
+func (a *ArticleRepository) Update(ctx context.Context, article *domain.Article, editorID uint, tags []*domain.Tag) (*domain.Article, error) {
 	const op = "ArticleRepository.Update"
 	articleModel := toArticleModel(article)
 	updates := map[string]interface{}{
 		"category_id": articleModel.Category.ID,
 		"title":       articleModel.Title,
 		"content":     articleModel.Content,
+		"publish_at":  articleModel.PublishAt,
 	}
-	result := a.DB.WithContext(ctx).Model(&ArticleModel{}).Where("id = ?", articleModel.ID).Updates(updates)
-	if err := checkChangeQueryResult(result, e.ErrArticleNotFound); err != nil {
+	err := a.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current ArticleModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "title", "slug", "content", "category_id").First(&current, "id = ?", articleModel.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return e.ErrArticleNotFound
+			}
+
+			return err
+		}
+
+		if base := domain.Slugify(articleModel.Title); base != domain.Slugify(current.Title) {
+			slug, err := changeArticleSlug(tx, current.ID, current.Slug, base)
+			if err != nil {
+				return err
+			}
+			updates["slug"] = slug
+		}
+
+		result := tx.Model(&ArticleModel{}).Where("id = ?", articleModel.ID).Updates(updates)
+		if err := checkChangeQueryResult(result, e.ErrArticleNotFound); err != nil {
+			return err
+		}
+
+		// Правка одних тегов или даты публикации ревизию не создаёт
+		revision := domain.NewArticleRevision(article, editorID)
+		revision.CategoryID = articleModel.Category.ID
+		previous := &domain.ArticleRevision{Title: current.Title, Content: current.Content, CategoryID: current.CategoryID}
+		if !previous.SameContent(revision) {
+			if err := createRevision(tx, revision); err != nil {
+				return err
+			}
+		}
+
+		if tags == nil {
+			return nil
+		}
+
+		_, err := replaceArticleTags(tx, articleModel.ID, tags)
+		return err
+	})
+	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
@@ -68,6 +154,26 @@ func (a *ArticleRepository) Update(ctx context.Context, article *domain.Article)
 	return updArticle, nil
 }
 
+func (a *ArticleRepository) UpdateStatus(ctx context.Context, article *domain.Article) (*domain.Article, error) {
+	const op = "ArticleRepository.UpdateStatus"
+	updates := map[string]interface{}{
+		"status":       article.Status,
+		"published_at": article.PublishedAt,
+		"publish_at":   article.PublishAt,
+	}
+	result := a.DB.WithContext(ctx).Model(&ArticleModel{}).Where("id = ?", article.ID).Updates(updates)
+	if err := checkChangeQueryResult(result, e.ErrArticleNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updArticle, err := a.GetByID(ctx, article.ID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return updArticle, nil
+}
+
 func (a *ArticleRepository) Delete(ctx context.Context, id uint) error {
 	const op = "ArticleRepository.Delete"
 	result := a.DB.WithContext(ctx).Delete(&ArticleModel{}, id)
@@ -78,22 +184,82 @@ func (a *ArticleRepository) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
-func (a *ArticleRepository) ListAll(ctx context.Context) ([]domain.Article, error) {
+func (a *ArticleRepository) ListAll(ctx context.Context, page domain.Page) ([]domain.Article, *domain.Cursor, error) {
 	const op = "ArticleRepository.ListAll"
-	query := a.DB.WithContext(ctx)
-	return a.listArticles(ctx, op, query)
+	query := a.DB.WithContext(ctx).Where("articles.status = ?", domain.ArticleStatusPublished)
+	return a.listArticles(op, query, page)
 }
 
-func (a *ArticleRepository) ListByAuthor(ctx context.Context, authorID uint) ([]domain.Article, error) {
+func (a *ArticleRepository) ListByAuthor(ctx context.Context, authorID uint, onlyPublished bool, page domain.Page) ([]domain.Article, *domain.Cursor, error) {
 	const op = "ArticleRepository.ListByAuthor"
-	query := a.DB.WithContext(ctx).Where("author_id = ?", authorID)
-	return a.listArticles(ctx, op, query)
+	query := a.DB.WithContext(ctx).Where("articles.author_id = ?", authorID)
+	if onlyPublished {
+		query = query.Where("articles.status = ?", domain.ArticleStatusPublished)
+	}
+	return a.listArticles(op, query, page)
 }
 
-func (a *ArticleRepository) ListByCategory(ctx context.Context, categoryID uint) ([]domain.Article, error) {
+// AuthorStats считает статистику профиля по опубликованным статьям автора
+func (a *ArticleRepository) AuthorStats(ctx context.Context, authorID uint) (*domain.AuthorStats, error) {
+	const op = "ArticleRepository.AuthorStats"
+
+	var totals struct {
+		Count            int
+		FirstPublishedAt *time.Time
+	}
+	if err := a.DB.WithContext(ctx).
+		Model(&ArticleModel{}).
+		Select("COUNT(*) AS count, MIN(published_at) AS first_published_at").
+		Where("author_id = ? AND status = ?", authorID, domain.ArticleStatusPublished).
+		Scan(&totals).Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	stats := &domain.AuthorStats{
+		ArticleCount:     totals.Count,
+		FirstPublishedAt: totals.FirstPublishedAt,
+	}
+	if totals.Count == 0 {
+		return stats, nil
+	}
+
+	// При равенстве побеждает категория, в которой автор написал раньше
+	var category CategoryModel
+	result := a.DB.WithContext(ctx).
+		Model(&CategoryModel{}).
+		Select("categories.*").
+		Joins("JOIN articles ON articles.category_id = categories.id").
+		Where("articles.author_id = ? AND articles.status = ?", authorID, domain.ArticleStatusPublished).
+		Group("categories.id").
+		Order("COUNT(*) DESC, MIN(articles.published_at), categories.id").
+		Limit(1).
+		Find(&category)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	if result.RowsAffected > 0 {
+		stats.TopCategory = toCategoryEntity(&category)
+	}
+
+	return stats, nil
+}
+
+func (a *ArticleRepository) ListByCategory(ctx context.Context, categoryID uint, page domain.Page) ([]domain.Article, *domain.Cursor, error) {
 	const op = "ArticleRepository.ListByCategory"
-	query := a.DB.WithContext(ctx).Where("category_id = ?", categoryID)
-	return a.listArticles(ctx, op, query)
+	query := a.DB.WithContext(ctx).
+		Where("articles.category_id = ?", categoryID).
+		Where("articles.status = ?", domain.ArticleStatusPublished)
+	return a.listArticles(op, query, page)
+}
+
+func (a *ArticleRepository) ListByTag(ctx context.Context, tagID uint, page domain.Page) ([]domain.Article, *domain.Cursor, error) {
+	const op = "ArticleRepository.ListByTag"
+	query := a.DB.WithContext(ctx).
+		Select("articles.*").
+		Joins("JOIN article_tags ON article_tags.article_id = articles.id").
+		Where("article_tags.tag_id = ?", tagID).
+		Where("articles.status = ?", domain.ArticleStatusPublished)
+	return a.listArticles(op, query, page)
 }
 
 func (a *ArticleRepository) ExistsByTitleContentAuthor(ctx context.Context, article *domain.Article) error {
@@ -117,10 +283,101 @@ func (a *ArticleRepository) ExistsByTitleContentAuthor(ctx context.Context, arti
 	return e.Wrap(op, e.ErrArticleDuplicate)
 }
 
-func (a *ArticleRepository) listArticles(ctx context.Context, op string, query *gorm.DB) ([]domain.Article, error) {
+// Маркеры подсветки из Private Use Area: в тексте статей они не встречаются
+// и не затрагиваются экранированием HTML
+const (
+	snippetStartSel = "\uE000"
+	snippetStopSel  = "\uE001"
+	snippetOptions  = "StartSel=" + snippetStartSel + ", StopSel=" + snippetStopSel + ", MaxFragments=2, MaxWords=30, MinWords=10"
+)
+
+type searchRow struct {
+	ID      uint
+	Rank    float32
+	Snippet string
+}
+
+// Поиск по опубликованным статьям, результаты упорядочены по релевантности
+func (a *ArticleRepository) Search(ctx context.Context, search domain.ArticleSearch, page domain.SearchPage) ([]domain.ArticleSearchHit, *domain.SearchCursor, error) {
+	const op = "ArticleRepository.Search"
+	const rank = "ts_rank_cd(articles.search_vector, q)::real"
+
+	query := a.DB.WithContext(ctx).Table("articles").
+		Select("articles.id, "+rank+" AS rank, ts_headline('simple', articles.content, q, ?) AS snippet", snippetOptions).
+		Joins("CROSS JOIN websearch_to_tsquery('simple', ?) AS q", search.Query).
+		Where("articles.search_vector @@ q").
+		Where("articles.status = ?", domain.ArticleStatusPublished)
+
+	if search.CategoryID != nil {
+		query = query.Where("articles.category_id = ?", *search.CategoryID)
+	}
+
+	if search.AuthorID != nil {
+		query = query.Where("articles.author_id = ?", *search.AuthorID)
+	}
+
+	if page.After != nil {
+		query = query.Where("("+rank+", articles.id) < (?, ?)", page.After.Rank, page.After.ID)
+	}
+
+	var rows []searchRow
+	result := query.Order("rank DESC").Order("articles.id DESC").Limit(page.Limit + 1).Scan(&rows)
+	if err := result.Error; err != nil {
+		return nil, nil, e.Wrap(op, err)
+	}
+
+	var next *domain.SearchCursor
+	if len(rows) > page.Limit {
+		rows = rows[:page.Limit]
+		last := rows[len(rows)-1]
+		next = &domain.SearchCursor{Rank: last.Rank, ID: last.ID}
+	}
+
+	if len(rows) == 0 {
+		return []domain.ArticleSearchHit{}, nil, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
 	var articleModels []ArticleModel
-	result := query.Preload("Author").Preload("Category").Find(&articleModels)
-	if err := checkGetQueryResult(result, e.ErrArticleNotFound); err != nil {
+	if err := a.DB.WithContext(ctx).Preload("Author").Preload("Category").Preload("Tags").Where("id IN ?", ids).Find(&articleModels).Error; err != nil {
+		return nil, nil, e.Wrap(op, err)
+	}
+
+	byID := make(map[uint]*ArticleModel, len(articleModels))
+	for i := range articleModels {
+		byID[articleModels[i].ID] = &articleModels[i]
+	}
+
+	hits := make([]domain.ArticleSearchHit, 0, len(rows))
+	for _, row := range rows {
+		model, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+
+		hits = append(hits, domain.ArticleSearchHit{
+			Article: *toArticleEntity(model),
+			Rank:    row.Rank,
+			Snippet: highlightSnippet(row.Snippet),
+		})
+	}
+
+	return hits, next, nil
+}
+
+func (a *ArticleRepository) ListDueForPublishing(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	const op = "ArticleRepository.ListDueForPublishing"
+	var articleModels []ArticleModel
+	result := a.DB.WithContext(ctx).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", domain.ArticleStatusDraft, now).
+		Order("publish_at").
+		Limit(limit).
+		Find(&articleModels)
+	if err := result.Error; err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
@@ -132,15 +389,113 @@ func (a *ArticleRepository) listArticles(ctx context.Context, op string, query *
 	return articles, nil
 }
 
+// Публикует статью, только если она всё ещё ждёт публикации в момент scheduledAt:
+// автор мог успеть отменить или перенести расписание или опубликовать статью вручную
+func (a *ArticleRepository) PublishScheduled(ctx context.Context, article *domain.Article, scheduledAt, now time.Time) error {
+	const op = "ArticleRepository.PublishScheduled"
+	updates := map[string]interface{}{
+		"status":       article.Status,
+		"published_at": article.PublishedAt,
+		"publish_at":   nil,
+	}
+	result := a.DB.WithContext(ctx).Model(&ArticleModel{}).
+		Where("id = ? AND status = ? AND publish_at = ? AND publish_at <= ?", article.ID, domain.ArticleStatusDraft, scheduledAt, now).
+		Updates(updates)
+	if err := checkChangeQueryResult(result, e.ErrArticleNotScheduled); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Keyset-пагинация по (created_at, id): выбираем на одну запись больше,
+// чтобы понять, есть ли следующая страница
+func (a *ArticleRepository) listArticles(op string, query *gorm.DB, page domain.Page) ([]domain.Article, *domain.Cursor, error) {
+	if page.After != nil {
+		query = query.Where("(articles.created_at, articles.id) < (?, ?)", page.After.CreatedAt, page.After.ID)
+	}
+
+	var articleModels []ArticleModel
+	result := query.Preload("Author").Preload("Category").Preload("Tags").
+		Order("articles.created_at DESC").
+		Order("articles.id DESC").
+		Limit(page.Limit + 1).
+		Find(&articleModels)
+	if err := checkGetQueryResult(result, e.ErrArticleNotFound); err != nil {
+		return nil, nil, e.Wrap(op, err)
+	}
+
+	var next *domain.Cursor
+	if len(articleModels) > page.Limit {
+		articleModels = articleModels[:page.Limit]
+		last := articleModels[len(articleModels)-1]
+		next = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	articles := make([]domain.Article, 0, len(articleModels))
+	for _, model := range articleModels {
+		articles = append(articles, *toArticleEntity(&model))
+	}
+
+	return articles, next, nil
+}
+
+// uniqueArticleSlug подбирает свободный slug среди текущих и прежних slug всех статей,
+// кроме статьи articleID. Advisory lock не даёт параллельным транзакциям выбрать один и тот же slug
+func uniqueArticleSlug(tx *gorm.DB, base string, articleID uint) (string, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "article_slug:"+base).Error; err != nil {
+		return "", err
+	}
+
+	var taken []string
+	err := tx.Raw(`SELECT slug FROM articles WHERE (slug = ? OR slug LIKE ?) AND id <> ?
+		UNION SELECT slug FROM article_slugs WHERE (slug = ? OR slug LIKE ?) AND article_id <> ?`,
+		base, base+"-%", articleID, base, base+"-%", articleID).
+		Scan(&taken).Error
+	if err != nil {
+		return "", err
+	}
+
+	return domain.UniqueSlug(base, taken), nil
+}
+
+// changeArticleSlug сохраняет старый slug для редиректа и возвращает новый.
+// Если новый slug уже был у этой статьи раньше, он убирается из истории
+func changeArticleSlug(tx *gorm.DB, articleID uint, oldSlug, base string) (string, error) {
+	slug, err := uniqueArticleSlug(tx, base, articleID)
+	if err != nil {
+		return "", err
+	}
+
+	if slug == oldSlug {
+		return slug, nil
+	}
+
+	if err := tx.Where("slug = ? AND article_id = ?", slug, articleID).Delete(&ArticleSlugModel{}).Error; err != nil {
+		return "", err
+	}
+
+	oldSlugModel := &ArticleSlugModel{Slug: oldSlug, ArticleID: articleID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(oldSlugModel).Error; err != nil {
+		return "", err
+	}
+
+	return slug, nil
+}
+
 func toArticleModel(a *domain.Article) *ArticleModel {
 	model := &ArticleModel{
-		ID:         a.ID,
-		CreatedAt:  a.CreatedAt,
-		UpdatedAt:  a.UpdatedAt,
-		Title:      a.Title,
-		Content:    a.Content,
-		AuthorID:   a.AuthorID,
-		CategoryID: a.CategoryID,
+		ID:          a.ID,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+		Title:       a.Title,
+		Slug:        a.Slug,
+		Content:     a.Content,
+		Status:      a.Status,
+		PublishedAt: a.PublishedAt,
+		PublishAt:   a.PublishAt,
+		AuthorID:    a.AuthorID,
+		CategoryID:  a.CategoryID,
 	}
 
 	if a.Author != nil {
@@ -155,13 +510,17 @@ func toArticleModel(a *domain.Article) *ArticleModel {
 
 func toArticleEntity(a *ArticleModel) *domain.Article {
 	entity := &domain.Article{
-		ID:         a.ID,
-		CreatedAt:  a.CreatedAt,
-		UpdatedAt:  a.UpdatedAt,
-		Title:      a.Title,
-		Content:    a.Content,
-		AuthorID:   a.AuthorID,
-		CategoryID: a.CategoryID,
+		ID:          a.ID,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+		Title:       a.Title,
+		Slug:        a.Slug,
+		Content:     a.Content,
+		Status:      a.Status,
+		PublishedAt: a.PublishedAt,
+		PublishAt:   a.PublishAt,
+		AuthorID:    a.AuthorID,
+		CategoryID:  a.CategoryID,
 	}
 
 	if a.Author != nil {
@@ -172,5 +531,12 @@ func toArticleEntity(a *ArticleModel) *domain.Article {
 		entity.Category = toCategoryEntity(a.Category)
 	}
 
+	if a.Tags != nil {
+		entity.Tags = make([]domain.Tag, len(a.Tags))
+		for i := range a.Tags {
+			entity.Tags[i] = *toTagEntity(&a.Tags[i])
+		}
+	}
+
 	return entity
 }
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+
+	"gorm.io/gorm"
+)
+
+type CategorySubscriptionRepository struct {
+	DB *gorm.DB
+}
+
+func NewCategorySubscriptionRepository(db *gorm.DB) *CategorySubscriptionRepository {
+	return &CategorySubscriptionRepository{
+		DB: db,
+	}
+}
+
+func (s *CategorySubscriptionRepository) Create(ctx context.Context, subscription *domain.CategorySubscription) error {
+	const op = "CategorySubscriptionRepository.Create"
+
+	result := s.DB.WithContext(ctx).Create(&CategorySubscriptionModel{
+		UserID:     subscription.UserID,
+		CategoryID: subscription.CategoryID,
+		CreatedAt:  subscription.CreatedAt,
+	})
+	if err := postgresDuplicate(result, e.ErrAlreadySubscribed); err != nil {
+		if errors.Is(err, e.ErrAlreadySubscribed) {
+			return e.Wrap(op, err)
+		}
+
+		return e.Wrap(op, postgresForeignKeyViolation(result, e.ErrCategoryNotFound))
+	}
+
+	return nil
+}
+
+func (s *CategorySubscriptionRepository) Delete(ctx context.Context, userID, categoryID uint) error {
+	const op = "CategorySubscriptionRepository.Delete"
+
+	result := s.DB.WithContext(ctx).
+		Where("user_id = ? AND category_id = ?", userID, categoryID).
+		Delete(&CategorySubscriptionModel{})
+	if err := checkChangeQueryResult(result, e.ErrNotSubscribed); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *CategorySubscriptionRepository) ListByUser(ctx context.Context, userID uint) ([]domain.CategorySubscription, error) {
+	const op = "CategorySubscriptionRepository.ListByUser"
+
+	var models []CategorySubscriptionModel
+	result := s.DB.WithContext(ctx).
+		Preload("Category").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&models)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	subscriptions := make([]domain.CategorySubscription, 0, len(models))
+	for _, model := range models {
+		subscription := domain.CategorySubscription{
+			UserID:     model.UserID,
+			CategoryID: model.CategoryID,
+			CreatedAt:  model.CreatedAt,
+		}
+		if model.Category != nil {
+			subscription.Category = toCategoryEntity(model.Category)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, nil
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	DB *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{
+		DB: db,
+	}
+}
+
+func (c *CommentRepository) Create(ctx context.Context, comment *domain.Comment) (*domain.Comment, error) {
+	const op = "CommentRepository.Create"
+	commentModel := toCommentModel(comment)
+	result := c.DB.WithContext(ctx).Create(commentModel)
+	if err := postgresForeignKeyViolation(result, e.ErrArticleNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	newComment, err := c.GetByID(ctx, commentModel.ID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return newComment, nil
+}
+
+func (c *CommentRepository) GetByID(ctx context.Context, id uint) (*domain.Comment, error) {
+	const op = "CommentRepository.GetByID"
+	var commentModel CommentModel
+	result := c.DB.WithContext(ctx).Preload("Author").First(&commentModel, "id = ?", id)
+	if err := checkGetQueryResult(result, e.ErrCommentNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toCommentEntity(&commentModel), nil
+}
+
+func (c *CommentRepository) Update(ctx context.Context, comment *domain.Comment) (*domain.Comment, error) {
+	const op = "CommentRepository.Update"
+	updates := map[string]interface{}{
+		"content":    comment.Content,
+		"is_deleted": comment.IsDeleted,
+		"updated_at": time.Now().UTC(),
+	}
+	result := c.DB.WithContext(ctx).Model(&CommentModel{}).Where("id = ?", comment.ID).Updates(updates)
+	if err := checkChangeQueryResult(result, e.ErrCommentNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updComment, err := c.GetByID(ctx, comment.ID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return updComment, nil
+}
+
+func (c *CommentRepository) Delete(ctx context.Context, id uint) error {
+	const op = "CommentRepository.Delete"
+	result := c.DB.WithContext(ctx).Delete(&CommentModel{}, id)
+	if err := checkChangeQueryResult(result, e.ErrCommentNotFound); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (c *CommentRepository) HasReplies(ctx context.Context, id uint) (bool, error) {
+	const op = "CommentRepository.HasReplies"
+	var exists bool
+	result := c.DB.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM comments WHERE parent_id = ?)", id).
+		Scan(&exists)
+	if err := result.Error; err != nil {
+		return false, e.Wrap(op, err)
+	}
+
+	return exists, nil
+}
+
+// Постраничный список комментариев одного уровня: корневых, если parentID == nil, или ответов на parentID.
+// Комментарии идут от старых к новым
+func (c *CommentRepository) ListByParent(ctx context.Context, articleID uint, parentID *uint, page domain.Page) ([]domain.Comment, *domain.Cursor, error) {
+	const op = "CommentRepository.ListByParent"
+	query := c.DB.WithContext(ctx).Where("article_id = ?", articleID)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	if page.After != nil {
+		query = query.Where("(created_at, id) > (?, ?)", page.After.CreatedAt, page.After.ID)
+	}
+
+	var commentModels []CommentModel
+	result := query.Preload("Author").
+		Order("created_at").
+		Order("id").
+		Limit(page.Limit + 1).
+		Find(&commentModels)
+	if err := result.Error; err != nil {
+		return nil, nil, e.Wrap(op, err)
+	}
+
+	var next *domain.Cursor
+	if len(commentModels) > page.Limit {
+		commentModels = commentModels[:page.Limit]
+		last := commentModels[len(commentModels)-1]
+		next = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return toCommentEntities(commentModels), next, nil
+}
+
+// Все потомки комментариев parentIDs не глубже maxDepth уровней
+func (c *CommentRepository) ListDescendants(ctx context.Context, parentIDs []uint, maxDepth int) ([]domain.Comment, error) {
+	const op = "CommentRepository.ListDescendants"
+	if len(parentIDs) == 0 || maxDepth <= 0 {
+		return []domain.Comment{}, nil
+	}
+
+	thread := c.DB.Raw(`
+		WITH RECURSIVE thread AS (
+			SELECT id, 1 AS depth FROM comments WHERE parent_id IN ?
+			UNION ALL
+			SELECT comments.id, thread.depth + 1 FROM comments
+			JOIN thread ON comments.parent_id = thread.id
+			WHERE thread.depth < ?
+		)
+		SELECT id FROM thread`, parentIDs, maxDepth)
+
+	var commentModels []CommentModel
+	result := c.DB.WithContext(ctx).Preload("Author").
+		Where("id IN (?)", thread).
+		Order("created_at").
+		Order("id").
+		Find(&commentModels)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toCommentEntities(commentModels), nil
+}
+
+func (c *CommentRepository) ListByAuthor(ctx context.Context, authorID uint) ([]domain.Comment, error) {
+	const op = "CommentRepository.ListByAuthor"
+
+	var commentModels []CommentModel
+	result := c.DB.WithContext(ctx).
+		Where("author_id = ?", authorID).
+		Order("created_at").
+		Order("id").
+		Find(&commentModels)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toCommentEntities(commentModels), nil
+}
+
+func toCommentEntities(commentModels []CommentModel) []domain.Comment {
+	comments := make([]domain.Comment, 0, len(commentModels))
+	for _, model := range commentModels {
+		comments = append(comments, *toCommentEntity(&model))
+	}
+
+	return comments
+}
+
+func toCommentModel(c *domain.Comment) *CommentModel {
+	model := &CommentModel{
+		ID:        c.ID,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		ArticleID: c.ArticleID,
+		ParentID:  c.ParentID,
+		Content:   c.Content,
+		IsDeleted: c.IsDeleted,
+	}
+
+	if c.AuthorID != 0 {
+		authorID := c.AuthorID
+		model.AuthorID = &authorID
+	}
+
+	return model
+}
+
+func toCommentEntity(c *CommentModel) *domain.Comment {
+	entity := &domain.Comment{
+		ID:        c.ID,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		ArticleID: c.ArticleID,
+		ParentID:  c.ParentID,
+		Content:   c.Content,
+		IsDeleted: c.IsDeleted,
+	}
+
+	if c.AuthorID != nil {
+		entity.AuthorID = *c.AuthorID
+	}
+
+	if c.Author != nil {
+		entity.Author = toUserEntity(c.Author)
+	}
+
+	return entity
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EmailVerificationRepository struct {
+	DB *gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		DB: db,
+	}
+}
+
+func (r *EmailVerificationRepository) Create(ctx context.Context, token *domain.EmailVerificationToken) error {
+	const op = "EmailVerificationRepository.Create"
+	tokenModel := toEmailVerificationTokenModel(token)
+	result := r.DB.WithContext(ctx).Create(tokenModel)
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	token.ID = tokenModel.ID
+	token.CreatedAt = tokenModel.CreatedAt
+	return nil
+}
+
+func (r *EmailVerificationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	const op = "EmailVerificationRepository.GetByTokenHash"
+	var tokenModel EmailVerificationTokenModel
+	result := r.DB.WithContext(ctx).First(&tokenModel, "token_hash = ?", tokenHash)
+	if err := checkGetQueryResult(result, e.ErrEmailVerificationTokenInvalid); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toEmailVerificationTokenEntity(&tokenModel), nil
+}
+
+// MarkUsed гасит токен. Условие на used_at не даёт использовать токен дважды
+// при параллельных запросах
+func (r *EmailVerificationRepository) MarkUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	const op = "EmailVerificationRepository.MarkUsed"
+	result := r.DB.WithContext(ctx).
+		Model(&EmailVerificationTokenModel{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if err := checkChangeQueryResult(result, e.ErrEmailVerificationTokenInvalid); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Гасит все неиспользованные токены пользователя
+func (r *EmailVerificationRepository) InvalidateByUser(ctx context.Context, userID uint, at time.Time) error {
+	const op = "EmailVerificationRepository.InvalidateByUser"
+	result := r.DB.WithContext(ctx).
+		Model(&EmailVerificationTokenModel{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at)
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func toEmailVerificationTokenModel(t *domain.EmailVerificationToken) *EmailVerificationTokenModel {
+	return &EmailVerificationTokenModel{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Email:     t.Email,
+		TokenHash: t.TokenHash,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+	}
+}
+
+func toEmailVerificationTokenEntity(t *EmailVerificationTokenModel) *domain.EmailVerificationToken {
+	return &domain.EmailVerificationToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Email:     t.Email,
+		TokenHash: t.TokenHash,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+	}
+}
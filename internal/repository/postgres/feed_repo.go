@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+
+	"gorm.io/gorm"
+)
+
+type FeedRepository struct {
+	DB *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) *FeedRepository {
+	return &FeedRepository{
+		DB: db,
+	}
+}
+
+// List возвращает опубликованные статьи авторов, на которых подписан пользователь,
+// и статьи из его категорий одним запросом. Лента упорядочена по времени публикации,
+// поэтому CreatedAt курсора здесь хранит published_at
+func (f *FeedRepository) List(ctx context.Context, userID uint, page domain.Page) ([]domain.Article, *domain.Cursor, error) {
+	const op = "FeedRepository.List"
+
+	db := f.DB.WithContext(ctx)
+	followees := db.Model(&FollowModel{}).Select("followee_id").Where("follower_id = ?", userID)
+	categories := db.Model(&CategorySubscriptionModel{}).Select("category_id").Where("user_id = ?", userID)
+
+	query := db.
+		Where("articles.status = ?", domain.ArticleStatusPublished).
+		Where("articles.author_id IN (?) OR articles.category_id IN (?)", followees, categories)
+	if page.After != nil {
+		query = query.Where("(articles.published_at, articles.id) < (?, ?)", page.After.CreatedAt, page.After.ID)
+	}
+
+	var articleModels []ArticleModel
+	result := query.Preload("Author").Preload("Category").Preload("Tags").
+		Order("articles.published_at DESC").
+		Order("articles.id DESC").
+		Limit(page.Limit + 1).
+		Find(&articleModels)
+	if err := result.Error; err != nil {
+		return nil, nil, e.Wrap(op, err)
+	}
+
+	var next *domain.Cursor
+	if len(articleModels) > page.Limit {
+		articleModels = articleModels[:page.Limit]
+		last := articleModels[len(articleModels)-1]
+		next = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		if last.PublishedAt != nil {
+			next.CreatedAt = *last.PublishedAt
+		}
+	}
+
+	articles := make([]domain.Article, 0, len(articleModels))
+	for _, model := range articleModels {
+		articles = append(articles, *toArticleEntity(&model))
+	}
+
+	return articles, next, nil
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+
+	"gorm.io/gorm"
+)
+
+type FollowRepository struct {
+	DB *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) *FollowRepository {
+	return &FollowRepository{
+		DB: db,
+	}
+}
+
+func (f *FollowRepository) Create(ctx context.Context, follow *domain.Follow) error {
+	const op = "FollowRepository.Create"
+
+	result := f.DB.WithContext(ctx).Create(toFollowModel(follow))
+	if err := postgresDuplicate(result, e.ErrAlreadyFollowing); err != nil {
+		if errors.Is(err, e.ErrAlreadyFollowing) {
+			return e.Wrap(op, err)
+		}
+
+		return e.Wrap(op, postgresForeignKeyViolation(result, e.ErrUserNotFound))
+	}
+
+	return nil
+}
+
+func (f *FollowRepository) Delete(ctx context.Context, followerID, followeeID uint) error {
+	const op = "FollowRepository.Delete"
+
+	result := f.DB.WithContext(ctx).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Delete(&FollowModel{})
+	if err := checkChangeQueryResult(result, e.ErrNotFollowing); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// ListFollowers возвращает подписчиков пользователя, в курсоре ID - follower_id
+func (f *FollowRepository) ListFollowers(ctx context.Context, userID uint, page domain.Page) ([]domain.Follow, *domain.Cursor, error) {
+	const op = "FollowRepository.ListFollowers"
+
+	query := f.DB.WithContext(ctx).Preload("Follower").Where("followee_id = ?", userID)
+	follows, next, err := listFollows(query, "follower_id", func(m *FollowModel) uint { return m.FollowerID }, page)
+	if err != nil {
+		return nil, nil, e.Wrap(op, err)
+	}
+
+	return follows, next, nil
+}
+
+// ListFollowing возвращает авторов, на которых подписан пользователь, в курсоре ID - followee_id
+func (f *FollowRepository) ListFollowing(ctx context.Context, userID uint, page domain.Page) ([]domain.Follow, *domain.Cursor, error) {
+	const op = "FollowRepository.ListFollowing"
+
+	query := f.DB.WithContext(ctx).Preload("Followee").Where("follower_id = ?", userID)
+	follows, next, err := listFollows(query, "followee_id", func(m *FollowModel) uint { return m.FolloweeID }, page)
+	if err != nil {
+		return nil, nil, e.Wrap(op, err)
+	}
+
+	return follows, next, nil
+}
+
+// idColumn - второй столбец ключа сортировки, idOf достаёт его значение для курсора
+func listFollows(query *gorm.DB, idColumn string, idOf func(*FollowModel) uint, page domain.Page) ([]domain.Follow, *domain.Cursor, error) {
+	if page.After != nil {
+		query = query.Where("(created_at, "+idColumn+") < (?, ?)", page.After.CreatedAt, page.After.ID)
+	}
+
+	var followModels []FollowModel
+	result := query.
+		Order("created_at DESC").
+		Order(idColumn + " DESC").
+		Limit(page.Limit + 1).
+		Find(&followModels)
+	if err := result.Error; err != nil {
+		return nil, nil, err
+	}
+
+	var next *domain.Cursor
+	if len(followModels) > page.Limit {
+		followModels = followModels[:page.Limit]
+		last := followModels[len(followModels)-1]
+		next = &domain.Cursor{CreatedAt: last.CreatedAt, ID: idOf(&last)}
+	}
+
+	follows := make([]domain.Follow, 0, len(followModels))
+	for _, model := range followModels {
+		follows = append(follows, *toFollowEntity(&model))
+	}
+
+	return follows, next, nil
+}
+
+func toFollowModel(f *domain.Follow) *FollowModel {
+	return &FollowModel{
+		FollowerID: f.FollowerID,
+		FolloweeID: f.FolloweeID,
+		CreatedAt:  f.CreatedAt,
+	}
+}
+
+func toFollowEntity(f *FollowModel) *domain.Follow {
+	entity := &domain.Follow{
+		FollowerID: f.FollowerID,
+		FolloweeID: f.FolloweeID,
+		CreatedAt:  f.CreatedAt,
+	}
+
+	if f.Follower != nil {
+		entity.Follower = toUserEntity(f.Follower)
+	}
+	if f.Followee != nil {
+		entity.Followee = toUserEntity(f.Followee)
+	}
+
+	return entity
+}
@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"errors"
+	"html"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
@@ -58,3 +60,15 @@ func postgresForeignKeyViolation(result *gorm.DB, ErrInUse error) error {
 
 	return nil
 }
+
+// Экранирует спецсимволы LIKE, чтобы пользовательский ввод искался буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// Экранирует фрагмент из ts_headline и заменяет маркеры на <mark>
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, snippetStartSel, "<mark>")
+	return strings.ReplaceAll(escaped, snippetStopSel, "</mark>")
+}
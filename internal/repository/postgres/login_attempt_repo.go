@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginAttemptRepository struct {
+	DB *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		DB: db,
+	}
+}
+
+// TryAttempt держит блокировку строки счётчика, пока решает, пропускать ли попытку,
+// чтобы параллельные запросы не прошли проверку по одному и тому же значению
+func (r *LoginAttemptRepository) TryAttempt(ctx context.Context, key string, now time.Time, policy domain.LockoutPolicy) (time.Duration, error) {
+	const op = "LoginAttemptRepository.TryAttempt"
+	var retryAfter time.Duration
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Строка нужна заранее, чтобы было что блокировать. Время ошибки берётся за
+		// пределами окна, так что новый счётчик ни на что не влияет
+		err := tx.Exec(`
+			INSERT INTO login_attempts (attempt_key, failures, last_failure_at)
+			VALUES (?, 0, ?)
+			ON CONFLICT (attempt_key) DO NOTHING`,
+			key, now.Add(-policy.Window),
+		).Error
+		if err != nil {
+			return err
+		}
+
+		var attemptModel LoginAttemptModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&attemptModel, "attempt_key = ?", key).Error; err != nil {
+			return err
+		}
+
+		attempts := toLoginAttemptsEntity(&attemptModel)
+		if retryAfter = policy.Attempt(attempts, now); retryAfter > 0 {
+			return nil
+		}
+
+		return tx.Model(&LoginAttemptModel{}).
+			Where("attempt_key = ?", key).
+			Updates(map[string]any{
+				"failures":        attempts.Failures,
+				"last_failure_at": attempts.LastFailureAt,
+			}).Error
+	})
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return retryAfter, nil
+}
+
+func (r *LoginAttemptRepository) Forgive(ctx context.Context, key string) error {
+	const op = "LoginAttemptRepository.Forgive"
+	result := r.DB.WithContext(ctx).
+		Model(&LoginAttemptModel{}).
+		Where("attempt_key = ? AND failures > 0", key).
+		Update("failures", gorm.Expr("failures - 1"))
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) Reset(ctx context.Context, keys ...string) error {
+	const op = "LoginAttemptRepository.Reset"
+	if len(keys) == 0 {
+		return nil
+	}
+
+	result := r.DB.WithContext(ctx).Where("attempt_key IN ?", keys).Delete(&LoginAttemptModel{})
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) DeleteStale(ctx context.Context, staleBefore time.Time) error {
+	const op = "LoginAttemptRepository.DeleteStale"
+	result := r.DB.WithContext(ctx).Where("last_failure_at < ?", staleBefore).Delete(&LoginAttemptModel{})
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func toLoginAttemptsEntity(a *LoginAttemptModel) *domain.LoginAttempts {
+	return &domain.LoginAttempts{
+		Key:           a.AttemptKey,
+		Failures:      a.Failures,
+		LastFailureAt: a.LastFailureAt,
+	}
+}
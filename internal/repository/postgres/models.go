@@ -8,25 +8,94 @@ import (
 )
 
 type UserModel struct {
-	ID           uint `gorm:"primarykey"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	Role         domain.Role `gorm:"not null"`
-	Username     string      `gorm:"size:32;uniqueIndex:idx_username;not null"`
-	Email        string      `gorm:"size:320;uniqueIndex:idx_email;not null"`
-	PasswordHash string      `gorm:"not null"`
+	ID               uint `gorm:"primarykey"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Role             domain.Role `gorm:"not null"`
+	Username         string      `gorm:"size:32;uniqueIndex:idx_username;not null"`
+	Email            string      `gorm:"size:320;uniqueIndex:idx_email;not null"`
+	PasswordHash     string      `gorm:"not null"`
+	EmailVerifiedAt  *time.Time
+	PendingEmail     *string    `gorm:"size:320"`
+	TOTPSecret       *string    `gorm:"column:totp_secret;size:64"`
+	TOTPEnabledAt    *time.Time `gorm:"column:totp_enabled_at"`
+	TOTPLastUsedStep int64      `gorm:"column:totp_last_used_step;not null;default:0"`
+	SuspendedAt      *time.Time
+	// Если задано, после этого момента аккаунт удаляется
+	DeletionScheduledAt *time.Time
+	DeletionArticles    domain.ArticleDisposal `gorm:"size:16;not null;default:''"`
+	DisplayName         string                 `gorm:"size:64;not null;default:''"`
+	Bio                 string                 `gorm:"not null;default:''"`
+	AvatarURL           string                 `gorm:"column:avatar_url;size:2048;not null;default:''"`
+	Website             string                 `gorm:"size:2048;not null;default:''"`
+	Location            string                 `gorm:"size:64;not null;default:''"`
+	// JSON массив ProfileLinkModel
+	SocialLinks string `gorm:"type:jsonb;not null;default:'[]'"`
+	IsSystem    bool   `gorm:"not null;default:false"`
+}
+
+type ProfileLinkModel struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
 }
 
 type ArticleModel struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string               `gorm:"size:128;not null"`
+	Slug        string               `gorm:"size:128;uniqueIndex;not null"`
+	Content     string               `gorm:"not null"`
+	Status      domain.ArticleStatus `gorm:"size:16;not null"`
+	PublishedAt *time.Time
+	PublishAt   *time.Time
+	AuthorID    uint           `gorm:"not null;index"`
+	Author      *UserModel     `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	CategoryID  uint           `gorm:"not null;index"`
+	Category    *CategoryModel `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Tags        []TagModel     `gorm:"many2many:article_tags;joinForeignKey:ArticleID;joinReferences:TagID"`
+}
+
+type ArticleSlugModel struct {
+	Slug      string `gorm:"primaryKey;size:128"`
+	ArticleID uint   `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+type TagModel struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	Name      string `gorm:"size:64;not null"`
+	Slug      string `gorm:"size:64;unique;not null"`
+}
+
+type CommentModel struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ArticleID uint       `gorm:"not null;index"`
+	AuthorID  *uint      `gorm:"index"`
+	Author    *UserModel `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	ParentID  *uint      `gorm:"index"`
+	Content   string     `gorm:"not null"`
+	IsDeleted bool       `gorm:"not null"`
+}
+
+type ArticleRevisionModel struct {
 	ID         uint `gorm:"primarykey"`
 	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	Title      string         `gorm:"size:128;not null"`
-	Content    string         `gorm:"not null"`
-	AuthorID   uint           `gorm:"not null;index"`
-	Author     *UserModel     `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
-	CategoryID uint           `gorm:"not null;index"`
-	Category   *CategoryModel `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	ArticleID  uint       `gorm:"not null;uniqueIndex:idx_article_revision_number"`
+	Number     int        `gorm:"not null;uniqueIndex:idx_article_revision_number"`
+	Title      string     `gorm:"size:128;not null"`
+	Content    string     `gorm:"not null"`
+	CategoryID *uint      `gorm:"index"`
+	EditorID   *uint      `gorm:"index"`
+	Editor     *UserModel `gorm:"foreignKey:EditorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
+
+type ArticleTagModel struct {
+	ArticleID uint `gorm:"primaryKey"`
+	TagID     uint `gorm:"primaryKey"`
 }
 
 type CategoryModel struct {
@@ -38,14 +107,78 @@ type CategoryModel struct {
 }
 
 type SessionModel struct {
-	Id               uuid.UUID `gorm:"primarykey"`
-	UserId           uint      `gorm:"not null"`
-	RefreshTokenHash string    `gorm:"size:64;not null;unique"`
-	IsRevoked        bool      `gorm:"not null"`
+	Id               uuid.UUID                  `gorm:"primarykey"`
+	FamilyId         uuid.UUID                  `gorm:"type:uuid;not null;index"`
+	UserId           uint                       `gorm:"not null"`
+	RefreshTokenHash string                     `gorm:"size:64;not null;unique"`
+	IsRevoked        bool                       `gorm:"not null"`
+	RevokeReason     domain.SessionRevokeReason `gorm:"size:16;not null;default:''"`
+	UserAgent        string                     `gorm:"size:512;not null"`
+	IP               string                     `gorm:"size:45;not null"`
 	CreatedAt        time.Time
+	LastUsedAt       time.Time
 	ExpiresAt        time.Time
 }
 
+type PasswordResetTokenModel struct {
+	ID        uint   `gorm:"primarykey"`
+	UserID    uint   `gorm:"not null;index"`
+	TokenHash string `gorm:"size:64;not null;unique"`
+	CreatedAt time.Time
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
+type EmailVerificationTokenModel struct {
+	ID        uint   `gorm:"primarykey"`
+	UserID    uint   `gorm:"not null;index"`
+	Email     string `gorm:"size:320;not null"`
+	TokenHash string `gorm:"size:64;not null;unique"`
+	CreatedAt time.Time
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
+
+type RecoveryCodeModel struct {
+	ID        uint   `gorm:"primarykey"`
+	UserID    uint   `gorm:"not null;uniqueIndex:uq_recovery_codes_user_code"`
+	CodeHash  string `gorm:"size:64;not null;uniqueIndex:uq_recovery_codes_user_code"`
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+type AccessTokenModel struct {
+	ID         uint   `gorm:"primarykey"`
+	UserID     uint   `gorm:"not null;index"`
+	Name       string `gorm:"size:64;not null"`
+	TokenHash  string `gorm:"size:64;not null;unique"`
+	Scopes     string `gorm:"size:512;not null"`
+	CreatedAt  time.Time
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+}
+
+type LoginAttemptModel struct {
+	AttemptKey    string    `gorm:"primaryKey;size:400"`
+	Failures      int       `gorm:"not null"`
+	LastFailureAt time.Time `gorm:"not null;index"`
+}
+
+type FollowModel struct {
+	FollowerID uint `gorm:"primaryKey;autoIncrement:false"`
+	FolloweeID uint `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt  time.Time
+	Follower   *UserModel `gorm:"foreignKey:FollowerID"`
+	Followee   *UserModel `gorm:"foreignKey:FolloweeID"`
+}
+
+type CategorySubscriptionModel struct {
+	UserID     uint `gorm:"primaryKey;autoIncrement:false"`
+	CategoryID uint `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt  time.Time
+	Category   *CategoryModel `gorm:"foreignKey:CategoryID"`
+}
+
 func (*ArticleModel) TableName() string {
 	return "articles"
 }
@@ -53,4 +186,30 @@ func (*CategoryModel) TableName() string {
 	return "categories"
 }
 func (*SessionModel) TableName() string { return "sessions" }
-func (*UserModel) TableName() string    { return "users" }
+func (*PasswordResetTokenModel) TableName() string {
+	return "password_reset_tokens"
+}
+func (*EmailVerificationTokenModel) TableName() string {
+	return "email_verification_tokens"
+}
+func (*RecoveryCodeModel) TableName() string {
+	return "recovery_codes"
+}
+func (*AccessTokenModel) TableName() string {
+	return "personal_access_tokens"
+}
+func (*LoginAttemptModel) TableName() string {
+	return "login_attempts"
+}
+func (*TagModel) TableName() string     { return "tags" }
+func (*CommentModel) TableName() string { return "comments" }
+func (*ArticleRevisionModel) TableName() string {
+	return "article_revisions"
+}
+func (*ArticleTagModel) TableName() string  { return "article_tags" }
+func (*ArticleSlugModel) TableName() string { return "article_slugs" }
+func (*UserModel) TableName() string        { return "users" }
+func (*FollowModel) TableName() string      { return "follows" }
+func (*CategorySubscriptionModel) TableName() string {
+	return "category_subscriptions"
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepository struct {
+	DB *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		DB: db,
+	}
+}
+
+func (r *PasswordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	const op = "PasswordResetRepository.Create"
+	tokenModel := toPasswordResetTokenModel(token)
+	result := r.DB.WithContext(ctx).Create(tokenModel)
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	token.ID = tokenModel.ID
+	token.CreatedAt = tokenModel.CreatedAt
+	return nil
+}
+
+func (r *PasswordResetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	const op = "PasswordResetRepository.GetByTokenHash"
+	var tokenModel PasswordResetTokenModel
+	result := r.DB.WithContext(ctx).First(&tokenModel, "token_hash = ?", tokenHash)
+	if err := checkGetQueryResult(result, e.ErrPasswordResetTokenInvalid); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toPasswordResetTokenEntity(&tokenModel), nil
+}
+
+// MarkUsed гасит токен. Условие на used_at не даёт использовать токен дважды
+// при параллельных запросах
+func (r *PasswordResetRepository) MarkUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	const op = "PasswordResetRepository.MarkUsed"
+	result := r.DB.WithContext(ctx).
+		Model(&PasswordResetTokenModel{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if err := checkChangeQueryResult(result, e.ErrPasswordResetTokenInvalid); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Гасит все неиспользованные токены пользователя
+func (r *PasswordResetRepository) InvalidateByUser(ctx context.Context, userID uint, at time.Time) error {
+	const op = "PasswordResetRepository.InvalidateByUser"
+	result := r.DB.WithContext(ctx).
+		Model(&PasswordResetTokenModel{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at)
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func toPasswordResetTokenModel(t *domain.PasswordResetToken) *PasswordResetTokenModel {
+	return &PasswordResetTokenModel{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		TokenHash: t.TokenHash,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+	}
+}
+
+func toPasswordResetTokenEntity(t *PasswordResetTokenModel) *domain.PasswordResetToken {
+	return &domain.PasswordResetToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		TokenHash: t.TokenHash,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository struct {
+	DB *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		DB: db,
+	}
+}
+
+// Полностью заменяет набор кодов пользователя
+func (r *RecoveryCodeRepository) ReplaceByUser(ctx context.Context, userID uint, codes []*domain.RecoveryCode) error {
+	const op = "RecoveryCodeRepository.ReplaceByUser"
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error; err != nil {
+			return err
+		}
+
+		if len(codes) == 0 {
+			return nil
+		}
+
+		models := make([]RecoveryCodeModel, len(codes))
+		for i, code := range codes {
+			models[i] = *toRecoveryCodeModel(code)
+		}
+
+		return tx.Create(&models).Error
+	})
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Use гасит код. Условие на used_at не даёт использовать код дважды
+// при параллельных запросах
+func (r *RecoveryCodeRepository) Use(ctx context.Context, userID uint, codeHash string, usedAt time.Time) error {
+	const op = "RecoveryCodeRepository.Use"
+	result := r.DB.WithContext(ctx).
+		Model(&RecoveryCodeModel{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if err := checkChangeQueryResult(result, e.ErrTwoFactorCodeInvalid); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	const op = "RecoveryCodeRepository.DeleteByUser"
+	result := r.DB.WithContext(ctx).Where("user_id = ?", userID).Delete(&RecoveryCodeModel{})
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func toRecoveryCodeModel(c *domain.RecoveryCode) *RecoveryCodeModel {
+	return &RecoveryCodeModel{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		CodeHash:  c.CodeHash,
+		CreatedAt: c.CreatedAt,
+		UsedAt:    c.UsedAt,
+	}
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevisionRepository struct {
+	DB *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) *RevisionRepository {
+	return &RevisionRepository{
+		DB: db,
+	}
+}
+
+// Ревизии статьи от новых к старым, без содержимого
+func (r *RevisionRepository) ListByArticle(ctx context.Context, articleID uint) ([]domain.ArticleRevision, error) {
+	const op = "RevisionRepository.ListByArticle"
+	var revisionModels []ArticleRevisionModel
+	result := r.DB.WithContext(ctx).
+		Omit("content").
+		Preload("Editor").
+		Where("article_id = ?", articleID).
+		Order("number DESC").
+		Find(&revisionModels)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	revisions := make([]domain.ArticleRevision, 0, len(revisionModels))
+	for _, model := range revisionModels {
+		revisions = append(revisions, *toRevisionEntity(&model))
+	}
+
+	return revisions, nil
+}
+
+func (r *RevisionRepository) GetByNumber(ctx context.Context, articleID uint, number int) (*domain.ArticleRevision, error) {
+	const op = "RevisionRepository.GetByNumber"
+	var revisionModel ArticleRevisionModel
+	result := r.DB.WithContext(ctx).
+		Preload("Editor").
+		First(&revisionModel, "article_id = ? AND number = ?", articleID, number)
+	if err := checkGetQueryResult(result, e.ErrRevisionNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toRevisionEntity(&revisionModel), nil
+}
+
+// createRevision вызывается внутри транзакции изменения статьи. Строка статьи блокируется,
+// чтобы параллельные правки не получили одинаковый номер ревизии
+func createRevision(tx *gorm.DB, revision *domain.ArticleRevision) error {
+	var locked ArticleModel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, "id = ?", revision.ArticleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return e.ErrArticleNotFound
+		}
+
+		return err
+	}
+
+	var last ArticleRevisionModel
+	err := tx.Where("article_id = ?", revision.ArticleID).Order("number DESC").First(&last).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		revision.Number = 1
+	case err != nil:
+		return err
+	default:
+		if toRevisionEntity(&last).SameContent(revision) {
+			return nil
+		}
+
+		revision.Number = last.Number + 1
+	}
+
+	return tx.Create(toRevisionModel(revision)).Error
+}
+
+func toRevisionModel(r *domain.ArticleRevision) *ArticleRevisionModel {
+	model := &ArticleRevisionModel{
+		ID:        r.ID,
+		CreatedAt: r.CreatedAt,
+		ArticleID: r.ArticleID,
+		Number:    r.Number,
+		Title:     r.Title,
+		Content:   r.Content,
+	}
+
+	if r.CategoryID != 0 {
+		categoryID := r.CategoryID
+		model.CategoryID = &categoryID
+	}
+
+	if r.EditorID != 0 {
+		editorID := r.EditorID
+		model.EditorID = &editorID
+	}
+
+	return model
+}
+
+func toRevisionEntity(r *ArticleRevisionModel) *domain.ArticleRevision {
+	entity := &domain.ArticleRevision{
+		ID:        r.ID,
+		CreatedAt: r.CreatedAt,
+		ArticleID: r.ArticleID,
+		Number:    r.Number,
+		Title:     r.Title,
+		Content:   r.Content,
+	}
+
+	if r.CategoryID != nil {
+		entity.CategoryID = *r.CategoryID
+	}
+
+	if r.EditorID != nil {
+		entity.EditorID = *r.EditorID
+	}
+
+	if r.Editor != nil {
+		entity.Editor = toUserEntity(r.Editor)
+	}
+
+	return entity
+}
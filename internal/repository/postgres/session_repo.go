@@ -4,6 +4,7 @@ import (
 	"context"
 	"my_blog_backend/internal/domain"
 	"my_blog_backend/pkg/e"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -33,10 +34,10 @@ func (s *SessionRepository) Create(ctx context.Context, session *domain.Session)
 }
 
 // Получение сесси с помощью Id сессии
-func (s *SessionRepository) GetByID(ctx context.Context, sessionId uint) (*domain.Session, error) {
+func (s *SessionRepository) GetByID(ctx context.Context, sessionId uuid.UUID) (*domain.Session, error) {
 	const op = "SessionRepository.GetByID"
 	var sessionModel SessionModel
-	result := s.DB.WithContext(ctx).First(&sessionModel, sessionId)
+	result := s.DB.WithContext(ctx).First(&sessionModel, "id = ?", sessionId)
 	if err := checkGetQueryResult(result, e.ErrSessionNotFound); err != nil {
 		return nil, e.Wrap(op, err)
 	}
@@ -56,11 +57,13 @@ func (s *SessionRepository) GetByRefreshTokenHash(ctx context.Context, refreshTo
 	return toSessionEntity(&sessionModel), nil
 }
 
-// Функция аннулирует сессию
-// Используется при смене пароля, выхода из аккаунта/всех устройств
-func (s *SessionRepository) RevokeSession(ctx context.Context, id uuid.UUID) error {
+// Функция аннулирует активную сессию при выходе из аккаунта и ротации refresh токена.
+// Если сессия уже отозвана, возвращается ErrSessionNotFound: так ротация замечает,
+// что токен параллельно использовал кто-то ещё. Все сессии пользователя (например,
+// при смене пароля) отзывает RevokeAllByUser
+func (s *SessionRepository) RevokeSession(ctx context.Context, id uuid.UUID, reason domain.SessionRevokeReason) error {
 	const op = "SessionRepository.RevokeSession"
-	result := s.DB.WithContext(ctx).Model(&SessionModel{}).Where("id = ?", id).Update("is_revoked", true)
+	result := s.DB.WithContext(ctx).Model(&SessionModel{}).Where("id = ? AND is_revoked = FALSE", id).Updates(revokeUpdates(reason))
 	if err := checkChangeQueryResult(result, e.ErrSessionNotFound); err != nil {
 		return e.Wrap(op, err)
 	}
@@ -68,6 +71,88 @@ func (s *SessionRepository) RevokeSession(ctx context.Context, id uuid.UUID) err
 	return nil
 }
 
+// Активные сессии пользователя, недавно использованные первыми
+func (s *SessionRepository) ListActiveByUser(ctx context.Context, userId uint, now time.Time) ([]domain.Session, error) {
+	const op = "SessionRepository.ListActiveByUser"
+	var sessionModels []SessionModel
+	result := s.DB.WithContext(ctx).
+		Where("user_id = ? AND is_revoked = FALSE AND expires_at > ?", userId, now).
+		Order("last_used_at DESC").
+		Find(&sessionModels)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	sessions := make([]domain.Session, 0, len(sessionModels))
+	for _, model := range sessionModels {
+		sessions = append(sessions, *toSessionEntity(&model))
+	}
+
+	return sessions, nil
+}
+
+// ListByUser возвращает все сессии пользователя, включая отозванные и истёкшие
+func (s *SessionRepository) ListByUser(ctx context.Context, userId uint) ([]domain.Session, error) {
+	const op = "SessionRepository.ListByUser"
+	var sessionModels []SessionModel
+	result := s.DB.WithContext(ctx).
+		Where("user_id = ?", userId).
+		Order("created_at DESC").
+		Find(&sessionModels)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	sessions := make([]domain.Session, 0, len(sessionModels))
+	for _, model := range sessionModels {
+		sessions = append(sessions, *toSessionEntity(&model))
+	}
+
+	return sessions, nil
+}
+
+// Аннулирует сессию, только если она принадлежит пользователю
+func (s *SessionRepository) RevokeUserSession(ctx context.Context, userId uint, id uuid.UUID) error {
+	const op = "SessionRepository.RevokeUserSession"
+	result := s.DB.WithContext(ctx).
+		Model(&SessionModel{}).
+		Where("id = ? AND user_id = ? AND is_revoked = FALSE", id, userId).
+		Updates(revokeUpdates(domain.SessionRevoked))
+	if err := checkChangeQueryResult(result, e.ErrSessionNotFound); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Аннулирует все сессии пользователя, кроме exceptId. uuid.Nil - аннулировать все
+func (s *SessionRepository) RevokeAllByUser(ctx context.Context, userId uint, exceptId uuid.UUID) (int64, error) {
+	const op = "SessionRepository.RevokeAllByUser"
+	result := s.DB.WithContext(ctx).
+		Model(&SessionModel{}).
+		Where("user_id = ? AND is_revoked = FALSE AND id <> ?", userId, exceptId).
+		Updates(revokeUpdates(domain.SessionRevoked))
+	if err := result.Error; err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Аннулирует все сессии семейства
+func (s *SessionRepository) RevokeFamily(ctx context.Context, familyId uuid.UUID) error {
+	const op = "SessionRepository.RevokeFamily"
+	result := s.DB.WithContext(ctx).
+		Model(&SessionModel{}).
+		Where("family_id = ? AND is_revoked = FALSE", familyId).
+		Updates(revokeUpdates(domain.SessionFamilyRevoked))
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
 // Удаление сессии
 func (s *SessionRepository) DeleteSession(ctx context.Context, id uuid.UUID) error {
 	const op = "SessionRepository.DeleteSession"
@@ -79,13 +164,25 @@ func (s *SessionRepository) DeleteSession(ctx context.Context, id uuid.UUID) err
 	return nil
 }
 
+func revokeUpdates(reason domain.SessionRevokeReason) map[string]interface{} {
+	return map[string]interface{}{
+		"is_revoked":    true,
+		"revoke_reason": reason,
+	}
+}
+
 func toSessionModel(s *domain.Session) *SessionModel {
 	return &SessionModel{
 		Id:               s.Id,
+		FamilyId:         s.FamilyId,
 		UserId:           s.UserId,
 		RefreshTokenHash: s.RefreshTokenHash,
 		IsRevoked:        s.IsRevoked,
+		RevokeReason:     s.RevokeReason,
+		UserAgent:        s.UserAgent,
+		IP:               s.IP,
 		CreatedAt:        s.CreatedAt,
+		LastUsedAt:       s.LastUsedAt,
 		ExpiresAt:        s.ExpiresAt,
 	}
 }
@@ -93,10 +190,15 @@ func toSessionModel(s *domain.Session) *SessionModel {
 func toSessionEntity(s *SessionModel) *domain.Session {
 	return &domain.Session{
 		Id:               s.Id,
+		FamilyId:         s.FamilyId,
 		UserId:           s.UserId,
 		RefreshTokenHash: s.RefreshTokenHash,
 		IsRevoked:        s.IsRevoked,
+		RevokeReason:     s.RevokeReason,
+		UserAgent:        s.UserAgent,
+		IP:               s.IP,
 		CreatedAt:        s.CreatedAt,
+		LastUsedAt:       s.LastUsedAt,
 		ExpiresAt:        s.ExpiresAt,
 	}
 }
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository struct {
+	DB *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{
+		DB: db,
+	}
+}
+
+func (t *TagRepository) GetBySlug(ctx context.Context, slug string) (*domain.Tag, error) {
+	const op = "TagRepository.GetBySlug"
+	var tagModel TagModel
+	result := t.DB.WithContext(ctx).First(&tagModel, "slug = ?", slug)
+	if err := checkGetQueryResult(result, e.ErrTagNotFound); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toTagEntity(&tagModel), nil
+}
+
+// getOrCreateTags создаёт недостающие теги и возвращает все переданные теги с их ID.
+// Существующие теги не изменяются: имя остаётся тем, с которым тег был создан впервые
+func getOrCreateTags(tx *gorm.DB, tags []*domain.Tag) ([]domain.Tag, error) {
+	if len(tags) == 0 {
+		return []domain.Tag{}, nil
+	}
+
+	tagModels := make([]TagModel, len(tags))
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		tagModels[i] = *toTagModel(tag)
+		slugs[i] = tag.Slug
+	}
+
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoNothing: true,
+	}).Create(&tagModels)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+
+	var existing []TagModel
+	if err := tx.Where("slug IN ?", slugs).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	bySlug := make(map[string]*TagModel, len(existing))
+	for i := range existing {
+		bySlug[existing[i].Slug] = &existing[i]
+	}
+
+	res := make([]domain.Tag, 0, len(tags))
+	for _, slug := range slugs {
+		if model, ok := bySlug[slug]; ok {
+			res = append(res, *toTagEntity(model))
+		}
+	}
+
+	return res, nil
+}
+
+// replaceArticleTags создаёт недостающие теги и заменяет ими набор тегов статьи.
+// Вызывается внутри транзакции создания или правки статьи
+func replaceArticleTags(tx *gorm.DB, articleID uint, tags []*domain.Tag) ([]domain.Tag, error) {
+	stored, err := getOrCreateTags(tx, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("article_id = ?", articleID).Delete(&ArticleTagModel{}).Error; err != nil {
+		return nil, err
+	}
+
+	if len(stored) == 0 {
+		return stored, nil
+	}
+
+	links := make([]ArticleTagModel, len(stored))
+	for i, tag := range stored {
+		links[i] = ArticleTagModel{ArticleID: articleID, TagID: tag.ID}
+	}
+
+	if err := tx.Create(&links).Error; err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func toTagModel(t *domain.Tag) *TagModel {
+	return &TagModel{
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt,
+		Name:      t.Name,
+		Slug:      t.Slug,
+	}
+}
+
+func toTagEntity(t *TagModel) *domain.Tag {
+	return &domain.Tag{
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt,
+		Name:      t.Name,
+		Slug:      t.Slug,
+	}
+}
@@ -2,12 +2,17 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"log"
 	"my_blog_backend/internal/domain"
 	"my_blog_backend/pkg/e"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -49,24 +54,34 @@ func (u *UserRepository) GetByUsername(ctx context.Context, username string) (*d
 	return u.getUser(ctx, op, query)
 }
 
+func (u *UserRepository) GetGhost(ctx context.Context) (*domain.User, error) {
+	const op = "UserRepository.GetGhost"
+	query := u.DB.WithContext(ctx).Where("is_system = TRUE")
+	return u.getUser(ctx, op, query)
+}
+
+// Update сохраняет учётные данные и профиль. Роль, пароль, 2FA, блокировка и удаление
+// аккаунта пишутся отдельными методами только в свои столбцы, иначе параллельное
+// изменение профиля вернуло бы им прочитанные раньше значения
 func (u *UserRepository) Update(ctx context.Context, user *domain.User) (*domain.User, error) {
 	const op = "UserRepository.Update"
 
 	userModel := toUserModel(user)
 	updates := map[string]interface{}{
-		"username":      userModel.Username,
-		"email":         userModel.Email,
-		"password_hash": userModel.PasswordHash,
-		"role":          userModel.Role,
+		"username":          userModel.Username,
+		"email":             userModel.Email,
+		"email_verified_at": userModel.EmailVerifiedAt,
+		"pending_email":     userModel.PendingEmail,
+		"display_name":      userModel.DisplayName,
+		"bio":               userModel.Bio,
+		"avatar_url":        userModel.AvatarURL,
+		"website":           userModel.Website,
+		"location":          userModel.Location,
+		"social_links":      userModel.SocialLinks,
 	}
 
-	result := u.DB.WithContext(ctx).Model(&UserModel{}).Where("id = ?", userModel.ID).Updates(updates)
-	if err := checkChangeQueryResult(result, e.ErrUserNotFound); err != nil {
-		if errors.Is(err, e.ErrUserNotFound) {
-			return nil, e.Wrap(op, err)
-		}
-
-		return nil, e.Wrap(op, errUserDuplicate(err))
+	if err := u.updateColumns(ctx, user.ID, updates); err != nil {
+		return nil, e.Wrap(op, err)
 	}
 
 	newUserData, err := u.GetById(ctx, user.ID)
@@ -77,11 +92,115 @@ func (u *UserRepository) Update(ctx context.Context, user *domain.User) (*domain
 	return newUserData, nil
 }
 
+func (u *UserRepository) SetRole(ctx context.Context, id uint, role domain.Role) (*domain.User, error) {
+	const op = "UserRepository.SetRole"
+
+	if err := u.updateColumns(ctx, id, map[string]interface{}{"role": role}); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	user, err := u.GetById(ctx, id)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return user, nil
+}
+
+func (u *UserRepository) SetPasswordHash(ctx context.Context, id uint, hash string) error {
+	const op = "UserRepository.SetPasswordHash"
+
+	if err := u.updateColumns(ctx, id, map[string]interface{}{"password_hash": hash}); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (u *UserRepository) SetTOTP(ctx context.Context, id uint, secret *string, enabledAt *time.Time) error {
+	const op = "UserRepository.SetTOTP"
+
+	updates := map[string]interface{}{
+		"totp_secret":     secret,
+		"totp_enabled_at": enabledAt,
+	}
+	if err := u.updateColumns(ctx, id, updates); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (u *UserRepository) SetSuspendedAt(ctx context.Context, id uint, suspendedAt *time.Time) (*domain.User, error) {
+	const op = "UserRepository.SetSuspendedAt"
+
+	if err := u.updateColumns(ctx, id, map[string]interface{}{"suspended_at": suspendedAt}); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	user, err := u.GetById(ctx, id)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return user, nil
+}
+
+func (u *UserRepository) SetDeletionSchedule(ctx context.Context, id uint, scheduledAt *time.Time, articles domain.ArticleDisposal) (*domain.User, error) {
+	const op = "UserRepository.SetDeletionSchedule"
+
+	updates := map[string]interface{}{
+		"deletion_scheduled_at": scheduledAt,
+		"deletion_articles":     articles,
+	}
+	if err := u.updateColumns(ctx, id, updates); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	user, err := u.GetById(ctx, id)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return user, nil
+}
+
+func (u *UserRepository) updateColumns(ctx context.Context, id uint, updates map[string]interface{}) error {
+	result := u.DB.WithContext(ctx).Model(&UserModel{}).Where("id = ?", id).Updates(updates)
+	if err := checkChangeQueryResult(result, e.ErrUserNotFound); err != nil {
+		if errors.Is(err, e.ErrUserNotFound) {
+			return err
+		}
+
+		return errUserDuplicate(err)
+	}
+
+	return nil
+}
+
 func (u *UserRepository) Delete(ctx context.Context, id uint) error {
 	const op = "UserRepository.Delete"
 
 	result := u.DB.WithContext(ctx).Delete(&UserModel{}, id)
-	err := checkChangeQueryResult(result, e.ErrUserNotFound)
+	if err := postgresForeignKeyViolation(result, e.ErrUserHasArticles); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := checkChangeQueryResult(result, e.ErrUserNotFound); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// DeleteWithArticles удаляет пользователя вместе с его статьями или,
+// если задан reassignTo, сначала передаёт статьи этому пользователю
+func (u *UserRepository) DeleteWithArticles(ctx context.Context, id uint, reassignTo *uint) error {
+	const op = "UserRepository.DeleteWithArticles"
+
+	err := u.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteWithArticles(tx, id, reassignTo)
+	})
 	if err != nil {
 		return e.Wrap(op, err)
 	}
@@ -89,6 +208,100 @@ func (u *UserRepository) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
+// PurgeScheduled блокирует строку пользователя до конца транзакции: отмена удаления,
+// пришедшая после выборки ListDueForDeletion, либо успеет раньше и удаление не
+// состоится, либо дождётся его и не найдёт пользователя
+func (u *UserRepository) PurgeScheduled(ctx context.Context, id uint, reassignTo *uint, now time.Time) error {
+	const op = "UserRepository.PurgeScheduled"
+
+	err := u.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked UserModel
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).
+			Limit(1).
+			Find(&locked, "id = ?", id)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			return e.ErrAccountDeletionNotScheduled
+		}
+
+		return deleteWithArticles(tx, id, reassignTo)
+	})
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func deleteWithArticles(tx *gorm.DB, id uint, reassignTo *uint) error {
+	articles := tx.Model(&ArticleModel{}).Where("author_id = ?", id)
+
+	var result *gorm.DB
+	if reassignTo != nil {
+		result = articles.Update("author_id", *reassignTo)
+	} else {
+		result = tx.Where("author_id = ?", id).Delete(&ArticleModel{})
+	}
+	if err := result.Error; err != nil {
+		return err
+	}
+
+	result = tx.Delete(&UserModel{}, id)
+	return checkChangeQueryResult(result, e.ErrUserNotFound)
+}
+
+func (u *UserRepository) List(ctx context.Context, filter domain.UserListFilter, page domain.Page) ([]domain.User, *domain.Cursor, error) {
+	const op = "UserRepository.List"
+
+	query := u.DB.WithContext(ctx).Model(&UserModel{})
+	if filter.Role != nil {
+		query = query.Where("role = ?", *filter.Role)
+	}
+	if filter.UsernamePrefix != "" {
+		query = query.Where("lower(username) LIKE ?", escapeLike(strings.ToLower(filter.UsernamePrefix))+"%")
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.Suspended != nil {
+		if *filter.Suspended {
+			query = query.Where("suspended_at IS NOT NULL")
+		} else {
+			query = query.Where("suspended_at IS NULL")
+		}
+	}
+	if page.After != nil {
+		query = query.Where("(created_at, id) < (?, ?)", page.After.CreatedAt, page.After.ID)
+	}
+
+	var userModels []UserModel
+	result := query.Order("created_at DESC").Order("id DESC").Limit(page.Limit + 1).Find(&userModels)
+	if err := checkGetQueryResult(result, e.ErrUserNotFound); err != nil {
+		return nil, nil, e.Wrap(op, err)
+	}
+
+	var next *domain.Cursor
+	if len(userModels) > page.Limit {
+		userModels = userModels[:page.Limit]
+		last := userModels[len(userModels)-1]
+		next = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	users := make([]domain.User, 0, len(userModels))
+	for _, model := range userModels {
+		users = append(users, *toUserEntity(&model))
+	}
+
+	return users, next, nil
+}
+
 func (u *UserRepository) ExistsByEmailOrUsername(ctx context.Context, email, username string) error {
 	const op = "UserRepository.ExistsByEmailOrUsername"
 
@@ -120,27 +333,141 @@ func (u *UserRepository) ExistsByEmailOrUsername(ctx context.Context, email, use
 	return nil
 }
 
+// UseTOTPStep запоминает шаг принятого TOTP кода. Условие на шаг не даёт
+// использовать один код дважды, в том числе параллельными запросами
+func (u *UserRepository) UseTOTPStep(ctx context.Context, userID uint, step int64) error {
+	const op = "UserRepository.UseTOTPStep"
+
+	result := u.DB.WithContext(ctx).
+		Model(&UserModel{}).
+		Where("id = ? AND totp_last_used_step < ?", userID, step).
+		Update("totp_last_used_step", step)
+	if err := checkChangeQueryResult(result, e.ErrTwoFactorCodeInvalid); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// ListDueForDeletion возвращает аккаунты, у которых истёк срок до удаления
+func (u *UserRepository) ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]domain.User, error) {
+	const op = "UserRepository.ListDueForDeletion"
+
+	var userModels []UserModel
+	result := u.DB.WithContext(ctx).
+		Where("deletion_scheduled_at <= ?", now).
+		Order("deletion_scheduled_at").
+		Limit(limit).
+		Find(&userModels)
+	if err := result.Error; err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	users := make([]domain.User, 0, len(userModels))
+	for _, model := range userModels {
+		users = append(users, *toUserEntity(&model))
+	}
+
+	return users, nil
+}
+
+// Если пароль успели сменить параллельно, ничего не меняется
+func (u *UserRepository) ReplacePasswordHash(ctx context.Context, userID uint, oldHash, newHash string) error {
+	const op = "UserRepository.ReplacePasswordHash"
+
+	result := u.DB.WithContext(ctx).
+		Model(&UserModel{}).
+		Where("id = ? AND password_hash = ?", userID, oldHash).
+		UpdateColumn("password_hash", newHash)
+	if err := result.Error; err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
 func toUserModel(u *domain.User) *UserModel {
 	return &UserModel{
-		ID:           u.ID,
-		CreatedAt:    u.CreatedAt,
-		UpdatedAt:    u.UpdatedAt,
-		Role:         u.Role,
-		Username:     u.Username,
-		Email:        u.Email,
-		PasswordHash: u.PasswordHash,
+		ID:                  u.ID,
+		CreatedAt:           u.CreatedAt,
+		UpdatedAt:           u.UpdatedAt,
+		Role:                u.Role,
+		Username:            u.Username,
+		Email:               u.Email,
+		PasswordHash:        u.PasswordHash,
+		EmailVerifiedAt:     u.EmailVerifiedAt,
+		PendingEmail:        u.PendingEmail,
+		TOTPSecret:          u.TOTPSecret,
+		TOTPEnabledAt:       u.TOTPEnabledAt,
+		SuspendedAt:         u.SuspendedAt,
+		DeletionScheduledAt: u.DeletionScheduledAt,
+		DeletionArticles:    u.DeletionArticles,
+		DisplayName:         u.Profile.DisplayName,
+		Bio:                 u.Profile.Bio,
+		AvatarURL:           u.Profile.AvatarURL,
+		Website:             u.Profile.Website,
+		Location:            u.Profile.Location,
+		SocialLinks:         toSocialLinksModel(u.Profile.Links),
+		IsSystem:            u.IsSystem,
+	}
+}
+
+func toSocialLinksModel(links []domain.ProfileLink) string {
+	models := make([]ProfileLinkModel, len(links))
+	for i, link := range links {
+		models[i] = ProfileLinkModel{Label: link.Label, URL: link.URL}
+	}
+
+	// Структура из строк сериализуется без ошибок
+	raw, _ := json.Marshal(models)
+	return string(raw)
+}
+
+func toProfileEntity(u *UserModel) domain.Profile {
+	profile := domain.Profile{
+		DisplayName: u.DisplayName,
+		Bio:         u.Bio,
+		AvatarURL:   u.AvatarURL,
+		Website:     u.Website,
+		Location:    u.Location,
 	}
+
+	if u.SocialLinks == "" {
+		return profile
+	}
+
+	var links []ProfileLinkModel
+	if err := json.Unmarshal([]byte(u.SocialLinks), &links); err != nil {
+		log.Printf("user %d: invalid social_links: %v", u.ID, err)
+		return profile
+	}
+
+	profile.Links = make([]domain.ProfileLink, len(links))
+	for i, link := range links {
+		profile.Links[i] = domain.ProfileLink{Label: link.Label, URL: link.URL}
+	}
+
+	return profile
 }
 
 func toUserEntity(u *UserModel) *domain.User {
 	return &domain.User{
-		ID:           u.ID,
-		CreatedAt:    u.CreatedAt,
-		UpdatedAt:    u.UpdatedAt,
-		Role:         u.Role,
-		Username:     u.Username,
-		Email:        u.Email,
-		PasswordHash: u.PasswordHash,
+		ID:                  u.ID,
+		CreatedAt:           u.CreatedAt,
+		UpdatedAt:           u.UpdatedAt,
+		Role:                u.Role,
+		Username:            u.Username,
+		Email:               u.Email,
+		PasswordHash:        u.PasswordHash,
+		EmailVerifiedAt:     u.EmailVerifiedAt,
+		PendingEmail:        u.PendingEmail,
+		TOTPSecret:          u.TOTPSecret,
+		TOTPEnabledAt:       u.TOTPEnabledAt,
+		SuspendedAt:         u.SuspendedAt,
+		DeletionScheduledAt: u.DeletionScheduledAt,
+		DeletionArticles:    u.DeletionArticles,
+		Profile:             toProfileEntity(u),
+		IsSystem:            u.IsSystem,
 	}
 }
 
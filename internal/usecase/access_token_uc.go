@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAccessTokenTTL = 90 * 24 * time.Hour
+	maxAccessTokenTTL     = 365 * 24 * time.Hour
+	// Как часто обновлять last_used_at
+	accessTokenTouchInterval = time.Minute
+)
+
+type AccessTokenService struct {
+	tokenRepo    repository.AccessTokenRepository
+	userRepo     repository.UserRepository
+	tokenManager TokenManager
+	clock        Clock
+}
+
+func NewAccessTokenService(t repository.AccessTokenRepository, u repository.UserRepository, tm TokenManager, clock Clock) *AccessTokenService {
+	return &AccessTokenService{
+		tokenRepo:    t,
+		userRepo:     u,
+		tokenManager: tm,
+		clock:        clock,
+	}
+}
+
+// IsAccessToken отличает персональный токен от JWT
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, domain.AccessTokenPrefix)
+}
+
+// Create выпускает токен. Сам токен возвращается только здесь
+func (s *AccessTokenService) Create(ctx context.Context, userId uint, req *CreateAccessTokenReq) (*CreatedAccessTokenRes, error) {
+	const op = "AccessTokenService.Create"
+
+	scopes, err := domain.ParseScopes(req.Scopes)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	ttl := defaultAccessTokenTTL
+	if req.ExpiresIn != nil {
+		ttl = *req.ExpiresIn
+	}
+	if ttl <= 0 || ttl > maxAccessTokenTTL {
+		return nil, e.Wrap(op, e.ErrAccessTokenTTLInvalid)
+	}
+
+	count, err := s.tokenRepo.CountActiveByUser(ctx, userId, s.clock.Now())
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	if count >= domain.MaxAccessTokensPerUser {
+		return nil, e.Wrap(op, e.ErrTooManyAccessTokens)
+	}
+
+	secret, _, err := s.tokenManager.NewRefreshToken()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	token := domain.AccessTokenPrefix + secret
+
+	accessToken, err := domain.NewAccessToken(userId, req.Name, s.tokenManager.HashRefreshToken(token), scopes, s.clock.Now().Add(ttl))
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	accessToken, err = s.tokenRepo.Create(ctx, accessToken)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return &CreatedAccessTokenRes{
+		Token:       token,
+		AccessToken: *toAccessTokenRes(accessToken),
+	}, nil
+}
+
+func (s *AccessTokenService) List(ctx context.Context, userId uint) ([]*AccessTokenRes, error) {
+	const op = "AccessTokenService.List"
+
+	tokens, err := s.tokenRepo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	res := make([]*AccessTokenRes, len(tokens))
+	for i := range tokens {
+		res[i] = toAccessTokenRes(&tokens[i])
+	}
+
+	return res, nil
+}
+
+func (s *AccessTokenService) Get(ctx context.Context, userId, id uint) (*AccessTokenRes, error) {
+	const op = "AccessTokenService.Get"
+
+	token, err := s.tokenRepo.GetByID(ctx, userId, id)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toAccessTokenRes(token), nil
+}
+
+// Update меняет только название: скоупы и срок задаются при выпуске
+func (s *AccessTokenService) Update(ctx context.Context, userId, id uint, req *UpdateAccessTokenReq) (*AccessTokenRes, error) {
+	const op = "AccessTokenService.Update"
+
+	token, err := s.tokenRepo.GetByID(ctx, userId, id)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := token.Rename(req.Name); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	token, err = s.tokenRepo.Update(ctx, token)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toAccessTokenRes(token), nil
+}
+
+func (s *AccessTokenService) Delete(ctx context.Context, userId, id uint) error {
+	const op = "AccessTokenService.Delete"
+
+	if err := s.tokenRepo.Delete(ctx, userId, id); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Authenticate проверяет персональный токен из заголовка Authorization
+func (s *AccessTokenService) Authenticate(ctx context.Context, token string) (*AuthenticatedUser, error) {
+	const op = "AccessTokenService.Authenticate"
+
+	accessToken, err := s.tokenRepo.GetByTokenHash(ctx, s.tokenManager.HashRefreshToken(token))
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	now := s.clock.Now()
+	if err := accessToken.ValidateState(now); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	// Роль берём из базы: с момента выпуска токена её могли изменить
+	user, err := s.userRepo.GetById(ctx, accessToken.UserID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if user.IsSuspended() {
+		return nil, e.Wrap(op, e.ErrAccessTokenInvalid)
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(ctx, accessToken.ID, now, accessTokenTouchInterval); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return &AuthenticatedUser{
+		ID:        user.ID,
+		SessionID: uuid.Nil,
+		Role:      user.Role,
+		Email:     user.Email,
+		Scopes:    accessToken.Scopes,
+	}, nil
+}
+
+func toAccessTokenRes(token *domain.AccessToken) *AccessTokenRes {
+	return &AccessTokenRes{
+		Id:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"testing"
+	"time"
+)
+
+// accessTokenRepo хранит токены в памяти и считает активные так же, как postgres
+type accessTokenRepo struct {
+	repository.AccessTokenRepository
+
+	tokens []domain.AccessToken
+}
+
+func (r *accessTokenRepo) CountActiveByUser(_ context.Context, userID uint, now time.Time) (int64, error) {
+	var count int64
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.ExpiresAt.After(now) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *accessTokenRepo) Create(_ context.Context, token *domain.AccessToken) (*domain.AccessToken, error) {
+	token.ID = uint(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, *token)
+	return token, nil
+}
+
+func TestAccessTokenServiceCreateLimitIgnoresExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		expired int
+		active  int
+		wantErr error
+	}{
+		{name: "only expired tokens", expired: domain.MaxAccessTokensPerUser},
+		{name: "one slot left", expired: 5, active: domain.MaxAccessTokensPerUser - 1},
+		{name: "limit reached", expired: 5, active: domain.MaxAccessTokensPerUser, wantErr: e.ErrTooManyAccessTokens},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &accessTokenRepo{}
+			for i := 0; i < tt.expired; i++ {
+				repo.tokens = append(repo.tokens, domain.AccessToken{UserID: 1, ExpiresAt: now.Add(-time.Hour)})
+			}
+			for i := 0; i < tt.active; i++ {
+				repo.tokens = append(repo.tokens, domain.AccessToken{UserID: 1, ExpiresAt: now.Add(time.Hour)})
+			}
+
+			s := NewAccessTokenService(repo, nil, &refreshTokenManager{}, fixedClock{now: now})
+			_, err := s.Create(context.Background(), 1, &CreateAccessTokenReq{
+				Name:   "ci",
+				Scopes: []string{string(domain.ScopeArticlesRead)},
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		})
+	}
+}
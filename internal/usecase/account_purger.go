@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountPurger периодически удаляет аккаунты, у которых истёк срок до удаления.
+// Статьи либо удаляются, либо передаются служебному пользователю (см. User.IsGhost)
+type AccountPurger struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	clock       Clock
+	interval    time.Duration
+	batchSize   int
+}
+
+func NewAccountPurger(u repository.UserRepository, s repository.SessionRepository, clock Clock, interval time.Duration, batchSize int) *AccountPurger {
+	return &AccountPurger{
+		userRepo:    u,
+		sessionRepo: s,
+		clock:       clock,
+		interval:    interval,
+		batchSize:   batchSize,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (p *AccountPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.PurgeDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("account purger: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeDue удаляет все аккаунты, срок удаления которых уже наступил, и возвращает их количество
+func (p *AccountPurger) PurgeDue(ctx context.Context) (int, error) {
+	const op = "AccountPurger.PurgeDue"
+
+	now := p.clock.Now()
+	purged := 0
+	var ghost *domain.User
+	for {
+		users, err := p.userRepo.ListDueForDeletion(ctx, now, p.batchSize)
+		if err != nil {
+			return purged, e.Wrap(op, err)
+		}
+
+		for _, user := range users {
+			var reassignTo *uint
+			if user.DeletionArticles != domain.ArticlesDelete {
+				if ghost == nil {
+					if ghost, err = p.userRepo.GetGhost(ctx); err != nil {
+						return purged, e.Wrap(op, err)
+					}
+				}
+				reassignTo = &ghost.ID
+			}
+
+			if err := p.userRepo.PurgeScheduled(ctx, user.ID, reassignTo, now); err != nil {
+				// Удаление успели отменить или аккаунт уже удалён
+				if errors.Is(err, e.ErrAccountDeletionNotScheduled) || errors.Is(err, e.ErrUserNotFound) {
+					continue
+				}
+
+				return purged, e.Wrap(op, err)
+			}
+
+			// У сессий нет внешнего ключа на users, поэтому отзываем их явно
+			if _, err := p.sessionRepo.RevokeAllByUser(ctx, user.ID, uuid.Nil); err != nil {
+				return purged, e.Wrap(op, err)
+			}
+
+			purged++
+		}
+
+		if len(users) < p.batchSize {
+			return purged, nil
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const ghostUserID = 1000
+
+// purgerUserRepo хранит пользователей в памяти и повторяет проверку из PurgeScheduled
+type purgerUserRepo struct {
+	repository.UserRepository
+
+	users map[uint]*domain.User
+	// reassigned[id] - кому переданы статьи удалённого пользователя, 0 - статьи удалены
+	reassigned map[uint]uint
+	// afterList вызывается после выборки, чтобы смоделировать отмену удаления
+	afterList func(map[uint]*domain.User)
+}
+
+func (r *purgerUserRepo) ListDueForDeletion(_ context.Context, now time.Time, limit int) ([]domain.User, error) {
+	var due []domain.User
+	for _, user := range r.users {
+		if user.DeletionScheduledAt != nil && !user.DeletionScheduledAt.After(now) {
+			due = append(due, *user)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	if r.afterList != nil {
+		r.afterList(r.users)
+	}
+
+	return due, nil
+}
+
+// GetGhost возвращает служебного пользователя: по имени его больше не ищут
+func (r *purgerUserRepo) GetGhost(context.Context) (*domain.User, error) {
+	return &domain.User{ID: ghostUserID, Username: "deleted user", IsSystem: true}, nil
+}
+
+func (r *purgerUserRepo) PurgeScheduled(_ context.Context, id uint, reassignTo *uint, now time.Time) error {
+	user, ok := r.users[id]
+	if !ok {
+		return e.ErrUserNotFound
+	}
+	if user.DeletionScheduledAt == nil || user.DeletionScheduledAt.After(now) {
+		return e.ErrAccountDeletionNotScheduled
+	}
+
+	delete(r.users, id)
+	r.reassigned[id] = 0
+	if reassignTo != nil {
+		r.reassigned[id] = *reassignTo
+	}
+
+	return nil
+}
+
+type purgerSessionRepo struct {
+	repository.SessionRepository
+
+	revoked []uint
+}
+
+func (r *purgerSessionRepo) RevokeAllByUser(_ context.Context, userId uint, _ uuid.UUID) (int64, error) {
+	r.revoked = append(r.revoked, userId)
+	return 0, nil
+}
+
+func TestAccountPurgerPurgeDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	scheduled := func(id uint, at *time.Time, articles domain.ArticleDisposal) *domain.User {
+		return &domain.User{ID: id, DeletionScheduledAt: at, DeletionArticles: articles}
+	}
+
+	tests := []struct {
+		name       string
+		users      []*domain.User
+		batchSize  int
+		afterList  func(map[uint]*domain.User)
+		wantPurged int
+		// wantReassigned - удалённые пользователи и кому переданы их статьи
+		wantReassigned map[uint]uint
+	}{
+		{
+			name:           "due accounts are purged",
+			users:          []*domain.User{scheduled(1, &past, domain.ArticlesAnonymize), scheduled(2, &past, domain.ArticlesDelete)},
+			batchSize:      10,
+			wantPurged:     2,
+			wantReassigned: map[uint]uint{1: ghostUserID, 2: 0},
+		},
+		{
+			name:           "future and unscheduled accounts are left alone",
+			users:          []*domain.User{scheduled(1, &future, domain.ArticlesDelete), scheduled(2, nil, "")},
+			batchSize:      10,
+			wantReassigned: map[uint]uint{},
+		},
+		{
+			name:           "all batches are processed",
+			users:          []*domain.User{scheduled(1, &past, domain.ArticlesDelete), scheduled(2, &past, domain.ArticlesDelete), scheduled(3, &past, domain.ArticlesDelete)},
+			batchSize:      2,
+			wantPurged:     3,
+			wantReassigned: map[uint]uint{1: 0, 2: 0, 3: 0},
+		},
+		{
+			name:      "deletion cancelled after listing is skipped",
+			users:     []*domain.User{scheduled(1, &past, domain.ArticlesDelete), scheduled(2, &past, domain.ArticlesDelete)},
+			batchSize: 10,
+			afterList: func(users map[uint]*domain.User) {
+				users[1].DeletionScheduledAt = nil
+			},
+			wantPurged:     1,
+			wantReassigned: map[uint]uint{2: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &purgerUserRepo{
+				users:      make(map[uint]*domain.User),
+				reassigned: make(map[uint]uint),
+				afterList:  tt.afterList,
+			}
+			for _, user := range tt.users {
+				users.users[user.ID] = user
+			}
+			sessions := &purgerSessionRepo{}
+
+			purger := NewAccountPurger(users, sessions, fixedClock{now: now}, time.Minute, tt.batchSize)
+			purged, err := purger.PurgeDue(context.Background())
+			if err != nil {
+				t.Fatalf("PurgeDue() unexpected error: %v", err)
+			}
+
+			if purged != tt.wantPurged {
+				t.Errorf("PurgeDue() = %d, want %d", purged, tt.wantPurged)
+			}
+			if len(users.reassigned) != len(tt.wantReassigned) {
+				t.Errorf("purged users = %v, want %v", users.reassigned, tt.wantReassigned)
+			}
+			for id, want := range tt.wantReassigned {
+				got, ok := users.reassigned[id]
+				if !ok {
+					t.Errorf("user %d was not purged", id)
+					continue
+				}
+				if got != want {
+					t.Errorf("articles of user %d reassigned to %d, want %d", id, got, want)
+				}
+			}
+			if len(sessions.revoked) != tt.wantPurged {
+				t.Errorf("sessions revoked for %v, want %d users", sessions.revoked, tt.wantPurged)
+			}
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccountService - выгрузка данных и удаление аккаунта самим пользователем
+type AccountService struct {
+	userRepo    repository.UserRepository
+	articleRepo repository.ArticleRepository
+	commentRepo repository.CommentRepository
+	sessionRepo repository.SessionRepository
+	followRepo  repository.FollowRepository
+	subRepo     repository.CategorySubscriptionRepository
+	hashManager HashManager
+	clock       Clock
+	// Сколько аккаунт ждёт удаления, пока его ещё можно восстановить
+	gracePeriod time.Duration
+}
+
+func NewAccountService(u repository.UserRepository, a repository.ArticleRepository, c repository.CommentRepository, s repository.SessionRepository, f repository.FollowRepository, cs repository.CategorySubscriptionRepository, hm HashManager, clock Clock, gracePeriod time.Duration) *AccountService {
+	return &AccountService{
+		userRepo:    u,
+		articleRepo: a,
+		commentRepo: c,
+		sessionRepo: s,
+		followRepo:  f,
+		subRepo:     cs,
+		hashManager: hm,
+		clock:       clock,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// ScheduleDeletion после подтверждения паролем назначает удаление аккаунта
+// через gracePeriod. Само удаление выполняет AccountPurger
+func (s *AccountService) ScheduleDeletion(ctx context.Context, userId uint, req *DeleteAccountReq) (*UserRes, error) {
+	const op = "AccountService.ScheduleDeletion"
+
+	articles, err := domain.ParseArticleDisposal(req.Articles)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.hashManager.Compare(req.Password, user.PasswordHash); err != nil {
+		if errors.Is(err, e.ErrMismatchedHashAndPassword) {
+			return nil, e.Wrap(op, e.ErrInvalidCredentials)
+		}
+
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := user.ScheduleDeletion(s.clock.Now().Add(s.gracePeriod), articles); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updUser, err := s.userRepo.SetDeletionSchedule(ctx, user.ID, user.DeletionScheduledAt, user.DeletionArticles)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toUserResponse(updUser), nil
+}
+
+func (s *AccountService) CancelDeletion(ctx context.Context, userId uint) (*UserRes, error) {
+	const op = "AccountService.CancelDeletion"
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := user.CancelDeletion(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updUser, err := s.userRepo.SetDeletionSchedule(ctx, user.ID, user.DeletionScheduledAt, user.DeletionArticles)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toUserResponse(updUser), nil
+}
+
+// Export пишет в w ZIP архив с данными пользователя: profile.json, sessions.json,
+// comments.json, follows.json, category_subscriptions.json и статьи в articles/<slug>.md. Архив пишется по мере чтения данных,
+// поэтому ошибка в середине оставляет его обрезанным
+func (s *AccountService) Export(ctx context.Context, userId uint, w io.Writer) error {
+	const op = "AccountService.Export"
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	archive := zip.NewWriter(w)
+
+	if err := writeJSONFile(archive, "profile.json", toExportProfile(user)); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.exportArticles(ctx, archive, user.ID); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	sessions, err := s.sessionRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	exportSessions := make([]exportSession, len(sessions))
+	for i, session := range sessions {
+		exportSessions[i] = toExportSession(&session)
+	}
+
+	if err := writeJSONFile(archive, "sessions.json", exportSessions); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	comments, err := s.commentRepo.ListByAuthor(ctx, user.ID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	exportComments := make([]exportComment, len(comments))
+	for i, comment := range comments {
+		exportComments[i] = toExportComment(&comment)
+	}
+
+	if err := writeJSONFile(archive, "comments.json", exportComments); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	follows, err := s.exportFollows(ctx, user.ID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := writeJSONFile(archive, "follows.json", follows); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	subscriptions, err := s.subRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	exportSubscriptions := make([]exportCategorySubscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		exportSubscriptions[i] = toExportCategorySubscription(&subscription)
+	}
+
+	if err := writeJSONFile(archive, "category_subscriptions.json", exportSubscriptions); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := archive.Close(); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *AccountService) exportArticles(ctx context.Context, archive *zip.Writer, userId uint) error {
+	page := domain.Page{Limit: domain.MaxPageLimit}
+	for {
+		articles, next, err := s.articleRepo.ListByAuthor(ctx, userId, false, page)
+		if err != nil {
+			return err
+		}
+
+		for _, article := range articles {
+			file, err := archive.Create("articles/" + article.Slug + ".md")
+			if err != nil {
+				return err
+			}
+
+			if _, err := io.WriteString(file, articleMarkdown(&article)); err != nil {
+				return err
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+		page.After = next
+	}
+}
+
+// exportFollows собирает авторов, на которых подписан пользователь, и его подписчиков
+func (s *AccountService) exportFollows(ctx context.Context, userId uint) (*exportFollows, error) {
+	res := &exportFollows{
+		Following: []exportFollow{},
+		Followers: []exportFollow{},
+	}
+
+	page := domain.Page{Limit: domain.MaxPageLimit}
+	for {
+		follows, next, err := s.followRepo.ListFollowing(ctx, userId, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, follow := range follows {
+			res.Following = append(res.Following, toExportFollow(follow.FolloweeID, follow.Followee, follow.CreatedAt))
+		}
+
+		if next == nil {
+			break
+		}
+		page.After = next
+	}
+
+	page = domain.Page{Limit: domain.MaxPageLimit}
+	for {
+		follows, next, err := s.followRepo.ListFollowers(ctx, userId, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, follow := range follows {
+			res.Followers = append(res.Followers, toExportFollow(follow.FollowerID, follow.Follower, follow.CreatedAt))
+		}
+
+		if next == nil {
+			return res, nil
+		}
+		page.After = next
+	}
+}
+
+// articleMarkdown возвращает исходник статьи с метаданными во front matter.
+// Строки в двойных кавычках Go совместимы с YAML
+func articleMarkdown(article *domain.Article) string {
+	var b strings.Builder
+
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(article.Title))
+	fmt.Fprintf(&b, "slug: %s\n", strconv.Quote(article.Slug))
+	fmt.Fprintf(&b, "status: %s\n", article.Status)
+	if article.Category != nil {
+		fmt.Fprintf(&b, "category: %s\n", strconv.Quote(article.Category.Name))
+	}
+
+	tags := make([]string, len(article.Tags))
+	for i, tag := range article.Tags {
+		tags[i] = strconv.Quote(tag.Name)
+	}
+	fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(tags, ", "))
+
+	fmt.Fprintf(&b, "created_at: %s\n", article.CreatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "updated_at: %s\n", article.UpdatedAt.UTC().Format(time.RFC3339))
+	if article.PublishedAt != nil {
+		fmt.Fprintf(&b, "published_at: %s\n", article.PublishedAt.UTC().Format(time.RFC3339))
+	}
+	if article.PublishAt != nil {
+		fmt.Fprintf(&b, "publish_at: %s\n", article.PublishAt.UTC().Format(time.RFC3339))
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(article.Content)
+	if !strings.HasSuffix(article.Content, "\n") {
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func writeJSONFile(archive *zip.Writer, name string, v any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// Формат файлов выгрузки не зависит от ответов API
+type exportProfile struct {
+	Id                  uint                `json:"id"`
+	Username            string              `json:"username"`
+	Email               string              `json:"email"`
+	PendingEmail        *string             `json:"pending_email,omitempty"`
+	EmailVerified       bool                `json:"email_verified"`
+	TwoFactorEnabled    bool                `json:"two_factor_enabled"`
+	Role                string              `json:"role"`
+	CreatedAt           time.Time           `json:"created_at"`
+	DeletionScheduledAt *time.Time          `json:"deletion_scheduled_at,omitempty"`
+	DisplayName         string              `json:"display_name"`
+	Bio                 string              `json:"bio"`
+	AvatarURL           string              `json:"avatar_url"`
+	Website             string              `json:"website"`
+	Location            string              `json:"location"`
+	Links               []exportProfileLink `json:"links"`
+}
+
+type exportProfileLink struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+type exportFollows struct {
+	Following []exportFollow `json:"following"`
+	Followers []exportFollow `json:"followers"`
+}
+
+type exportFollow struct {
+	UserId    uint      `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type exportCategorySubscription struct {
+	CategoryId   uint      `json:"category_id"`
+	CategoryName string    `json:"category_name,omitempty"`
+	CategorySlug string    `json:"category_slug,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type exportSession struct {
+	Id         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	IsRevoked  bool      `json:"is_revoked"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type exportComment struct {
+	Id        uint      `json:"id"`
+	ArticleId uint      `json:"article_id"`
+	ParentId  *uint     `json:"parent_id,omitempty"`
+	Content   string    `json:"content"`
+	IsDeleted bool      `json:"is_deleted"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toExportProfile(user *domain.User) exportProfile {
+	links := make([]exportProfileLink, len(user.Profile.Links))
+	for i, link := range user.Profile.Links {
+		links[i] = exportProfileLink{Label: link.Label, URL: link.URL}
+	}
+
+	return exportProfile{
+		Id:                  user.ID,
+		Username:            user.Username,
+		Email:               user.Email,
+		PendingEmail:        user.PendingEmail,
+		EmailVerified:       user.IsEmailVerified(),
+		TwoFactorEnabled:    user.IsTwoFactorEnabled(),
+		Role:                string(user.Role),
+		CreatedAt:           user.CreatedAt,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		DisplayName:         user.Profile.DisplayName,
+		Bio:                 user.Profile.Bio,
+		AvatarURL:           user.Profile.AvatarURL,
+		Website:             user.Profile.Website,
+		Location:            user.Profile.Location,
+		Links:               links,
+	}
+}
+
+// user может быть nil, если связанный аккаунт не подгрузился
+func toExportFollow(userId uint, user *domain.User, createdAt time.Time) exportFollow {
+	res := exportFollow{
+		UserId:    userId,
+		CreatedAt: createdAt,
+	}
+	if user != nil {
+		res.Username = user.Username
+	}
+
+	return res
+}
+
+func toExportCategorySubscription(subscription *domain.CategorySubscription) exportCategorySubscription {
+	res := exportCategorySubscription{
+		CategoryId: subscription.CategoryID,
+		CreatedAt:  subscription.CreatedAt,
+	}
+	if subscription.Category != nil {
+		res.CategoryName = subscription.Category.Name
+		res.CategorySlug = subscription.Category.Slug
+	}
+
+	return res
+}
+
+func toExportSession(session *domain.Session) exportSession {
+	return exportSession{
+		Id:         session.Id.String(),
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		IsRevoked:  session.IsRevoked,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
+
+func toExportComment(comment *domain.Comment) exportComment {
+	return exportComment{
+		Id:        comment.ID,
+		ArticleId: comment.ArticleID,
+		ParentId:  comment.ParentID,
+		Content:   comment.Content,
+		IsDeleted: comment.IsDeleted,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Статьи удаляемого пользователя передаются другому пользователю
+	DeleteUserArticlesReassign = "reassign"
+	// Статьи удаляются вместе с пользователем
+	DeleteUserArticlesPurge = "purge"
+)
+
+// AdminUserService - управление пользователями для администраторов.
+// Каждый метод сам проверяет право user.manage у ActorId
+type AdminUserService struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	authz       *AuthorizationService
+	clock       Clock
+}
+
+func NewAdminUserService(u repository.UserRepository, s repository.SessionRepository, authz *AuthorizationService, clock Clock) *AdminUserService {
+	return &AdminUserService{
+		userRepo:    u,
+		sessionRepo: s,
+		authz:       authz,
+		clock:       clock,
+	}
+}
+
+func (s *AdminUserService) List(ctx context.Context, req *ListUsersReq) (*GetUsersRes, error) {
+	const op = "AdminUserService.List"
+
+	if err := s.authz.Authorize(ctx, req.ActorId, domain.PermUserManage); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	filter := domain.UserListFilter{
+		UsernamePrefix: req.UsernamePrefix,
+		CreatedAfter:   req.CreatedAfter,
+		CreatedBefore:  req.CreatedBefore,
+		Suspended:      req.Suspended,
+	}
+	if req.Role != "" {
+		role, err := domain.ParseRole(req.Role)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		filter.Role = &role
+	}
+
+	page, err := domain.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	users, next, err := s.userRepo.List(ctx, filter, page)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	result := &GetUsersRes{Users: make([]*AdminUserRes, len(users))}
+	for i := range users {
+		result.Users[i] = toAdminUserRes(&users[i])
+	}
+	if next != nil {
+		result.NextCursor = next.Encode()
+	}
+
+	return result, nil
+}
+
+func (s *AdminUserService) Get(ctx context.Context, actorId, userId uint) (*AdminUserRes, error) {
+	const op = "AdminUserService.Get"
+
+	if err := s.authz.Authorize(ctx, actorId, domain.PermUserManage); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toAdminUserRes(user), nil
+}
+
+// Suspend блокирует вход и отзывает все сессии. Уже выданные access токены
+// перестают приниматься сразу: AuthMiddleware сверяет их с пользователем в базе
+func (s *AdminUserService) Suspend(ctx context.Context, actorId, userId uint) (*AdminUserRes, error) {
+	const op = "AdminUserService.Suspend"
+
+	user, err := s.getManagedUser(ctx, actorId, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := user.Suspend(s.clock.Now()); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updUser, err := s.userRepo.SetSuspendedAt(ctx, user.ID, user.SuspendedAt)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if _, err := s.sessionRepo.RevokeAllByUser(ctx, user.ID, uuid.Nil); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toAdminUserRes(updUser), nil
+}
+
+func (s *AdminUserService) Unsuspend(ctx context.Context, actorId, userId uint) (*AdminUserRes, error) {
+	const op = "AdminUserService.Unsuspend"
+
+	user, err := s.getManagedUser(ctx, actorId, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := user.Unsuspend(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updUser, err := s.userRepo.SetSuspendedAt(ctx, user.ID, user.SuspendedAt)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toAdminUserRes(updUser), nil
+}
+
+// Delete удаляет пользователя безвозвратно. Без req.Articles удаление
+// пользователя со статьями отклоняется с ErrUserHasArticles
+func (s *AdminUserService) Delete(ctx context.Context, req *DeleteUserReq) error {
+	const op = "AdminUserService.Delete"
+
+	user, err := s.getManagedUser(ctx, req.ActorId, req.UserId)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	switch req.Articles {
+	case "":
+		err = s.userRepo.Delete(ctx, user.ID)
+	case DeleteUserArticlesPurge:
+		err = s.userRepo.DeleteWithArticles(ctx, user.ID, nil)
+	case DeleteUserArticlesReassign:
+		if err := s.checkReassignTarget(ctx, user.ID, req.ReassignTo); err != nil {
+			return e.Wrap(op, err)
+		}
+		err = s.userRepo.DeleteWithArticles(ctx, user.ID, req.ReassignTo)
+	default:
+		return e.Wrap(op, e.ErrDeleteUserModeInvalid)
+	}
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	// У сессий нет внешнего ключа на users, поэтому отзываем их явно
+	if _, err := s.sessionRepo.RevokeAllByUser(ctx, user.ID, uuid.Nil); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// getManagedUser проверяет права и не даёт администратору заблокировать или удалить самого себя
+// или служебного пользователя
+func (s *AdminUserService) getManagedUser(ctx context.Context, actorId, userId uint) (*domain.User, error) {
+	if err := s.authz.Authorize(ctx, actorId, domain.PermUserManage); err != nil {
+		return nil, err
+	}
+
+	if actorId == userId {
+		return nil, e.ErrPermissionDenied
+	}
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Служебный пользователь хранит статьи удалённых аккаунтов
+	if user.IsGhost() {
+		return nil, e.ErrPermissionDenied
+	}
+
+	return user, nil
+}
+
+func (s *AdminUserService) checkReassignTarget(ctx context.Context, userId uint, reassignTo *uint) error {
+	if reassignTo == nil || *reassignTo == userId {
+		return e.ErrReassignTargetInvalid
+	}
+
+	if _, err := s.userRepo.GetById(ctx, *reassignTo); err != nil {
+		if errors.Is(err, e.ErrUserNotFound) {
+			return e.ErrReassignTargetInvalid
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func toAdminUserRes(user *domain.User) *AdminUserRes {
+	return &AdminUserRes{
+		User:        *toUserResponse(user),
+		CreatedAt:   user.CreatedAt,
+		SuspendedAt: user.SuspendedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// adminUserRepo хранит пользователей в памяти. Update не реализован: блокировка
+// должна писать только suspended_at
+type adminUserRepo struct {
+	repository.UserRepository
+
+	users map[uint]*domain.User
+	// afterGet вызывается после чтения пользователя, чтобы смоделировать параллельную запись
+	afterGet func(*domain.User)
+}
+
+func (r *adminUserRepo) GetById(_ context.Context, id uint) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, e.ErrUserNotFound
+	}
+
+	copied := *user
+	if r.afterGet != nil {
+		r.afterGet(user)
+	}
+
+	return &copied, nil
+}
+
+func (r *adminUserRepo) SetSuspendedAt(_ context.Context, id uint, suspendedAt *time.Time) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, e.ErrUserNotFound
+	}
+
+	user.SuspendedAt = suspendedAt
+	copied := *user
+	return &copied, nil
+}
+
+type adminSessionRepo struct {
+	repository.SessionRepository
+}
+
+func (adminSessionRepo) RevokeAllByUser(context.Context, uint, uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+// Параллельные изменения профиля, 2FA и удаления аккаунта не затираются блокировкой
+func TestAdminUserServiceSuspendKeepsConcurrentChanges(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	deleteAt := now.Add(24 * time.Hour)
+
+	const adminId, userId = 1, 2
+	users := &adminUserRepo{
+		users: map[uint]*domain.User{
+			adminId: {ID: adminId, Username: "admin", Role: domain.RoleAdmin},
+			userId:  {ID: userId, Username: "reader", Role: domain.RoleUser},
+		},
+	}
+	users.afterGet = func(user *domain.User) {
+		if user.ID != userId {
+			return
+		}
+		user.Profile.Bio = "updated concurrently"
+		user.TOTPEnabledAt = &now
+		user.DeletionScheduledAt = &deleteAt
+	}
+
+	s := NewAdminUserService(users, adminSessionRepo{}, NewAuthorizationService(users), fixedClock{now: now})
+	if _, err := s.Suspend(context.Background(), adminId, userId); err != nil {
+		t.Fatalf("Suspend() error = %v", err)
+	}
+
+	stored := users.users[userId]
+	if stored.SuspendedAt == nil || !stored.SuspendedAt.Equal(now) {
+		t.Errorf("SuspendedAt = %v, want %v", stored.SuspendedAt, now)
+	}
+	if stored.Profile.Bio != "updated concurrently" {
+		t.Errorf("Bio = %q, concurrent profile change was overwritten", stored.Profile.Bio)
+	}
+	if stored.TOTPEnabledAt == nil {
+		t.Error("TOTPEnabledAt was reset by Suspend")
+	}
+	if stored.DeletionScheduledAt == nil {
+		t.Error("DeletionScheduledAt was reset by Suspend")
+	}
+}
+
+// Служебного пользователя узнают по флагу, даже если его переименовали
+func TestAdminUserServiceRefusesToManageGhost(t *testing.T) {
+	const adminId, ghostId = 1, 2
+	users := &adminUserRepo{
+		users: map[uint]*domain.User{
+			adminId: {ID: adminId, Username: "admin", Role: domain.RoleAdmin},
+			ghostId: {ID: ghostId, Username: "renamed", Role: domain.RoleUser, IsSystem: true},
+		},
+	}
+
+	s := NewAdminUserService(users, adminSessionRepo{}, NewAuthorizationService(users), fixedClock{now: time.Now()})
+	if _, err := s.Suspend(context.Background(), adminId, ghostId); !errors.Is(err, e.ErrPermissionDenied) {
+		t.Fatalf("Suspend() error = %v, want %v", err, e.ErrPermissionDenied)
+	}
+}
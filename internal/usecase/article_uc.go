@@ -12,39 +12,69 @@ type ArticleService struct {
 	articleRepo  repository.ArticleRepository
 	userRepo     repository.UserRepository
 	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	revisionRepo repository.RevisionRepository
+	authz        *AuthorizationService
+	renderer     MarkdownRenderer
+	clock        Clock
+	// Запрещает создавать статьи пользователям с неподтверждённым email
+	requireVerifiedEmail bool
 }
 
-func NewArticleService(a repository.ArticleRepository, u repository.UserRepository, c repository.CategoryRepository) *ArticleService {
+func NewArticleService(a repository.ArticleRepository, u repository.UserRepository, c repository.CategoryRepository, t repository.TagRepository, r repository.RevisionRepository, authz *AuthorizationService, renderer MarkdownRenderer, clock Clock, requireVerifiedEmail bool) *ArticleService {
 	return &ArticleService{
 		articleRepo:  a,
 		userRepo:     u,
 		categoryRepo: c,
+		tagRepo:      t,
+		revisionRepo: r,
+		authz:        authz,
+		renderer:     renderer,
+		clock:        clock,
+
+		requireVerifiedEmail: requireVerifiedEmail,
 	}
 }
 
-func (s *ArticleService) GetAllArticlesByUserId(ctx context.Context, userId uint) (*GetArticles, error) {
+// Автор видит все свои статьи, остальные - только опубликованные
+func (s *ArticleService) GetAllArticlesByUserId(ctx context.Context, userId, viewerId uint, req *ListArticlesReq) (*GetArticles, error) {
 	const op = "ArticleService.GetAllArticlesByUserId"
 
-	articles, err := s.articleRepo.ListByAuthor(ctx, userId)
+	page, err := domain.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	articles, next, err := s.articleRepo.ListByAuthor(ctx, userId, userId != viewerId, page)
 	if err != nil {
 		if errors.Is(err, e.ErrArticleNotFound) {
-			return &GetArticles{[]*ArticleRes{}}, nil
+			return &GetArticles{Articles: []*ArticleRes{}}, nil
 		}
 
 		return nil, e.Wrap(op, err)
 	}
 
-	res := make([]*ArticleRes, len(articles))
-	for i, article := range articles {
-		res[i] = toArticleRes(&article)
+	if err := s.renderAll(articles); err != nil {
+		return nil, e.Wrap(op, err)
 	}
 
-	return toGetArticlesByUserRes(res), nil
+	return toGetArticlesRes(articles, next), nil
 }
 
 func (s *ArticleService) Create(ctx context.Context, req *CreateArticleReq) (*CreateArticleRes, error) {
 	const op = "ArticleService.Create"
 
+	if s.requireVerifiedEmail {
+		author, err := s.userRepo.GetById(ctx, req.UserId)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		if !author.IsEmailVerified() {
+			return nil, e.Wrap(op, e.ErrEmailNotVerified)
+		}
+	}
+
 	category, err := s.categoryRepo.GetBySlug(ctx, req.CategorySlug)
 	if err != nil {
 		return nil, e.Wrap(op, err)
@@ -55,19 +85,34 @@ func (s *ArticleService) Create(ctx context.Context, req *CreateArticleReq) (*Cr
 		return nil, e.Wrap(op, e.ErrArticleDataIsInvalid)
 	}
 
+	tags, err := domain.NewTags(req.Tags)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
 	if err := s.articleRepo.ExistsByTitleContentAuthor(ctx, newArticle); err != nil {
 		return nil, e.Wrap(op, e.ErrArticleDataIsInvalid)
 	}
 
-	result, err := s.articleRepo.Create(ctx, newArticle)
+	if req.Publish {
+		if err := newArticle.Publish(s.clock.Now()); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	result, err := s.articleRepo.Create(ctx, newArticle, tags)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
+	if err := s.render(result); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
 	return toCreateArticleRes(result, category.Slug, category.Name), nil
 }
 
-func (s *ArticleService) GetById(ctx context.Context, id uint) (*ArticleRes, error) {
+func (s *ArticleService) GetById(ctx context.Context, id, viewerId uint) (*ArticleRes, error) {
 	const op = "ArticleService.GetById"
 
 	article, err := s.articleRepo.GetByID(ctx, id)
@@ -75,32 +120,65 @@ func (s *ArticleService) GetById(ctx context.Context, id uint) (*ArticleRes, err
 		return nil, e.Wrap(op, err)
 	}
 
+	if !article.IsVisibleTo(viewerId) {
+		return nil, e.Wrap(op, e.ErrArticleNotFound)
+	}
+
+	if err := s.render(article); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toArticleRes(article), nil
+}
+
+// GetBySlug находит статью и по прежнему slug: сравнив Slug ответа с запрошенным,
+// вызывающий может перенаправить на актуальный адрес
+func (s *ArticleService) GetBySlug(ctx context.Context, slug string, viewerId uint) (*ArticleRes, error) {
+	const op = "ArticleService.GetBySlug"
+
+	article, err := s.articleRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if !article.IsVisibleTo(viewerId) {
+		return nil, e.Wrap(op, e.ErrArticleNotFound)
+	}
+
+	if err := s.render(article); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
 	return toArticleRes(article), nil
 }
 
-func (s *ArticleService) GetAllArticlesByCategory(ctx context.Context, slug string) (*GetArticles, error) {
+func (s *ArticleService) GetAllArticlesByCategory(ctx context.Context, slug string, req *ListArticlesReq) (*GetArticles, error) {
 	const op = "ArticleService.GetAllArticlesByCategoryId"
 
+	page, err := domain.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
 	category, err := s.categoryRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	articles, err := s.articleRepo.ListByCategory(ctx, category.ID)
+	articles, next, err := s.articleRepo.ListByCategory(ctx, category.ID, page)
 	if err != nil {
 		if errors.Is(err, e.ErrArticleNotFound) {
-			return &GetArticles{[]*ArticleRes{}}, nil
+			return &GetArticles{Articles: []*ArticleRes{}}, nil
 		}
 
 		return nil, e.Wrap(op, err)
 	}
 
-	res := make([]*ArticleRes, len(articles))
-	for i, article := range articles {
-		res[i] = toArticleRes(&article)
+	if err := s.renderAll(articles); err != nil {
+		return nil, e.Wrap(op, err)
 	}
 
-	return toGetArticlesByUserRes(res), nil
+	return toGetArticlesRes(articles, next), nil
 }
 
 func (s *ArticleService) Delete(ctx context.Context, req *DeleteArticleReq) error {
@@ -111,8 +189,8 @@ func (s *ArticleService) Delete(ctx context.Context, req *DeleteArticleReq) erro
 		return e.Wrap(op, err)
 	}
 
-	if err := article.CheckAuthor(req.UserId); err != nil {
-		return e.Wrap(op, e.ErrUserNotAuthor)
+	if err := s.checkCanEdit(ctx, article, req.UserId); err != nil {
+		return e.Wrap(op, err)
 	}
 
 	if err := s.articleRepo.Delete(ctx, req.ArticleId); err != nil {
@@ -130,14 +208,18 @@ func (s *ArticleService) Update(ctx context.Context, req *UpdateArticleReq) (*Up
 		return nil, e.Wrap(op, err)
 	}
 
-	if err := article.CheckAuthor(req.UserId); err != nil {
-		return nil, e.Wrap(op, e.ErrUserNotAuthor)
+	if err := s.checkCanEdit(ctx, article, req.UserId); err != nil {
+		return nil, e.Wrap(op, err)
 	}
 
-	if req.Title == nil && req.Content == nil && req.CategorySlug == nil {
+	if req.Title == nil && req.Content == nil && req.CategorySlug == nil && req.Tags == nil && req.PublishAt == nil && !req.Unschedule {
 		return nil, e.Wrap(op, e.ErrNoDataToUpdate)
 	}
 
+	if req.PublishAt != nil && req.Unschedule {
+		return nil, e.Wrap(op, e.ErrArticleDataIsInvalid)
+	}
+
 	if req.Title != nil {
 		if err := article.ChangeTitle(*req.Title); err != nil {
 			return nil, e.Wrap(op, e.ErrArticleNameIsExists)
@@ -161,28 +243,210 @@ func (s *ArticleService) Update(ctx context.Context, req *UpdateArticleReq) (*Up
 		}
 	}
 
-	updArticle, err := s.articleRepo.Update(ctx, article)
+	// Теги заменяются в одной транзакции с правкой статьи
+	var tags []*domain.Tag
+	if req.Tags != nil {
+		tags, err = domain.NewTags(*req.Tags)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	if req.PublishAt != nil {
+		if err := article.Schedule(*req.PublishAt, s.clock.Now()); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	if req.Unschedule {
+		if err := article.Unschedule(); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	if err := article.Validate(); err != nil {
+		return nil, e.Wrap(op, e.ErrArticleDataIsInvalid)
+	}
+
+	updArticle, err := s.articleRepo.Update(ctx, article, req.UserId, tags)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
+	if err := s.render(updArticle); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
 	return toUpdateArticleRes(updArticle), nil
 }
 
-func (s *ArticleService) GetAll(ctx context.Context) (*GetArticles, error) {
+func (s *ArticleService) GetAllArticlesByTag(ctx context.Context, slug string, req *ListArticlesReq) (*GetArticles, error) {
+	const op = "ArticleService.GetAllArticlesByTag"
+
+	page, err := domain.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	tag, err := s.tagRepo.GetBySlug(ctx, domain.NormalizeTagSlug(slug))
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	articles, next, err := s.articleRepo.ListByTag(ctx, tag.ID, page)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.renderAll(articles); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toGetArticlesRes(articles, next), nil
+}
+
+func (s *ArticleService) Search(ctx context.Context, req *SearchArticlesReq) (*SearchArticlesRes, error) {
+	const op = "ArticleService.Search"
+
+	page, err := domain.NewSearchPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	search := domain.ArticleSearch{Query: req.Query}
+	if req.CategorySlug != "" {
+		category, err := s.categoryRepo.GetBySlug(ctx, req.CategorySlug)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		search.CategoryID = &category.ID
+	}
+
+	if req.AuthorUsername != "" {
+		author, err := s.userRepo.GetByUsername(ctx, req.AuthorUsername)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		search.AuthorID = &author.ID
+	}
+
+	hits, next, err := s.articleRepo.Search(ctx, search, page)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	for i := range hits {
+		if err := s.render(&hits[i].Article); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	return toSearchArticlesRes(hits, next), nil
+}
+
+func (s *ArticleService) Publish(ctx context.Context, req *ChangeArticleStatusReq) (*ArticleRes, error) {
+	const op = "ArticleService.Publish"
+
+	res, err := s.changeStatus(ctx, req, func(article *domain.Article) error {
+		return article.Publish(s.clock.Now())
+	})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return res, nil
+}
+
+func (s *ArticleService) Unpublish(ctx context.Context, req *ChangeArticleStatusReq) (*ArticleRes, error) {
+	const op = "ArticleService.Unpublish"
+
+	res, err := s.changeStatus(ctx, req, func(article *domain.Article) error {
+		return article.Unpublish()
+	})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return res, nil
+}
+
+func (s *ArticleService) Archive(ctx context.Context, req *ChangeArticleStatusReq) (*ArticleRes, error) {
+	const op = "ArticleService.Archive"
+
+	res, err := s.changeStatus(ctx, req, func(article *domain.Article) error {
+		return article.Archive()
+	})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return res, nil
+}
+
+func (s *ArticleService) changeStatus(ctx context.Context, req *ChangeArticleStatusReq, transition func(article *domain.Article) error) (*ArticleRes, error) {
+	article, err := s.articleRepo.GetByID(ctx, req.ArticleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, article, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := transition(article); err != nil {
+		return nil, err
+	}
+
+	updArticle, err := s.articleRepo.UpdateStatus(ctx, article)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.render(updArticle); err != nil {
+		return nil, err
+	}
+
+	return toArticleRes(updArticle), nil
+}
+
+// Чужую статью может менять только пользователь с правом article.edit_any
+func (s *ArticleService) checkCanEdit(ctx context.Context, article *domain.Article, userId uint) error {
+	if article.CheckAuthor(userId) == nil {
+		return nil
+	}
+
+	allowed, err := s.authz.Can(ctx, userId, domain.PermArticleEditAny)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return e.ErrUserNotAuthor
+	}
+
+	return nil
+}
+
+func (s *ArticleService) GetAll(ctx context.Context, req *ListArticlesReq) (*GetArticles, error) {
 	const op = "ArticleService.GetAll"
 
-	articles, err := s.articleRepo.ListAll(ctx)
+	page, err := domain.NewPage(req.Limit, req.Cursor)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	res := make([]*ArticleRes, len(articles))
-	for i, article := range articles {
-		res[i] = toArticleRes(&article)
+	articles, next, err := s.articleRepo.ListAll(ctx, page)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.renderAll(articles); err != nil {
+		return nil, e.Wrap(op, err)
 	}
 
-	return toGetArticlesByUserRes(res), nil
+	return toGetArticlesRes(articles, next), nil
 }
 
 func toCategoryRes(category *domain.Category) *CategoryRes {
@@ -193,30 +457,105 @@ func toCategoryRes(category *domain.Category) *CategoryRes {
 	}
 }
 
+func (s *ArticleService) render(article *domain.Article) error {
+	html, err := s.renderer.Render(article.Content)
+	if err != nil {
+		return err
+	}
+
+	article.ContentHTML = html
+	return nil
+}
+
+func (s *ArticleService) renderAll(articles []domain.Article) error {
+	for i := range articles {
+		if err := s.render(&articles[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toTagsRes(tags []domain.Tag) []TagRes {
+	res := make([]TagRes, len(tags))
+	for i, tag := range tags {
+		res[i] = TagRes{
+			Name: tag.Name,
+			Slug: tag.Slug,
+		}
+	}
+
+	return res
+}
+
 func toUpdateArticleRes(a *domain.Article) *UpdateArticleRes {
 	return &UpdateArticleRes{
-		ArticleId: a.ID,
-		Title:     a.Title,
-		Content:   a.Content,
-		Category:  *toCategoryRes(a.Category),
-		AuthorID:  a.Author.ID,
-		UpdatedAt: a.UpdatedAt,
+		ArticleId:   a.ID,
+		Title:       a.Title,
+		Slug:        a.Slug,
+		Content:     a.Content,
+		ContentHTML: a.ContentHTML,
+		Status:      a.Status,
+		PublishAt:   a.PublishAt,
+		Category:    *toCategoryRes(a.Category),
+		Tags:        toTagsRes(a.Tags),
+		AuthorID:    a.Author.ID,
+		UpdatedAt:   a.UpdatedAt,
 	}
 }
 
 func toArticleRes(article *domain.Article) *ArticleRes {
 	return &ArticleRes{
-		ArticleId: article.ID,
-		Title:     article.Title,
-		Content:   article.Content,
-		Author:    *toUserResponse(article.Author),
-		Category:  *toCategoryRes(article.Category),
+		ArticleId:   article.ID,
+		Title:       article.Title,
+		Slug:        article.Slug,
+		Content:     article.Content,
+		ContentHTML: article.ContentHTML,
+		Status:      article.Status,
+		PublishedAt: article.PublishedAt,
+		PublishAt:   article.PublishAt,
+		Author:      *toUserResponse(article.Author),
+		Category:    *toCategoryRes(article.Category),
+		Tags:        toTagsRes(article.Tags),
 	}
 }
 
-func toGetArticlesByUserRes(articles []*ArticleRes) *GetArticles {
+func toGetArticlesRes(articles []domain.Article, next *domain.Cursor) *GetArticles {
+	res := make([]*ArticleRes, len(articles))
+	for i, article := range articles {
+		res[i] = toArticleRes(&article)
+	}
+
+	var nextCursor string
+	if next != nil {
+		nextCursor = next.Encode()
+	}
+
 	return &GetArticles{
-		Articles: articles,
+		Articles:   res,
+		NextCursor: nextCursor,
+	}
+}
+
+func toSearchArticlesRes(hits []domain.ArticleSearchHit, next *domain.SearchCursor) *SearchArticlesRes {
+	results := make([]*ArticleSearchRes, len(hits))
+	for i, hit := range hits {
+		results[i] = &ArticleSearchRes{
+			Article: *toArticleRes(&hit.Article),
+			Rank:    hit.Rank,
+			Snippet: hit.Snippet,
+		}
+	}
+
+	var nextCursor string
+	if next != nil {
+		nextCursor = next.Encode()
+	}
+
+	return &SearchArticlesRes{
+		Results:    results,
+		NextCursor: nextCursor,
 	}
 }
 
@@ -224,8 +563,13 @@ func toCreateArticleRes(article *domain.Article, categorySlug, categoryName stri
 	return &CreateArticleRes{
 		ArticleId:    article.ID,
 		Title:        article.Title,
+		Slug:         article.Slug,
 		Content:      article.Content,
+		ContentHTML:  article.ContentHTML,
+		Status:       article.Status,
+		PublishedAt:  article.PublishedAt,
 		CategorySlug: categorySlug,
 		CategoryName: categoryName,
+		Tags:         toTagsRes(article.Tags),
 	}
 }
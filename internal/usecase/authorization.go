@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+)
+
+// AuthorizationService проверяет права по текущей роли из базы, а не из JWT:
+// смена роли действует сразу, не дожидаясь истечения access токена
+type AuthorizationService struct {
+	userRepo repository.UserRepository
+}
+
+func NewAuthorizationService(u repository.UserRepository) *AuthorizationService {
+	return &AuthorizationService{userRepo: u}
+}
+
+func (s *AuthorizationService) Authorize(ctx context.Context, userId uint, permission domain.Permission) error {
+	const op = "AuthorizationService.Authorize"
+
+	allowed, err := s.Can(ctx, userId, permission)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if !allowed {
+		return e.Wrap(op, e.ErrPermissionDenied)
+	}
+
+	return nil
+}
+
+func (s *AuthorizationService) Can(ctx context.Context, userId uint, permission domain.Permission) (bool, error) {
+	const op = "AuthorizationService.Can"
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return false, e.Wrap(op, err)
+	}
+
+	return !user.IsSuspended() && user.Can(permission), nil
+}
+
+// ChangeRole назначает роль другому пользователю. Свою роль менять нельзя,
+// иначе последний администратор может случайно лишиться прав
+func (s *AuthorizationService) ChangeRole(ctx context.Context, req *ChangeRoleReq) (*UserRes, error) {
+	const op = "AuthorizationService.ChangeRole"
+
+	if err := s.Authorize(ctx, req.ActorId, domain.PermUserManageRoles); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if req.ActorId == req.UserId {
+		return nil, e.Wrap(op, e.ErrPermissionDenied)
+	}
+
+	role, err := domain.ParseRole(req.Role)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	user, err := s.userRepo.GetById(ctx, req.UserId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := user.ChangeRole(role); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updUser, err := s.userRepo.SetRole(ctx, user.ID, user.Role)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toUserResponse(updUser), nil
+}
+
+func (s *AuthorizationService) ListRoles(ctx context.Context, actorId uint) ([]*RoleRes, error) {
+	const op = "AuthorizationService.ListRoles"
+
+	if err := s.Authorize(ctx, actorId, domain.PermUserManageRoles); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	roles := domain.Roles()
+	result := make([]*RoleRes, len(roles))
+	for i, role := range roles {
+		result[i] = &RoleRes{
+			Role:        role,
+			Permissions: role.Permissions(),
+		}
+	}
+
+	return result, nil
+}
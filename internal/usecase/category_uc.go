@@ -9,17 +9,21 @@ import (
 
 type CategoryService struct {
 	categoryRepo repository.CategoryRepository
+	authz        *AuthorizationService
 }
 
-func NewCategoryService(c repository.CategoryRepository) *CategoryService {
-	return &CategoryService{categoryRepo: c}
+func NewCategoryService(c repository.CategoryRepository, authz *AuthorizationService) *CategoryService {
+	return &CategoryService{
+		categoryRepo: c,
+		authz:        authz,
+	}
 }
 
 func (s *CategoryService) Create(ctx context.Context, req *CreateCategoryReq) (string, error) {
 	const op = "CategoryService.Create"
 
-	if req.UserRole != domain.RoleAdmin {
-		return "", e.Wrap(op, e.ErrPermissionDenied)
+	if err := s.authz.Authorize(ctx, req.UserId, domain.PermCategoryManage); err != nil {
+		return "", e.Wrap(op, err)
 	}
 
 	newCategory := domain.NewCategory(req.CategoryName, req.CategorySlug)
@@ -51,8 +55,8 @@ func (s *CategoryService) GetAll(ctx context.Context) ([]*GetAllCategoriesRes, e
 func (s *CategoryService) Update(ctx context.Context, req *UpdateCategoryReq) (*UpdateCategoryRes, error) {
 	const op = "CategoryService.Update"
 
-	if req.UserRole != domain.RoleAdmin {
-		return nil, e.Wrap(op, e.ErrPermissionDenied)
+	if err := s.authz.Authorize(ctx, req.UserId, domain.PermCategoryManage); err != nil {
+		return nil, e.Wrap(op, err)
 	}
 
 	category, err := s.categoryRepo.GetBySlug(ctx, req.CategorySlug)
@@ -87,8 +91,8 @@ func (s *CategoryService) Update(ctx context.Context, req *UpdateCategoryReq) (*
 func (s *CategoryService) Delete(ctx context.Context, req *DeleteCategoryReq) error {
 	const op = "CategoryService.Delete"
 
-	if req.UserRole != domain.RoleAdmin {
-		return e.Wrap(op, e.ErrPermissionDenied)
+	if err := s.authz.Authorize(ctx, req.UserId, domain.PermCategoryManage); err != nil {
+		return e.Wrap(op, err)
 	}
 
 	category, err := s.categoryRepo.GetBySlug(ctx, req.CategorySlug)
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+)
+
+type CommentService struct {
+	commentRepo repository.CommentRepository
+	articleRepo repository.ArticleRepository
+	authz       *AuthorizationService
+}
+
+func NewCommentService(c repository.CommentRepository, a repository.ArticleRepository, authz *AuthorizationService) *CommentService {
+	return &CommentService{
+		commentRepo: c,
+		articleRepo: a,
+		authz:       authz,
+	}
+}
+
+func (s *CommentService) Create(ctx context.Context, req *CreateCommentReq) (*CommentRes, error) {
+	const op = "CommentService.Create"
+
+	article, err := s.getVisibleArticle(ctx, req.ArticleId, req.UserId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if req.ParentId != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *req.ParentId)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		if err := parent.CanBeRepliedIn(article.ID); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	newComment := domain.NewComment(article.ID, req.UserId, req.ParentId, req.Content)
+	if err := newComment.Validate(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	comment, err := s.commentRepo.Create(ctx, newComment)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toCommentRes(comment), nil
+}
+
+// List возвращает страницу комментариев одного уровня вместе с ответами не глубже req.Depth уровней.
+// Если ответы обрезаны лимитом глубины, у комментария выставляется HasMoreReplies,
+// и их можно догрузить, передав его ID в ParentId
+func (s *CommentService) List(ctx context.Context, req *ListCommentsReq) (*GetCommentsRes, error) {
+	const op = "CommentService.List"
+
+	page, err := domain.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	depth := req.Depth
+	if depth <= 0 {
+		depth = domain.DefaultCommentDepth
+	}
+
+	if depth > domain.MaxCommentDepth {
+		depth = domain.MaxCommentDepth
+	}
+
+	article, err := s.getVisibleArticle(ctx, req.ArticleId, req.ViewerId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if req.ParentId != nil {
+		parent, err := s.commentRepo.GetByID(ctx, *req.ParentId)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		if parent.ArticleID != article.ID {
+			return nil, e.Wrap(op, e.ErrCommentNotFound)
+		}
+	}
+
+	roots, next, err := s.commentRepo.ListByParent(ctx, article.ID, req.ParentId, page)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	rootIds := make([]uint, len(roots))
+	for i, root := range roots {
+		rootIds[i] = root.ID
+	}
+
+	// Берём на уровень больше, чем отдаём, чтобы понять, есть ли ответы за пределами глубины
+	descendants, err := s.commentRepo.ListDescendants(ctx, rootIds, depth)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	var nextCursor string
+	if next != nil {
+		nextCursor = next.Encode()
+	}
+
+	return &GetCommentsRes{
+		Comments:   buildCommentTree(roots, descendants, depth),
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (s *CommentService) Update(ctx context.Context, req *UpdateCommentReq) (*CommentRes, error) {
+	const op = "CommentService.Update"
+
+	comment, err := s.commentRepo.GetByID(ctx, req.CommentId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if comment.ArticleID != req.ArticleId {
+		return nil, e.Wrap(op, e.ErrCommentNotFound)
+	}
+
+	if err := comment.CheckAuthor(req.UserId); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := comment.ChangeContent(req.Content); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updComment, err := s.commentRepo.Update(ctx, comment)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toCommentRes(updComment), nil
+}
+
+// Удалить комментарий может его автор, автор статьи или модератор.
+// Комментарий с ответами не удаляется, а превращается в tombstone
+func (s *CommentService) Delete(ctx context.Context, req *DeleteCommentReq) error {
+	const op = "CommentService.Delete"
+
+	comment, err := s.commentRepo.GetByID(ctx, req.CommentId)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if comment.ArticleID != req.ArticleId {
+		return e.Wrap(op, e.ErrCommentNotFound)
+	}
+
+	if err := s.checkCanDelete(ctx, comment, req.UserId); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	hasReplies, err := s.commentRepo.HasReplies(ctx, comment.ID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if !hasReplies {
+		if err := s.commentRepo.Delete(ctx, comment.ID); err != nil {
+			return e.Wrap(op, err)
+		}
+
+		return nil
+	}
+
+	if comment.IsDeleted {
+		return e.Wrap(op, e.ErrCommentDeleted)
+	}
+
+	comment.Tombstone()
+	if _, err := s.commentRepo.Update(ctx, comment); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *CommentService) checkCanDelete(ctx context.Context, comment *domain.Comment, userId uint) error {
+	if comment.CheckAuthor(userId) == nil {
+		return nil
+	}
+
+	article, err := s.articleRepo.GetByID(ctx, comment.ArticleID)
+	if err != nil {
+		return err
+	}
+
+	if article.CheckAuthor(userId) == nil {
+		return nil
+	}
+
+	return s.authz.Authorize(ctx, userId, domain.PermCommentModerate)
+}
+
+func (s *CommentService) getVisibleArticle(ctx context.Context, articleId, viewerId uint) (*domain.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, articleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !article.IsVisibleTo(viewerId) {
+		return nil, e.ErrArticleNotFound
+	}
+
+	return article, nil
+}
+
+func buildCommentTree(roots, descendants []domain.Comment, depth int) []*CommentRes {
+	children := make(map[uint][]*CommentRes, len(descendants))
+	for i := range descendants {
+		comment := &descendants[i]
+		if comment.ParentID == nil {
+			continue
+		}
+
+		children[*comment.ParentID] = append(children[*comment.ParentID], toCommentRes(comment))
+	}
+
+	var attach func(node *CommentRes, level int)
+	attach = func(node *CommentRes, level int) {
+		replies := children[node.Id]
+		if level >= depth {
+			node.HasMoreReplies = len(replies) > 0
+			return
+		}
+
+		node.Replies = replies
+		for _, reply := range replies {
+			attach(reply, level+1)
+		}
+	}
+
+	res := make([]*CommentRes, len(roots))
+	for i := range roots {
+		res[i] = toCommentRes(&roots[i])
+		attach(res[i], 1)
+	}
+
+	return res
+}
+
+func toCommentRes(comment *domain.Comment) *CommentRes {
+	res := &CommentRes{
+		Id:        comment.ID,
+		ArticleId: comment.ArticleID,
+		ParentId:  comment.ParentID,
+		Content:   comment.Content,
+		IsDeleted: comment.IsDeleted,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		Replies:   []*CommentRes{},
+	}
+
+	if !comment.IsDeleted && comment.Author != nil {
+		res.Author = &CommentAuthorRes{
+			Id:       comment.Author.ID,
+			Username: comment.Author.Username,
+		}
+	}
+
+	return res
+}
@@ -1,17 +1,46 @@
 package usecase
 
 import (
+	"context"
 	"my_blog_backend/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type HashManager interface {
 	HashPassword(password string) (string, error)
 	Compare(password string, hash string) error
+	// NeedsRehash истинно, если хэш получен устаревшим алгоритмом или параметрами
+	NeedsRehash(hash string) bool
+}
+
+// PasswordChecker оценивает стойкость пароля и ищет его среди утёкших
+type PasswordChecker interface {
+	// Score возвращает оценку от 0 до 4, userInputs считаются легко угадываемыми
+	Score(password string, userInputs ...string) int
+	IsBreached(password string) (bool, error)
 }
 
 type TokenManager interface {
-	NewJWT(userID uint, email string, role domain.Role) (*TokenResponse, error)
+	NewJWT(userID uint, sessionID uuid.UUID, email string, role domain.Role) (*TokenResponse, error)
 	VerifyJWT(tokenString string) (*AuthenticatedUser, error)
 	NewRefreshToken() (token string, hashed string, err error)
 	HashRefreshToken(token string) string
+	NewChallengeToken(userID uint) (*TokenResponse, error)
+	VerifyChallengeToken(token string) (uint, error)
+	// PublicKeys - ключи для проверки JWT сторонними сервисами
+	PublicKeys() []JSONWebKey
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+type MarkdownRenderer interface {
+	Render(markdown string) (string, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, mail *Mail) error
 }
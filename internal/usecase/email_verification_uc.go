@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"time"
+)
+
+type EmailVerificationService struct {
+	userRepo     repository.UserRepository
+	verifyRepo   repository.EmailVerificationRepository
+	tokenManager TokenManager
+	mailer       Mailer
+	clock        Clock
+	verifyURL    string
+	tokenTTL     time.Duration
+}
+
+// verifyURL - адрес страницы подтверждения на фронтенде, токен добавляется параметром token
+func NewEmailVerificationService(u repository.UserRepository, v repository.EmailVerificationRepository, tm TokenManager, mailer Mailer, clock Clock, verifyURL string, tokenTTL time.Duration) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:     u,
+		verifyRepo:   v,
+		tokenManager: tm,
+		mailer:       mailer,
+		clock:        clock,
+		verifyURL:    verifyURL,
+		tokenTTL:     tokenTTL,
+	}
+}
+
+// SendVerification отправляет письмо на адрес, который ждёт подтверждения:
+// новый адрес при смене email или текущий, если он ещё не подтверждён
+func (s *EmailVerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	const op = "EmailVerificationService.SendVerification"
+
+	email, err := user.EmailToVerify()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	now := s.clock.Now()
+
+	// Действует только последняя ссылка
+	if err := s.verifyRepo.InvalidateByUser(ctx, user.ID, now); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	token, tokenHash, err := s.tokenManager.NewRefreshToken()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.verifyRepo.Create(ctx, domain.NewEmailVerificationToken(user.ID, email, tokenHash, now.Add(s.tokenTTL))); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	link, err := tokenLink(s.verifyURL, token)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	mail := &Mail{
+		To:      email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf(
+			"Hi, %s!\n\nPlease confirm your email address by following the link:\n\n%s\n\n"+
+				"The link expires in %s. If you did not request this, ignore this email.\n",
+			user.Username, link, s.tokenTTL,
+		),
+	}
+	if err := s.mailer.Send(ctx, mail); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *EmailVerificationService) Resend(ctx context.Context, userId uint) error {
+	const op = "EmailVerificationService.Resend"
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.SendVerification(ctx, user); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, token string) (*UserRes, error) {
+	const op = "EmailVerificationService.VerifyEmail"
+
+	verifyToken, err := s.verifyRepo.GetByTokenHash(ctx, s.tokenManager.HashRefreshToken(token))
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	now := s.clock.Now()
+	if err := verifyToken.ValidateState(now); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	user, err := s.userRepo.GetById(ctx, verifyToken.UserID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := user.ConfirmEmail(verifyToken.Email, now); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updUser, err := s.userRepo.Update(ctx, user)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	// Гасим токен только после сохранения: если Update упадёт, ссылка из письма
+	// останется рабочей. Параллельный запрос с тем же токеном подтвердит тот же
+	// email, а MarkUsed одному из них вернёт ошибку
+	if err := s.verifyRepo.MarkUsed(ctx, verifyToken.ID, now); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toUserResponse(updUser), nil
+}
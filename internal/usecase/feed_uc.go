@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+)
+
+// FeedService отдаёт персональную ленту: свежие статьи авторов и категорий, на которые подписан пользователь
+type FeedService struct {
+	feedRepo repository.FeedRepository
+	renderer MarkdownRenderer
+}
+
+func NewFeedService(f repository.FeedRepository, renderer MarkdownRenderer) *FeedService {
+	return &FeedService{
+		feedRepo: f,
+		renderer: renderer,
+	}
+}
+
+func (s *FeedService) Get(ctx context.Context, userId uint, req *ListArticlesReq) (*GetArticles, error) {
+	const op = "FeedService.Get"
+
+	page, err := domain.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	articles, next, err := s.feedRepo.List(ctx, userId, page)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	for i := range articles {
+		html, err := s.renderer.Render(articles[i].Content)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		articles[i].ContentHTML = html
+	}
+
+	return toGetArticlesRes(articles, next), nil
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+)
+
+// FollowService управляет подписками на авторов и категории, из которых собирается лента
+type FollowService struct {
+	followRepo       repository.FollowRepository
+	subscriptionRepo repository.CategorySubscriptionRepository
+	userRepo         repository.UserRepository
+	categoryRepo     repository.CategoryRepository
+}
+
+func NewFollowService(f repository.FollowRepository, s repository.CategorySubscriptionRepository, u repository.UserRepository, c repository.CategoryRepository) *FollowService {
+	return &FollowService{
+		followRepo:       f,
+		subscriptionRepo: s,
+		userRepo:         u,
+		categoryRepo:     c,
+	}
+}
+
+func (s *FollowService) Follow(ctx context.Context, followerId uint, username string) error {
+	const op = "FollowService.Follow"
+
+	followee, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	follow, err := domain.NewFollow(followerId, followee.ID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.followRepo.Create(ctx, follow); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) Unfollow(ctx context.Context, followerId uint, username string) error {
+	const op = "FollowService.Unfollow"
+
+	followee, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.followRepo.Delete(ctx, followerId, followee.ID); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) ListFollowers(ctx context.Context, req *ListFollowsReq) (*GetFollowsRes, error) {
+	const op = "FollowService.ListFollowers"
+
+	user, page, err := s.listTarget(ctx, req)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	follows, next, err := s.followRepo.ListFollowers(ctx, user.ID, page)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toGetFollowsRes(follows, next, func(f *domain.Follow) *domain.User { return f.Follower }), nil
+}
+
+func (s *FollowService) ListFollowing(ctx context.Context, req *ListFollowsReq) (*GetFollowsRes, error) {
+	const op = "FollowService.ListFollowing"
+
+	user, page, err := s.listTarget(ctx, req)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	follows, next, err := s.followRepo.ListFollowing(ctx, user.ID, page)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toGetFollowsRes(follows, next, func(f *domain.Follow) *domain.User { return f.Followee }), nil
+}
+
+func (s *FollowService) SubscribeCategory(ctx context.Context, userId uint, slug string) error {
+	const op = "FollowService.SubscribeCategory"
+
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.subscriptionRepo.Create(ctx, domain.NewCategorySubscription(userId, category.ID)); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) UnsubscribeCategory(ctx context.Context, userId uint, slug string) error {
+	const op = "FollowService.UnsubscribeCategory"
+
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.subscriptionRepo.Delete(ctx, userId, category.ID); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *FollowService) ListSubscriptions(ctx context.Context, userId uint) ([]*CategoryRes, error) {
+	const op = "FollowService.ListSubscriptions"
+
+	subscriptions, err := s.subscriptionRepo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	res := make([]*CategoryRes, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.Category == nil {
+			continue
+		}
+		res = append(res, toCategoryRes(subscription.Category))
+	}
+
+	return res, nil
+}
+
+func (s *FollowService) listTarget(ctx context.Context, req *ListFollowsReq) (*domain.User, domain.Page, error) {
+	page, err := domain.NewPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, domain.Page{}, err
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, domain.Page{}, err
+	}
+
+	return user, page, nil
+}
+
+// other выбирает из подписки пользователя, которого нужно показать в списке
+func toGetFollowsRes(follows []domain.Follow, next *domain.Cursor, other func(*domain.Follow) *domain.User) *GetFollowsRes {
+	res := make([]*FollowRes, 0, len(follows))
+	for i := range follows {
+		user := other(&follows[i])
+		if user == nil {
+			continue
+		}
+
+		res = append(res, &FollowRes{
+			User:       *toUserResponse(user),
+			FollowedAt: follows[i].CreatedAt,
+		})
+	}
+
+	var nextCursor string
+	if next != nil {
+		nextCursor = next.Encode()
+	}
+
+	return &GetFollowsRes{
+		Follows:    res,
+		NextCursor: nextCursor,
+	}
+}
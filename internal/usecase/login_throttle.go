@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoginThrottle ограничивает подбор пароля и кодов 2FA. Ошибки считаются
+// отдельно по email (защищает аккаунт) и по IP (защищает от перебора
+// одного пароля по многим аккаунтам)
+type LoginThrottle struct {
+	attemptRepo repository.LoginAttemptRepository
+	clock       Clock
+	emailPolicy domain.LockoutPolicy
+	ipPolicy    domain.LockoutPolicy
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+func NewLoginThrottle(a repository.LoginAttemptRepository, clock Clock, emailPolicy, ipPolicy domain.LockoutPolicy) *LoginThrottle {
+	return &LoginThrottle{
+		attemptRepo: a,
+		clock:       clock,
+		emailPolicy: emailPolicy,
+		ipPolicy:    ipPolicy,
+	}
+}
+
+// Acquire возвращает *e.RetryError, если по email или IP действует блокировка.
+// Иначе попытка сразу учитывается как неудачная: проверка и инкремент атомарны,
+// и параллельные запросы не могут пройти проверку по одному и тому же счётчику.
+// Если попытка окажется успешной, её снимает Release или Succeeded
+func (t *LoginThrottle) Acquire(ctx context.Context, email, ip string) error {
+	const op = "LoginThrottle.Acquire"
+
+	now := t.clock.Now()
+	limits := t.limits(email, ip)
+	for i, limit := range limits {
+		retryAfter, err := t.attemptRepo.TryAttempt(ctx, limit.key, now, limit.policy)
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+
+		if retryAfter > 0 {
+			// Попытка по предыдущим ключам уже учтена, но так и не состоялась
+			if err := t.forgive(ctx, limits[:i]); err != nil {
+				return e.Wrap(op, err)
+			}
+
+			return e.Wrap(op, &e.RetryError{RetryAfter: retryAfter})
+		}
+	}
+
+	if err := t.cleanup(ctx, now); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Release снимает попытку, учтённую Acquire, не сбрасывая счётчики: пароль верный,
+// но вход ещё не завершён (осталось ввести код второго фактора)
+func (t *LoginThrottle) Release(ctx context.Context, email, ip string) error {
+	const op = "LoginThrottle.Release"
+
+	if err := t.forgive(ctx, t.limits(email, ip)); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Succeeded завершает успешный вход: счётчик аккаунта сбрасывается,
+// а с IP снимается только учтённая попытка (см. Reset)
+func (t *LoginThrottle) Succeeded(ctx context.Context, email, ip string) error {
+	const op = "LoginThrottle.Succeeded"
+
+	if err := t.Reset(ctx, email); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if ip != "" {
+		if err := t.attemptRepo.Forgive(ctx, ipAttemptKey(ip)); err != nil {
+			return e.Wrap(op, err)
+		}
+	}
+
+	return nil
+}
+
+// Reset сбрасывает счётчик аккаунта. Счётчик IP не сбрасывается: иначе, входя
+// время от времени в свой аккаунт, можно было бы перебирать пароли к чужим
+func (t *LoginThrottle) Reset(ctx context.Context, email string) error {
+	const op = "LoginThrottle.Reset"
+
+	if err := t.attemptRepo.Reset(ctx, emailAttemptKey(email)); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+type attemptLimit struct {
+	key    string
+	policy domain.LockoutPolicy
+}
+
+func (t *LoginThrottle) limits(email, ip string) []attemptLimit {
+	limits := []attemptLimit{{key: emailAttemptKey(email), policy: t.emailPolicy}}
+	if ip != "" {
+		limits = append(limits, attemptLimit{key: ipAttemptKey(ip), policy: t.ipPolicy})
+	}
+
+	return limits
+}
+
+func (t *LoginThrottle) forgive(ctx context.Context, limits []attemptLimit) error {
+	for _, limit := range limits {
+		if err := t.attemptRepo.Forgive(ctx, limit.key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cleanup раз в окно удаляет счётчики, которые уже ни на что не влияют
+func (t *LoginThrottle) cleanup(ctx context.Context, now time.Time) error {
+	window := max(t.emailPolicy.Window, t.ipPolicy.Window)
+
+	t.mu.Lock()
+	if now.Sub(t.lastCleanup) < window {
+		t.mu.Unlock()
+		return nil
+	}
+	t.lastCleanup = now
+	t.mu.Unlock()
+
+	return t.attemptRepo.DeleteStale(ctx, now.Add(-window))
+}
+
+func emailAttemptKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository/memory"
+	"my_blog_backend/pkg/e"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testLockout = domain.LockoutPolicy{
+	FreeAttempts: 3,
+	BaseDelay:    time.Minute,
+	MaxDelay:     time.Hour,
+	Window:       time.Hour,
+}
+
+func newTestThrottle() *LoginThrottle {
+	clock := fixedClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	return NewLoginThrottle(memory.NewLoginAttemptRepository(), clock, testLockout, testLockout)
+}
+
+func isRetryError(err error) bool {
+	var retryErr *e.RetryError
+	return errors.As(err, &retryErr)
+}
+
+// Параллельные попытки не должны пройти проверку по одному и тому же значению счётчика
+func TestLoginThrottleAcquireIsAtomic(t *testing.T) {
+	throttle := newTestThrottle()
+
+	const attempts = 50
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := throttle.Acquire(context.Background(), "victim@example.com", "10.0.0.1")
+			switch {
+			case err == nil:
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			case !isRetryError(err):
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Первые FreeAttempts проходят без задержки, следующая попадает под паузу
+	if allowed != testLockout.FreeAttempts {
+		t.Errorf("allowed attempts = %d, want %d", allowed, testLockout.FreeAttempts)
+	}
+}
+
+func TestLoginThrottleSuccessfulAttempts(t *testing.T) {
+	tests := []struct {
+		name string
+		// finish вызывается после каждой успешной попытки
+		finish func(*LoginThrottle) error
+	}{
+		{
+			name: "succeeded",
+			finish: func(throttle *LoginThrottle) error {
+				return throttle.Succeeded(context.Background(), "user@example.com", "10.0.0.1")
+			},
+		},
+		{
+			name: "released for second factor",
+			finish: func(throttle *LoginThrottle) error {
+				return throttle.Release(context.Background(), "user@example.com", "10.0.0.1")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle := newTestThrottle()
+
+			// Успешные входы не копятся в счётчиках и не ведут к блокировке
+			for i := 0; i < 2*testLockout.FreeAttempts; i++ {
+				if err := throttle.Acquire(context.Background(), "user@example.com", "10.0.0.1"); err != nil {
+					t.Fatalf("attempt %d: %v", i+1, err)
+				}
+				if err := tt.finish(throttle); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoginThrottleBlockedAttemptIsNotCounted(t *testing.T) {
+	throttle := newTestThrottle()
+
+	// Неудачные попытки по разным аккаунтам исчерпывают лимит IP
+	for i := 0; i < testLockout.FreeAttempts; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if err := throttle.Acquire(context.Background(), email, "10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := throttle.Acquire(context.Background(), "b@example.com", "10.0.0.1"); !isRetryError(err) {
+			t.Fatalf("attempt from a blocked IP: error = %v, want RetryError", err)
+		}
+	}
+
+	// Попытки b@example.com с заблокированного IP не учлись для аккаунта
+	for i := 0; i < testLockout.FreeAttempts; i++ {
+		if err := throttle.Acquire(context.Background(), "b@example.com", "10.0.0.2"); err != nil {
+			t.Fatalf("attempt %d from another IP: %v", i+1, err)
+		}
+	}
+}
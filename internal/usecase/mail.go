@@ -0,0 +1,17 @@
+package usecase
+
+import "net/url"
+
+// tokenLink добавляет одноразовый токен к адресу страницы фронтенда
+func tokenLink(baseURL, token string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+)
+
+// PasswordValidator проверяет новый пароль при регистрации, смене и сбросе
+type PasswordValidator struct {
+	policy  domain.PasswordPolicy
+	checker PasswordChecker
+}
+
+func NewPasswordValidator(policy domain.PasswordPolicy, checker PasswordChecker) *PasswordValidator {
+	return &PasswordValidator{
+		policy:  policy,
+		checker: checker,
+	}
+}
+
+func (v *PasswordValidator) Validate(password, username, email string) error {
+	const op = "PasswordValidator.Validate"
+
+	if v.policy.TooLong(password) {
+		return e.Wrap(op, &e.PasswordPolicyError{Reasons: []string{domain.PasswordTooLong}})
+	}
+
+	breached, err := v.checker.IsBreached(password)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	err = v.policy.Check(domain.PasswordCandidate{
+		Password: password,
+		Username: username,
+		Email:    email,
+		Score:    v.checker.Score(password, username, email),
+		Breached: breached,
+	})
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
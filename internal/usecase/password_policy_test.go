@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// countingChecker считает вызовы, чтобы проверить, что до них дело не дошло
+type countingChecker struct {
+	calls int
+}
+
+func (c *countingChecker) Score(string, ...string) int {
+	c.calls++
+	return 4
+}
+
+func (c *countingChecker) IsBreached(string) (bool, error) {
+	c.calls++
+	return false, nil
+}
+
+func TestPasswordValidatorRejectsLongPasswordBeforeChecker(t *testing.T) {
+	checker := &countingChecker{}
+	validator := NewPasswordValidator(domain.PasswordPolicy{MinLength: 8, MaxLength: 128}, checker)
+
+	err := validator.Validate(strings.Repeat("ab", 512), "ivan", "ivan@example.com")
+
+	var policyErr *e.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("Validate() error = %v, want *e.PasswordPolicyError", err)
+	}
+	if !slices.Equal(policyErr.Reasons, []string{domain.PasswordTooLong}) {
+		t.Errorf("Reasons = %v, want [%s]", policyErr.Reasons, domain.PasswordTooLong)
+	}
+	if checker.calls != 0 {
+		t.Errorf("checker was called %d times for an overlong password", checker.calls)
+	}
+
+	if err := validator.Validate(strings.Repeat("ab", 64), "ivan", "ivan@example.com"); err != nil {
+		t.Fatalf("Validate() at max length error = %v", err)
+	}
+	if checker.calls == 0 {
+		t.Errorf("checker was not called for a password within the limit")
+	}
+}
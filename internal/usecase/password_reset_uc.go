@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetService struct {
+	userRepo     repository.UserRepository
+	sessionRepo  repository.SessionRepository
+	resetRepo    repository.PasswordResetRepository
+	tokenManager TokenManager
+	hashManager  HashManager
+	mailer       Mailer
+	clock        Clock
+	throttle     *LoginThrottle
+	passwords    *PasswordValidator
+	resetURL     string
+	tokenTTL     time.Duration
+
+	// Письма, которые ещё отправляются в фоне
+	pending sync.WaitGroup
+}
+
+// forgotPasswordTimeout ограничивает фоновую отправку ссылки для сброса
+const forgotPasswordTimeout = time.Minute
+
+// resetURL - адрес страницы сброса пароля на фронтенде, токен добавляется параметром token
+func NewPasswordResetService(u repository.UserRepository, s repository.SessionRepository, r repository.PasswordResetRepository, tm TokenManager, hm HashManager, mailer Mailer, clock Clock, lt *LoginThrottle, pv *PasswordValidator, resetURL string, tokenTTL time.Duration) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:     u,
+		sessionRepo:  s,
+		resetRepo:    r,
+		tokenManager: tm,
+		hashManager:  hm,
+		mailer:       mailer,
+		clock:        clock,
+		throttle:     lt,
+		passwords:    pv,
+		resetURL:     resetURL,
+		tokenTTL:     tokenTTL,
+	}
+}
+
+// ForgotPassword запускает отправку ссылки для сброса в фоне и сразу возвращается.
+// Ни ответ, ни время ответа не зависят от того, есть ли аккаунт с таким email
+// и удалось ли отправить письмо, поэтому ошибки только пишутся в лог
+func (s *PasswordResetService) ForgotPassword(ctx context.Context, email string) {
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+
+		// Запрос клиента к этому моменту уже завершён, его контекст отменён
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), forgotPasswordTimeout)
+		defer cancel()
+
+		if err := s.sendResetLink(ctx, email); err != nil {
+			log.Printf("forgot password: %v", err)
+		}
+	}()
+}
+
+// Wait дожидается писем, отправка которых уже началась. Вызывается при остановке сервера
+func (s *PasswordResetService) Wait() {
+	s.pending.Wait()
+}
+
+// sendResetLink создаёт токен сброса и отправляет ссылку владельцу email.
+// Для неизвестного email ничего не делает
+func (s *PasswordResetService) sendResetLink(ctx context.Context, email string) error {
+	const op = "PasswordResetService.sendResetLink"
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, e.ErrUserNotFound) {
+			return nil
+		}
+
+		return e.Wrap(op, err)
+	}
+
+	now := s.clock.Now()
+
+	// Действует только последняя ссылка
+	if err := s.resetRepo.InvalidateByUser(ctx, user.ID, now); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	token, tokenHash, err := s.tokenManager.NewRefreshToken()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.resetRepo.Create(ctx, domain.NewPasswordResetToken(user.ID, tokenHash, now.Add(s.tokenTTL))); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	link, err := tokenLink(s.resetURL, token)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	mail := &Mail{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf(
+			"Hi, %s!\n\nSomeone requested a password reset for your account.\n"+
+				"Follow the link to set a new password:\n\n%s\n\n"+
+				"The link expires in %s. If you did not request a reset, ignore this email.\n",
+			user.Username, link, s.tokenTTL,
+		),
+	}
+	if err := s.mailer.Send(ctx, mail); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// ResetPassword меняет пароль по токену из письма, завершает все сессии пользователя
+// и снимает блокировку входа
+func (s *PasswordResetService) ResetPassword(ctx context.Context, req *ResetPasswordReq) error {
+	const op = "PasswordResetService.ResetPassword"
+
+	resetToken, err := s.resetRepo.GetByTokenHash(ctx, s.tokenManager.HashRefreshToken(req.Token))
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	now := s.clock.Now()
+	if err := resetToken.ValidateState(now); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	user, err := s.userRepo.GetById(ctx, resetToken.UserID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	// Проверяем до MarkUsed, чтобы отклонённый пароль не сжигал ссылку из письма
+	if err := s.passwords.Validate(req.NewPassword, user.Username, user.Email); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.resetRepo.MarkUsed(ctx, resetToken.ID, now); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	newPassHash, err := s.hashManager.HashPassword(req.NewPassword)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.userRepo.SetPasswordHash(ctx, user.ID, newPassHash); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.resetRepo.InvalidateByUser(ctx, user.ID, now); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if _, err := s.sessionRepo.RevokeAllByUser(ctx, user.ID, uuid.Nil); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	// Владелец подтвердил доступ к почте, блокировка входа ему больше не нужна
+	if err := s.throttle.Reset(ctx, user.Email); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
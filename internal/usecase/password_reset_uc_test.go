@@ -0,0 +1,436 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/internal/repository/memory"
+	"my_blog_backend/internal/usecase"
+	"my_blog_backend/pkg/auth/token"
+	"my_blog_backend/pkg/e"
+	"my_blog_backend/pkg/mailer"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const resetTokenTTL = time.Hour
+
+type resetClock struct {
+	now time.Time
+}
+
+func (c *resetClock) Now() time.Time {
+	return c.now
+}
+
+type resetUserRepo struct {
+	repository.UserRepository
+
+	users map[uint]*domain.User
+}
+
+func (r *resetUserRepo) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+
+	return nil, e.ErrUserNotFound
+}
+
+func (r *resetUserRepo) GetById(_ context.Context, id uint) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, e.ErrUserNotFound
+	}
+
+	copied := *user
+	return &copied, nil
+}
+
+func (r *resetUserRepo) SetPasswordHash(_ context.Context, id uint, hash string) error {
+	user, ok := r.users[id]
+	if !ok {
+		return e.ErrUserNotFound
+	}
+
+	user.PasswordHash = hash
+	return nil
+}
+
+type resetSessionRepo struct {
+	repository.SessionRepository
+
+	revokedUsers []uint
+}
+
+func (r *resetSessionRepo) RevokeAllByUser(_ context.Context, userId uint, _ uuid.UUID) (int64, error) {
+	r.revokedUsers = append(r.revokedUsers, userId)
+	return 1, nil
+}
+
+// resetTokenRepo повторяет условные UPDATE из postgres
+type resetTokenRepo struct {
+	tokens []*domain.PasswordResetToken
+}
+
+func (r *resetTokenRepo) Create(_ context.Context, t *domain.PasswordResetToken) error {
+	t.ID = uint(len(r.tokens) + 1)
+	copied := *t
+	r.tokens = append(r.tokens, &copied)
+	return nil
+}
+
+func (r *resetTokenRepo) GetByTokenHash(_ context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+
+	return nil, e.ErrPasswordResetTokenInvalid
+}
+
+func (r *resetTokenRepo) MarkUsed(_ context.Context, id uint, usedAt time.Time) error {
+	for _, t := range r.tokens {
+		if t.ID == id && t.UsedAt == nil {
+			t.UsedAt = &usedAt
+			return nil
+		}
+	}
+
+	return e.ErrPasswordResetTokenInvalid
+}
+
+func (r *resetTokenRepo) InvalidateByUser(_ context.Context, userID uint, at time.Time) error {
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+
+	return nil
+}
+
+func (r *resetTokenRepo) active() int {
+	count := 0
+	for _, t := range r.tokens {
+		if t.UsedAt == nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+type plainHashManager struct{}
+
+func (plainHashManager) HashPassword(password string) (string, error) {
+	return "plain:" + password, nil
+}
+
+func (plainHashManager) Compare(password string, hash string) error {
+	if hash != "plain:"+password {
+		return e.ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
+
+func (plainHashManager) NeedsRehash(string) bool {
+	return false
+}
+
+type strongPasswordChecker struct{}
+
+func (strongPasswordChecker) Score(string, ...string) int {
+	return 4
+}
+
+func (strongPasswordChecker) IsBreached(string) (bool, error) {
+	return false, nil
+}
+
+type failingMailer struct{}
+
+func (failingMailer) Send(context.Context, *usecase.Mail) error {
+	return errors.New("smtp is down")
+}
+
+type resetFixture struct {
+	svc      *usecase.PasswordResetService
+	users    *resetUserRepo
+	sessions *resetSessionRepo
+	tokens   *resetTokenRepo
+	attempts *memory.LoginAttemptRepository
+	throttle *usecase.LoginThrottle
+	clock    *resetClock
+}
+
+func newResetFixture(m usecase.Mailer) *resetFixture {
+	f := &resetFixture{
+		users: &resetUserRepo{users: map[uint]*domain.User{
+			1: {ID: 1, Username: "alice", Email: "alice@example.com", PasswordHash: "plain:old-password"},
+		}},
+		sessions: &resetSessionRepo{},
+		tokens:   &resetTokenRepo{},
+		attempts: memory.NewLoginAttemptRepository(),
+		clock:    &resetClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)},
+	}
+
+	lockout := domain.LockoutPolicy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+	f.throttle = usecase.NewLoginThrottle(f.attempts, f.clock, lockout, lockout)
+	passwords := usecase.NewPasswordValidator(domain.PasswordPolicy{MinLength: 8, MaxLength: 128}, strongPasswordChecker{})
+	tokens := token.NewTokenManager(token.NewHMACKeySet("access-secret"), "refresh-secret", time.Minute)
+
+	f.svc = usecase.NewPasswordResetService(f.users, f.sessions, f.tokens, tokens, plainHashManager{}, m, f.clock, f.throttle, passwords, "https://blog.example.com/reset", resetTokenTTL)
+	return f
+}
+
+var resetLinkRe = regexp.MustCompile(`https://blog\.example\.com/reset\?token=\S+`)
+
+// resetTokenFromMail достаёт токен из ссылки в последнем письме
+func resetTokenFromMail(t *testing.T, m *mailer.MemoryMailer) string {
+	t.Helper()
+
+	mails := m.Mails()
+	if len(mails) == 0 {
+		t.Fatal("no mail was sent")
+	}
+
+	link := resetLinkRe.FindString(mails[len(mails)-1].Body)
+	if link == "" {
+		t.Fatalf("mail has no reset link: %q", mails[len(mails)-1].Body)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return u.Query().Get("token")
+}
+
+func TestPasswordResetService_ForgotPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		emails     []string
+		failMail   bool
+		wantMails  int
+		wantTokens int
+		wantActive int
+	}{
+		{
+			name:       "known email gets a link",
+			emails:     []string{"alice@example.com"},
+			wantMails:  1,
+			wantTokens: 1,
+			wantActive: 1,
+		},
+		{
+			name:   "unknown email is silently ignored",
+			emails: []string{"nobody@example.com"},
+		},
+		{
+			name:       "new request invalidates the previous link",
+			emails:     []string{"alice@example.com", "alice@example.com"},
+			wantMails:  2,
+			wantTokens: 2,
+			wantActive: 1,
+		},
+		{
+			name:       "mailer error is not returned to the caller",
+			emails:     []string{"alice@example.com"},
+			failMail:   true,
+			wantTokens: 1,
+			wantActive: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memoryMailer := mailer.NewMemoryMailer()
+			var m usecase.Mailer = memoryMailer
+			if tt.failMail {
+				m = failingMailer{}
+			}
+			f := newResetFixture(m)
+
+			for _, email := range tt.emails {
+				f.svc.ForgotPassword(context.Background(), email)
+				f.svc.Wait()
+			}
+
+			if got := len(memoryMailer.Mails()); got != tt.wantMails {
+				t.Errorf("mails sent = %d, want %d", got, tt.wantMails)
+			}
+			if got := len(f.tokens.tokens); got != tt.wantTokens {
+				t.Errorf("tokens created = %d, want %d", got, tt.wantTokens)
+			}
+			if got := f.tokens.active(); got != tt.wantActive {
+				t.Errorf("active tokens = %d, want %d", got, tt.wantActive)
+			}
+
+			if tt.wantMails > 0 {
+				mail := memoryMailer.Mails()[0]
+				if mail.To != "alice@example.com" {
+					t.Errorf("mail sent to %q, want alice@example.com", mail.To)
+				}
+			}
+		})
+	}
+}
+
+// ForgotPassword не должен ждать отправки: иначе по времени ответа видно, есть ли аккаунт
+func TestPasswordResetService_ForgotPasswordDoesNotWaitForMailer(t *testing.T) {
+	release := make(chan struct{})
+	f := newResetFixture(blockingMailer{release: release})
+
+	done := make(chan struct{})
+	go func() {
+		f.svc.ForgotPassword(context.Background(), "alice@example.com")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ForgotPassword blocked on the mailer")
+	}
+
+	close(release)
+	f.svc.Wait()
+}
+
+type blockingMailer struct {
+	release chan struct{}
+}
+
+func (m blockingMailer) Send(ctx context.Context, _ *usecase.Mail) error {
+	select {
+	case <-m.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestPasswordResetService_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name string
+		// token подменяет токен из письма, если не пустой
+		token       string
+		advance     time.Duration
+		newPassword string
+		wantErr     error
+		wantPolicy  bool
+	}{
+		{
+			name:        "valid token",
+			newPassword: "brand-new-secret",
+		},
+		{
+			name:        "unknown token",
+			token:       "not-a-real-token",
+			newPassword: "brand-new-secret",
+			wantErr:     e.ErrPasswordResetTokenInvalid,
+		},
+		{
+			name:        "expired token",
+			advance:     resetTokenTTL,
+			newPassword: "brand-new-secret",
+			wantErr:     e.ErrPasswordResetTokenInvalid,
+		},
+		{
+			name:        "password rejected by policy",
+			newPassword: "alice-password",
+			wantPolicy:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mailer.NewMemoryMailer()
+			f := newResetFixture(m)
+
+			// Блокировка входа, которую должен снять сброс
+			for i := 0; i < 3; i++ {
+				// Acquire без последующего Succeeded - это неудачная попытка
+				if err := f.throttle.Acquire(context.Background(), "alice@example.com", "10.0.0.1"); err != nil {
+					var retryErr *e.RetryError
+					if !errors.As(err, &retryErr) {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			f.svc.ForgotPassword(context.Background(), "alice@example.com")
+			f.svc.Wait()
+
+			resetToken := resetTokenFromMail(t, m)
+			if tt.token != "" {
+				resetToken = tt.token
+			}
+			f.clock.now = f.clock.now.Add(tt.advance)
+
+			err := f.svc.ResetPassword(context.Background(), &usecase.ResetPasswordReq{
+				Token:       resetToken,
+				NewPassword: tt.newPassword,
+			})
+
+			var policyErr *e.PasswordPolicyError
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ResetPassword() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantPolicy:
+				if !errors.As(err, &policyErr) {
+					t.Fatalf("ResetPassword() error = %v, want PasswordPolicyError", err)
+				}
+			case err != nil:
+				t.Fatalf("ResetPassword() error = %v", err)
+			}
+
+			changed := f.users.users[1].PasswordHash == "plain:"+tt.newPassword
+			if succeeded := tt.wantErr == nil && !tt.wantPolicy; changed != succeeded {
+				t.Fatalf("password changed = %v, want %v", changed, succeeded)
+			}
+
+			if !changed {
+				if len(f.sessions.revokedUsers) != 0 {
+					t.Errorf("sessions revoked after a failed reset")
+				}
+				// Отклонённый пароль не сжигает ссылку
+				if tt.wantPolicy && f.tokens.active() != 1 {
+					t.Errorf("active tokens = %d, want 1", f.tokens.active())
+				}
+				return
+			}
+
+			if len(f.sessions.revokedUsers) != 1 || f.sessions.revokedUsers[0] != 1 {
+				t.Errorf("revoked sessions of users %v, want [1]", f.sessions.revokedUsers)
+			}
+			if err := f.throttle.Acquire(context.Background(), "alice@example.com", "10.0.0.2"); err != nil {
+				t.Errorf("account is still locked after reset: %v", err)
+			}
+
+			// Ссылка одноразовая
+			err = f.svc.ResetPassword(context.Background(), &usecase.ResetPasswordReq{
+				Token:       resetToken,
+				NewPassword: "another-new-secret",
+			})
+			if !errors.Is(err, e.ErrPasswordResetTokenInvalid) {
+				t.Errorf("second ResetPassword() error = %v, want %v", err, e.ErrPasswordResetTokenInvalid)
+			}
+		})
+	}
+}
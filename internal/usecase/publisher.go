@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"time"
+)
+
+// ScheduledPublisher периодически публикует статьи, у которых наступило время publish_at.
+// Статья переводится в published только условным UPDATE, поэтому при сбое между выборкой
+// и обновлением она будет подхвачена на следующем проходе (at-least-once)
+type ScheduledPublisher struct {
+	articleRepo repository.ArticleRepository
+	clock       Clock
+	interval    time.Duration
+	batchSize   int
+}
+
+func NewScheduledPublisher(a repository.ArticleRepository, clock Clock, interval time.Duration, batchSize int) *ScheduledPublisher {
+	return &ScheduledPublisher{
+		articleRepo: a,
+		clock:       clock,
+		interval:    interval,
+		batchSize:   batchSize,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (p *ScheduledPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.PublishDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("scheduled publisher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue публикует все статьи, время публикации которых уже наступило, и возвращает их количество
+func (p *ScheduledPublisher) PublishDue(ctx context.Context) (int, error) {
+	const op = "ScheduledPublisher.PublishDue"
+
+	now := p.clock.Now()
+	published := 0
+	for {
+		articles, err := p.articleRepo.ListDueForPublishing(ctx, now, p.batchSize)
+		if err != nil {
+			return published, e.Wrap(op, err)
+		}
+
+		for _, article := range articles {
+			publishAt := *article.PublishAt
+			if err := article.Publish(publishAt); err != nil {
+				return published, e.Wrap(op, err)
+			}
+
+			if err := p.articleRepo.PublishScheduled(ctx, &article, publishAt, now); err != nil {
+				if errors.Is(err, e.ErrArticleNotScheduled) {
+					continue
+				}
+
+				return published, e.Wrap(op, err)
+			}
+
+			published++
+		}
+
+		if len(articles) < p.batchSize {
+			return published, nil
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"sort"
+	"testing"
+	"time"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+// publisherArticleRepo хранит статьи в памяти и повторяет условный UPDATE из postgres
+type publisherArticleRepo struct {
+	repository.ArticleRepository
+
+	articles map[uint]*domain.Article
+	listErr  error
+	// afterList вызывается после выборки, чтобы смоделировать параллельную правку статьи
+	afterList func(map[uint]*domain.Article)
+}
+
+func (r *publisherArticleRepo) ListDueForPublishing(_ context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	if r.listErr != nil {
+		return nil, r.listErr
+	}
+
+	var due []domain.Article
+	for _, article := range r.articles {
+		if article.Status == domain.ArticleStatusDraft && article.PublishAt != nil && !article.PublishAt.After(now) {
+			due = append(due, *article)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	if r.afterList != nil {
+		r.afterList(r.articles)
+	}
+
+	return due, nil
+}
+
+func (r *publisherArticleRepo) PublishScheduled(_ context.Context, article *domain.Article, scheduledAt, now time.Time) error {
+	stored := r.articles[article.ID]
+	if stored == nil || stored.Status != domain.ArticleStatusDraft || stored.PublishAt == nil ||
+		!stored.PublishAt.Equal(scheduledAt) || stored.PublishAt.After(now) {
+		return e.ErrArticleNotScheduled
+	}
+
+	stored.Status = article.Status
+	stored.PublishedAt = article.PublishedAt
+	stored.PublishAt = nil
+	return nil
+}
+
+func TestScheduledPublisherPublishDue(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	draft := func(id uint, publishAt *time.Time) *domain.Article {
+		return &domain.Article{ID: id, Status: domain.ArticleStatusDraft, PublishAt: publishAt}
+	}
+
+	tests := []struct {
+		name          string
+		articles      []*domain.Article
+		batchSize     int
+		listErr       error
+		afterList     func(map[uint]*domain.Article)
+		wantPublished int
+		wantErr       bool
+		wantStatus    map[uint]domain.ArticleStatus
+	}{
+		{
+			name:          "due article is published",
+			articles:      []*domain.Article{draft(1, &past)},
+			batchSize:     10,
+			wantPublished: 1,
+			wantStatus:    map[uint]domain.ArticleStatus{1: domain.ArticleStatusPublished},
+		},
+		{
+			name:          "future and unscheduled drafts are left alone",
+			articles:      []*domain.Article{draft(1, &future), draft(2, nil)},
+			batchSize:     10,
+			wantPublished: 0,
+			wantStatus:    map[uint]domain.ArticleStatus{1: domain.ArticleStatusDraft, 2: domain.ArticleStatusDraft},
+		},
+		{
+			name:          "all batches are processed",
+			articles:      []*domain.Article{draft(1, &past), draft(2, &past), draft(3, &past), draft(4, &past), draft(5, &past)},
+			batchSize:     2,
+			wantPublished: 5,
+		},
+		{
+			name:      "article rescheduled after listing is skipped",
+			articles:  []*domain.Article{draft(1, &past), draft(2, &past)},
+			batchSize: 10,
+			afterList: func(articles map[uint]*domain.Article) {
+				articles[1].PublishAt = &future
+			},
+			wantPublished: 1,
+			wantStatus:    map[uint]domain.ArticleStatus{1: domain.ArticleStatusDraft, 2: domain.ArticleStatusPublished},
+		},
+		{
+			name:      "article unscheduled after listing is skipped",
+			articles:  []*domain.Article{draft(1, &past)},
+			batchSize: 10,
+			afterList: func(articles map[uint]*domain.Article) {
+				articles[1].PublishAt = nil
+			},
+			wantPublished: 0,
+			wantStatus:    map[uint]domain.ArticleStatus{1: domain.ArticleStatusDraft},
+		},
+		{
+			name:      "repository error is returned",
+			batchSize: 10,
+			listErr:   errors.New("connection refused"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &publisherArticleRepo{
+				articles:  make(map[uint]*domain.Article),
+				listErr:   tt.listErr,
+				afterList: tt.afterList,
+			}
+			for _, article := range tt.articles {
+				repo.articles[article.ID] = article
+			}
+
+			publisher := NewScheduledPublisher(repo, fixedClock{now: now}, time.Minute, tt.batchSize)
+			published, err := publisher.PublishDue(context.Background())
+
+			if tt.wantErr {
+				if !errors.Is(err, tt.listErr) {
+					t.Fatalf("PublishDue() error = %v, want %v", err, tt.listErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PublishDue() unexpected error: %v", err)
+			}
+
+			if published != tt.wantPublished {
+				t.Errorf("PublishDue() = %d, want %d", published, tt.wantPublished)
+			}
+
+			for id, want := range tt.wantStatus {
+				if got := repo.articles[id].Status; got != want {
+					t.Errorf("article %d status = %s, want %s", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduledPublisherKeepsScheduledTimeAsPublishedAt(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	publishAt := now.Add(-30 * time.Minute)
+
+	repo := &publisherArticleRepo{articles: map[uint]*domain.Article{
+		1: {ID: 1, Status: domain.ArticleStatusDraft, PublishAt: &publishAt},
+	}}
+
+	publisher := NewScheduledPublisher(repo, fixedClock{now: now}, time.Minute, 10)
+	if _, err := publisher.PublishDue(context.Background()); err != nil {
+		t.Fatalf("PublishDue() unexpected error: %v", err)
+	}
+
+	article := repo.articles[1]
+	if article.PublishedAt == nil || !article.PublishedAt.Equal(publishAt) {
+		t.Errorf("PublishedAt = %v, want %v", article.PublishedAt, publishAt)
+	}
+	if article.PublishAt != nil {
+		t.Errorf("PublishAt = %v, want nil", article.PublishAt)
+	}
+}
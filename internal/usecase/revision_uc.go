@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/diff"
+	"my_blog_backend/pkg/e"
+)
+
+// История правок доступна только автору статьи
+
+func (s *ArticleService) ListRevisions(ctx context.Context, req *ListRevisionsReq) ([]*RevisionRes, error) {
+	const op = "ArticleService.ListRevisions"
+
+	if _, err := s.getAuthoredArticle(ctx, req.ArticleId, req.UserId); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	revisions, err := s.revisionRepo.ListByArticle(ctx, req.ArticleId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	res := make([]*RevisionRes, len(revisions))
+	for i, revision := range revisions {
+		res[i] = toRevisionRes(&revision)
+	}
+
+	return res, nil
+}
+
+func (s *ArticleService) GetRevision(ctx context.Context, req *GetRevisionReq) (*RevisionRes, error) {
+	const op = "ArticleService.GetRevision"
+
+	if _, err := s.getAuthoredArticle(ctx, req.ArticleId, req.UserId); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	revision, err := s.revisionRepo.GetByNumber(ctx, req.ArticleId, req.Number)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toRevisionRes(revision), nil
+}
+
+func (s *ArticleService) DiffRevisions(ctx context.Context, req *DiffRevisionsReq) (*RevisionDiffRes, error) {
+	const op = "ArticleService.DiffRevisions"
+
+	if _, err := s.getAuthoredArticle(ctx, req.ArticleId, req.UserId); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	from, err := s.revisionRepo.GetByNumber(ctx, req.ArticleId, req.From)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	to, err := s.revisionRepo.GetByNumber(ctx, req.ArticleId, req.To)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	fromText, err := s.revisionDocument(ctx, from)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	toText, err := s.revisionDocument(ctx, to)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return &RevisionDiffRes{
+		From: from.Number,
+		To:   to.Number,
+		Diff: diff.Unified(
+			fromText,
+			toText,
+			fmt.Sprintf("revision %d", from.Number),
+			fmt.Sprintf("revision %d", to.Number),
+			diff.DefaultContext,
+		),
+	}, nil
+}
+
+// RestoreRevision возвращает статью к состоянию ревизии. История не переписывается:
+// восстановление сохраняется как новая ревизия
+func (s *ArticleService) RestoreRevision(ctx context.Context, req *RestoreRevisionReq) (*UpdateArticleRes, error) {
+	const op = "ArticleService.RestoreRevision"
+
+	article, err := s.getAuthoredArticle(ctx, req.ArticleId, req.UserId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	revision, err := s.revisionRepo.GetByNumber(ctx, req.ArticleId, req.Number)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	var category *domain.Category
+	if revision.CategoryID != 0 {
+		category, err = s.categoryRepo.GetByID(ctx, revision.CategoryID)
+		if err != nil && !errors.Is(err, e.ErrCategoryNotFound) {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	if err := article.Restore(revision, category); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	updArticle, err := s.articleRepo.Update(ctx, article, req.UserId, nil)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.render(updArticle); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toUpdateArticleRes(updArticle), nil
+}
+
+func (s *ArticleService) getAuthoredArticle(ctx context.Context, articleId, userId uint) (*domain.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, articleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, article, userId); err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// Текст ревизии для сравнения: заголовок и категория идут заголовками перед содержимым
+func (s *ArticleService) revisionDocument(ctx context.Context, revision *domain.ArticleRevision) (string, error) {
+	categoryName := "(deleted)"
+	if revision.CategoryID != 0 {
+		category, err := s.categoryRepo.GetByID(ctx, revision.CategoryID)
+		switch {
+		case err == nil:
+			categoryName = category.Name
+		case !errors.Is(err, e.ErrCategoryNotFound):
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("Title: %s\nCategory: %s\n\n%s\n", revision.Title, categoryName, revision.Content), nil
+}
+
+func toRevisionRes(revision *domain.ArticleRevision) *RevisionRes {
+	res := &RevisionRes{
+		Number:     revision.Number,
+		Title:      revision.Title,
+		Content:    revision.Content,
+		CategoryId: revision.CategoryID,
+		CreatedAt:  revision.CreatedAt,
+	}
+
+	if revision.Editor != nil {
+		res.Editor = &CommentAuthorRes{
+			Id:       revision.Editor.ID,
+			Username: revision.Editor.Username,
+		}
+	}
+
+	return res
+}
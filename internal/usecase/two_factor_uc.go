@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/auth/totp"
+	"my_blog_backend/pkg/e"
+	"strings"
+)
+
+const (
+	recoveryCodesCount = 10
+	// Допуск в один шаг в обе стороны на расхождение часов телефона и сервера
+	totpSkew = 1
+)
+
+type TwoFactorService struct {
+	userRepo     repository.UserRepository
+	recoveryRepo repository.RecoveryCodeRepository
+	tokenManager TokenManager
+	hashManager  HashManager
+	clock        Clock
+	issuer       string
+}
+
+// issuer - название сервиса, которое приложение-аутентификатор покажет рядом с кодом
+func NewTwoFactorService(u repository.UserRepository, rc repository.RecoveryCodeRepository, tm TokenManager, hm HashManager, clock Clock, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:     u,
+		recoveryRepo: rc,
+		tokenManager: tm,
+		hashManager:  hm,
+		clock:        clock,
+		issuer:       issuer,
+	}
+}
+
+// Enroll выдаёт новый секрет. 2FA не включается, пока пользователь не подтвердит
+// его кодом из приложения
+func (s *TwoFactorService) Enroll(ctx context.Context, userId uint) (*TwoFactorEnrollmentRes, error) {
+	const op = "TwoFactorService.Enroll"
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := user.StartTOTPEnrollment(secret); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.userRepo.SetTOTP(ctx, user.ID, user.TOTPSecret, user.TOTPEnabledAt); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return &TwoFactorEnrollmentRes{
+		Secret: secret,
+		URI:    totp.URI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm включает 2FA и возвращает коды восстановления. Коды показываются один раз
+func (s *TwoFactorService) Confirm(ctx context.Context, userId uint, code string) (*RecoveryCodesRes, error) {
+	const op = "TwoFactorService.Confirm"
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if user.TOTPSecret == nil {
+		return nil, e.Wrap(op, e.ErrTwoFactorNotEnrolled)
+	}
+
+	if err := s.verifyTOTP(ctx, user, code); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := user.EnableTOTP(s.clock.Now()); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.userRepo.SetTOTP(ctx, user.ID, user.TOTPSecret, user.TOTPEnabledAt); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	res, err := s.issueRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return res, nil
+}
+
+// RegenerateRecoveryCodes заменяет все коды восстановления новыми
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userId uint, code string) (*RecoveryCodesRes, error) {
+	const op = "TwoFactorService.RegenerateRecoveryCodes"
+
+	user, err := s.enabledUser(ctx, userId)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.verifyTOTP(ctx, user, code); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	res, err := s.issueRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return res, nil
+}
+
+// Disable выключает 2FA. Нужны и пароль, и второй фактор, чтобы украденной
+// сессии не хватило для отключения защиты
+func (s *TwoFactorService) Disable(ctx context.Context, userId uint, req *DisableTwoFactorReq) error {
+	const op = "TwoFactorService.Disable"
+
+	user, err := s.enabledUser(ctx, userId)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.hashManager.Compare(req.Password, user.PasswordHash); err != nil {
+		if errors.Is(err, e.ErrMismatchedHashAndPassword) {
+			return e.Wrap(op, e.ErrInvalidCredentials)
+		}
+
+		return e.Wrap(op, err)
+	}
+
+	if err := s.Verify(ctx, user, req.Code); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := user.DisableTOTP(); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.userRepo.SetTOTP(ctx, user.ID, user.TOTPSecret, user.TOTPEnabledAt); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := s.recoveryRepo.DeleteByUser(ctx, user.ID); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// Verify проверяет второй фактор: код из приложения или неиспользованный код восстановления
+func (s *TwoFactorService) Verify(ctx context.Context, user *domain.User, code string) error {
+	const op = "TwoFactorService.Verify"
+
+	if !user.IsTwoFactorEnabled() {
+		return e.Wrap(op, e.ErrTwoFactorNotEnabled)
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		if err := s.verifyTOTP(ctx, user, code); err != nil {
+			return e.Wrap(op, err)
+		}
+
+		return nil
+	}
+
+	codeHash := s.tokenManager.HashRefreshToken(normalizeRecoveryCode(code))
+	if err := s.recoveryRepo.Use(ctx, user.ID, codeHash, s.clock.Now()); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (s *TwoFactorService) verifyTOTP(ctx context.Context, user *domain.User, code string) error {
+	if user.TOTPSecret == nil {
+		return e.ErrTwoFactorNotEnrolled
+	}
+
+	step, ok := totp.Validate(*user.TOTPSecret, code, s.clock.Now(), totpSkew)
+	if !ok {
+		return e.ErrTwoFactorCodeInvalid
+	}
+
+	return s.userRepo.UseTOTPStep(ctx, user.ID, step)
+}
+
+func (s *TwoFactorService) enabledUser(ctx context.Context, userId uint) (*domain.User, error) {
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return nil, e.ErrTwoFactorNotEnabled
+	}
+
+	return user, nil
+}
+
+func (s *TwoFactorService) issueRecoveryCodes(ctx context.Context, userId uint) (*RecoveryCodesRes, error) {
+	codes := make([]string, recoveryCodesCount)
+	models := make([]*domain.RecoveryCode, recoveryCodesCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes[i] = code
+		models[i] = domain.NewRecoveryCode(userId, s.tokenManager.HashRefreshToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := s.recoveryRepo.ReplaceByUser(ctx, userId, models); err != nil {
+		return nil, err
+	}
+
+	return &RecoveryCodesRes{Codes: codes}, nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCode возвращает код вида abcde-fghij (50 бит)
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// Коды принимаются в любом регистре, с дефисом и без
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
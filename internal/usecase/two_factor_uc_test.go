@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base32"
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/auth/totp"
+	"my_blog_backend/pkg/e"
+	"testing"
+	"time"
+)
+
+// totpUserRepo повторяет условный UPDATE из postgres: шаг принимается, только
+// если он больше последнего использованного
+type totpUserRepo struct {
+	repository.UserRepository
+
+	lastStep int64
+}
+
+func (r *totpUserRepo) UseTOTPStep(_ context.Context, _ uint, step int64) error {
+	if step <= r.lastStep {
+		return e.ErrTwoFactorCodeInvalid
+	}
+
+	r.lastStep = step
+	return nil
+}
+
+func TestTwoFactorServiceVerifyRejectsReplayedStep(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	user := &domain.User{ID: 1, TOTPSecret: &secret, TOTPEnabledAt: &now}
+
+	code := func(at time.Time) string {
+		c, err := totp.Code(secret, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	s := NewTwoFactorService(&totpUserRepo{}, nil, nil, nil, fixedClock{now: now}, "blog")
+
+	if err := s.Verify(context.Background(), user, code(now)); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := s.Verify(context.Background(), user, code(now)); !errors.Is(err, e.ErrTwoFactorCodeInvalid) {
+		t.Fatalf("replayed code: error = %v, want %v", err, e.ErrTwoFactorCodeInvalid)
+	}
+	// Код предыдущего шага ещё в допуске, но шаг уже позади использованного
+	if err := s.Verify(context.Background(), user, code(now.Add(-totp.Period))); !errors.Is(err, e.ErrTwoFactorCodeInvalid) {
+		t.Fatalf("code of an earlier step: error = %v, want %v", err, e.ErrTwoFactorCodeInvalid)
+	}
+	if err := s.Verify(context.Background(), user, code(now.Add(totp.Period))); err != nil {
+		t.Fatalf("code of the next step: %v", err)
+	}
+}
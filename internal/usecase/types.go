@@ -3,26 +3,71 @@ package usecase
 import (
 	"my_blog_backend/internal/domain"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Services struct {
-	UserService     *UserService
-	ArticleService  *ArticleService
-	CategoryService *CategoryService
+	UserService              *UserService
+	ArticleService           *ArticleService
+	CategoryService          *CategoryService
+	CommentService           *CommentService
+	PasswordResetService     *PasswordResetService
+	EmailVerificationService *EmailVerificationService
+	TwoFactorService         *TwoFactorService
+	AccessTokenService       *AccessTokenService
+	AuthorizationService     *AuthorizationService
+	AdminUserService         *AdminUserService
+	AccountService           *AccountService
+	FollowService            *FollowService
+	FeedService              *FeedService
 }
 
-func NewServices(u *UserService, a *ArticleService, c *CategoryService) *Services {
+func NewServices(u *UserService, a *ArticleService, c *CategoryService, cm *CommentService, pr *PasswordResetService, ev *EmailVerificationService, tf *TwoFactorService, at *AccessTokenService, az *AuthorizationService, au *AdminUserService, ac *AccountService, fl *FollowService, fd *FeedService) *Services {
 	return &Services{
-		UserService:     u,
-		ArticleService:  a,
-		CategoryService: c,
+		UserService:              u,
+		ArticleService:           a,
+		CategoryService:          c,
+		CommentService:           cm,
+		PasswordResetService:     pr,
+		EmailVerificationService: ev,
+		TwoFactorService:         tf,
+		AccessTokenService:       at,
+		AuthorizationService:     az,
+		AdminUserService:         au,
+		AccountService:           ac,
+		FollowService:            fl,
+		FeedService:              fd,
 	}
 }
 
 type AuthenticatedUser struct {
-	ID    uint
-	Role  domain.Role
-	Email string
+	ID        uint
+	SessionID uuid.UUID
+	Role      domain.Role
+	Email     string
+	// Scopes задан только при входе по персональному токену, JWT даёт полный доступ
+	Scopes []domain.Scope
+}
+
+// ClientInfo описывает устройство, с которого пришёл запрос
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// JSONWebKey - публичный ключ подписи JWT в формате RFC 7517
+type JSONWebKey struct {
+	KeyType   string
+	KeyID     string
+	Use       string
+	Algorithm string
+	// RSA
+	N string
+	E string
+	// Ed25519
+	Curve string
+	X     string
 }
 
 type TokenResponse struct {
@@ -39,13 +84,75 @@ type CreateUserReq struct {
 type LoginUserReq struct {
 	Email    string
 	Password string
+	Client   ClientInfo
 }
 
 type UserRes struct {
+	Id               uint
+	Username         string
+	Email            string
+	EmailVerified    bool
+	TwoFactorEnabled bool
+	Role             domain.Role
+	// Задан, если пользователь запросил удаление аккаунта
+	DeletionScheduledAt *time.Time
+	Profile             domain.Profile
+}
+
+// PublicProfileRes - то, что видно о пользователе любому посетителю, без email и служебных полей
+type PublicProfileRes struct {
 	Id       uint
 	Username string
-	Email    string
-	Role     domain.Role
+	Profile  domain.Profile
+	JoinedAt time.Time
+	Stats    AuthorStatsRes
+}
+
+type AuthorStatsRes struct {
+	ArticleCount     int
+	FirstPublishedAt *time.Time
+	TopCategory      *CategoryRes
+}
+
+type ChangeRoleReq struct {
+	ActorId uint
+	UserId  uint
+	Role    string
+}
+
+type RoleRes struct {
+	Role        domain.Role
+	Permissions []domain.Permission
+}
+
+type AdminUserRes struct {
+	User        UserRes
+	CreatedAt   time.Time
+	SuspendedAt *time.Time
+}
+
+type ListUsersReq struct {
+	ActorId        uint
+	Role           string
+	UsernamePrefix string
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	Suspended      *bool
+	Limit          int
+	Cursor         string
+}
+
+type GetUsersRes struct {
+	Users      []*AdminUserRes
+	NextCursor string
+}
+
+type DeleteUserReq struct {
+	ActorId uint
+	UserId  uint
+	// Пусто, DeleteUserArticlesReassign или DeleteUserArticlesPurge
+	Articles   string
+	ReassignTo *uint
 }
 
 type LoginUserRes struct {
@@ -55,6 +162,34 @@ type LoginUserRes struct {
 	AccessTokenExpiresAt  time.Time
 	RefreshTokenExpiresAt time.Time
 	User                  UserRes
+	// Если у пользователя включена 2FA, вместо сессии возвращается только challenge
+	TwoFactorChallenge *TokenResponse
+}
+
+type VerifyTwoFactorReq struct {
+	ChallengeToken string
+	Code           string
+	Client         ClientInfo
+}
+
+type TwoFactorEnrollmentRes struct {
+	Secret string
+	URI    string
+}
+
+type RecoveryCodesRes struct {
+	Codes []string
+}
+
+type DisableTwoFactorReq struct {
+	Password string
+	Code     string
+}
+
+type DeleteAccountReq struct {
+	Password string
+	// domain.ArticlesAnonymize или domain.ArticlesDelete
+	Articles string
 }
 
 type ChangePasswordReq struct {
@@ -63,13 +198,13 @@ type ChangePasswordReq struct {
 }
 
 type CreateCategoryReq struct {
-	UserRole     domain.Role
+	UserId       uint
 	CategoryName string
 	CategorySlug string
 }
 
 type UpdateCategoryReq struct {
-	UserRole        domain.Role
+	UserId          uint
 	CategorySlug    string
 	NewCategoryName *string
 	NewCategorySlug *string
@@ -81,7 +216,7 @@ type UpdateCategoryRes struct {
 }
 
 type DeleteCategoryReq struct {
-	UserRole     domain.Role
+	UserId       uint
 	CategorySlug string
 }
 
@@ -92,15 +227,67 @@ type GetAllCategoriesRes struct {
 }
 
 type ArticleRes struct {
-	ArticleId uint
-	Title     string
-	Content   string
-	Author    UserRes
-	Category  CategoryRes
+	ArticleId   uint
+	Title       string
+	Slug        string
+	Content     string
+	ContentHTML string
+	Status      domain.ArticleStatus
+	PublishedAt *time.Time
+	PublishAt   *time.Time
+	Author      UserRes
+	Category    CategoryRes
+	Tags        []TagRes
+}
+
+type TagRes struct {
+	Name string
+	Slug string
 }
 
 type GetArticles struct {
-	Articles []*ArticleRes
+	Articles   []*ArticleRes
+	NextCursor string
+}
+
+type ListFollowsReq struct {
+	Username string
+	Limit    int
+	Cursor   string
+}
+
+type FollowRes struct {
+	User       UserRes
+	FollowedAt time.Time
+}
+
+type GetFollowsRes struct {
+	Follows    []*FollowRes
+	NextCursor string
+}
+
+type ListArticlesReq struct {
+	Limit  int
+	Cursor string
+}
+
+type SearchArticlesReq struct {
+	Query          string
+	CategorySlug   string
+	AuthorUsername string
+	Limit          int
+	Cursor         string
+}
+
+type ArticleSearchRes struct {
+	Article ArticleRes
+	Rank    float32
+	Snippet string
+}
+
+type SearchArticlesRes struct {
+	Results    []*ArticleSearchRes
+	NextCursor string
 }
 
 type CreateArticleReq struct {
@@ -108,19 +295,33 @@ type CreateArticleReq struct {
 	Title        string
 	Content      string
 	CategorySlug string
+	Tags         []string
+	Publish      bool
 }
 
 type CreateArticleRes struct {
 	ArticleId    uint
 	Title        string
+	Slug         string
 	Content      string
+	ContentHTML  string
+	Status       domain.ArticleStatus
+	PublishedAt  *time.Time
 	CategoryName string
 	CategorySlug string
+	Tags         []TagRes
 }
 
 type UpdateUserReq struct {
 	Username *string
 	Email    *string
+	// Поля профиля, nil - не менять, пустая строка - очистить
+	DisplayName *string
+	Bio         *string
+	AvatarURL   *string
+	Website     *string
+	Location    *string
+	Links       *[]domain.ProfileLink
 }
 
 type UpdateArticleReq struct {
@@ -129,15 +330,23 @@ type UpdateArticleReq struct {
 	Title        *string
 	Content      *string
 	CategorySlug *string
+	Tags         *[]string
+	PublishAt    *time.Time
+	Unschedule   bool
 }
 
 type UpdateArticleRes struct {
-	AuthorID  uint
-	ArticleId uint
-	Title     string
-	Content   string
-	Category  CategoryRes
-	UpdatedAt time.Time
+	AuthorID    uint
+	ArticleId   uint
+	Title       string
+	Slug        string
+	Content     string
+	ContentHTML string
+	Status      domain.ArticleStatus
+	PublishAt   *time.Time
+	Category    CategoryRes
+	Tags        []TagRes
+	UpdatedAt   time.Time
 }
 
 type CategoryRes struct {
@@ -150,3 +359,145 @@ type DeleteArticleReq struct {
 	UserId    uint
 	ArticleId uint
 }
+
+type ChangeArticleStatusReq struct {
+	UserId    uint
+	ArticleId uint
+}
+
+type CreateCommentReq struct {
+	UserId    uint
+	ArticleId uint
+	ParentId  *uint
+	Content   string
+}
+
+type ListCommentsReq struct {
+	ViewerId  uint
+	ArticleId uint
+	ParentId  *uint
+	Depth     int
+	Limit     int
+	Cursor    string
+}
+
+type UpdateCommentReq struct {
+	UserId    uint
+	ArticleId uint
+	CommentId uint
+	Content   string
+}
+
+type DeleteCommentReq struct {
+	UserId    uint
+	ArticleId uint
+	CommentId uint
+}
+
+type CommentAuthorRes struct {
+	Id       uint
+	Username string
+}
+
+type CommentRes struct {
+	Id             uint
+	ArticleId      uint
+	ParentId       *uint
+	Author         *CommentAuthorRes
+	Content        string
+	IsDeleted      bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Replies        []*CommentRes
+	HasMoreReplies bool
+}
+
+type GetCommentsRes struct {
+	Comments   []*CommentRes
+	NextCursor string
+}
+
+type ListRevisionsReq struct {
+	UserId    uint
+	ArticleId uint
+}
+
+type GetRevisionReq struct {
+	UserId    uint
+	ArticleId uint
+	Number    int
+}
+
+type DiffRevisionsReq struct {
+	UserId    uint
+	ArticleId uint
+	From      int
+	To        int
+}
+
+type RestoreRevisionReq struct {
+	UserId    uint
+	ArticleId uint
+	Number    int
+}
+
+type RevisionRes struct {
+	Number     int
+	Title      string
+	Content    string
+	CategoryId uint
+	Editor     *CommentAuthorRes
+	CreatedAt  time.Time
+}
+
+type RevisionDiffRes struct {
+	From int
+	To   int
+	Diff string
+}
+
+type SessionRes struct {
+	Id         uuid.UUID
+	UserAgent  string
+	IP         string
+	IsCurrent  bool
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+}
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type ResetPasswordReq struct {
+	Token       string
+	NewPassword string
+}
+
+type CreateAccessTokenReq struct {
+	Name   string
+	Scopes []string
+	// nil - срок по умолчанию
+	ExpiresIn *time.Duration
+}
+
+type UpdateAccessTokenReq struct {
+	Name string
+}
+
+type AccessTokenRes struct {
+	Id         uint
+	Name       string
+	Scopes     []domain.Scope
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+}
+
+type CreatedAccessTokenRes struct {
+	Token       string
+	AccessToken AccessTokenRes
+}
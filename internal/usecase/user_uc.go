@@ -3,10 +3,13 @@ package usecase
 import (
 	"context"
 	"errors"
+	"log"
 	"my_blog_backend/internal/domain"
 	"my_blog_backend/internal/repository"
 	"my_blog_backend/pkg/e"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 const (
@@ -14,9 +17,7 @@ const (
 )
 
 // TODO: вынести повторяющийся код в функции
-// вынести логику создания сессий в LoginUser и RefreshSession в отдельный метод
 // сделать одну функцию для ошибок, заменить handleUserError
-// создание сессии повторяющийся код
 
 type UserService struct {
 	userRepo     repository.UserRepository
@@ -24,15 +25,23 @@ type UserService struct {
 	sessionRepo  repository.SessionRepository
 	tokenManager TokenManager
 	hashManager  HashManager
+	verification *EmailVerificationService
+	twoFactor    *TwoFactorService
+	throttle     *LoginThrottle
+	passwords    *PasswordValidator
 }
 
-func NewUserService(u repository.UserRepository, a repository.ArticleRepository, s repository.SessionRepository, tm TokenManager, hm HashManager) *UserService {
+func NewUserService(u repository.UserRepository, a repository.ArticleRepository, s repository.SessionRepository, tm TokenManager, hm HashManager, ev *EmailVerificationService, tf *TwoFactorService, lt *LoginThrottle, pv *PasswordValidator) *UserService {
 	return &UserService{
 		userRepo:     u,
 		articleRepo:  a,
 		sessionRepo:  s,
 		tokenManager: tm,
 		hashManager:  hm,
+		verification: ev,
+		twoFactor:    tf,
+		throttle:     lt,
+		passwords:    pv,
 	}
 }
 
@@ -44,6 +53,10 @@ func (s *UserService) CreateUser(ctx context.Context, userDto *CreateUserReq) (*
 		return nil, e.Wrap(op, err)
 	}
 
+	if err := s.passwords.Validate(userDto.Password, userDto.Username, userDto.Email); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
 	hash, err := s.hashManager.HashPassword(userDto.Password)
 	if err != nil {
 		return nil, e.Wrap(op, err)
@@ -60,12 +73,22 @@ func (s *UserService) CreateUser(ctx context.Context, userDto *CreateUserReq) (*
 		return nil, e.Wrap(op, err)
 	}
 
+	// Аккаунт уже создан, письмо можно запросить повторно
+	if err := s.verification.SendVerification(ctx, userEntity); err != nil {
+		log.Printf("%s: %v", op, err)
+	}
+
 	return toUserResponse(userEntity), nil
 }
 
 func (s *UserService) LoginUser(ctx context.Context, userDto *LoginUserReq) (*LoginUserRes, error) {
 	const op = "UserService.LoginUser"
 
+	// Попытка сразу учитывается как неудачная и снимается, только если пароль верный
+	if err := s.throttle.Acquire(ctx, userDto.Email, userDto.Client.IP); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
 	user, err := s.userRepo.GetByEmail(ctx, userDto.Email)
 	if err != nil {
 		if errors.Is(err, e.ErrUserNotFound) {
@@ -83,21 +106,76 @@ func (s *UserService) LoginUser(ctx context.Context, userDto *LoginUserReq) (*Lo
 		return nil, e.Wrap(op, err)
 	}
 
-	jwtStruct, refreshToken, refreshTokenHash, err := s.generateTokens(user.ID, user.Email, user.Role)
+	s.rehashPassword(ctx, user, userDto.Password)
+
+	// Пароль верный, но сессия будет выдана только после проверки второго фактора.
+	// Снимаем только эту попытку: сброс счётчика давал бы со знанием пароля бесконечный перебор кодов
+	if user.IsTwoFactorEnabled() {
+		if err := s.throttle.Release(ctx, userDto.Email, userDto.Client.IP); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		challenge, err := s.tokenManager.NewChallengeToken(user.ID)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		return &LoginUserRes{TwoFactorChallenge: challenge}, nil
+	}
+
+	if err := s.throttle.Succeeded(ctx, userDto.Email, userDto.Client.IP); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	res, err := s.startSession(ctx, user, userDto.Client, nil)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	session, err := s.sessionRepo.Create(ctx, domain.NewSession(
-		user.ID,
-		refreshTokenHash,
-		time.Now().UTC().Add(refreshTokenTTL)),
-	)
+	return res, nil
+}
+
+// VerifyTwoFactor завершает вход с 2FA: по challenge токену из LoginUser и коду выдаёт сессию
+func (s *UserService) VerifyTwoFactor(ctx context.Context, req *VerifyTwoFactorReq) (*LoginUserRes, error) {
+	const op = "UserService.VerifyTwoFactor"
+
+	userId, err := s.tokenManager.VerifyChallengeToken(req.ChallengeToken)
 	if err != nil {
+		return nil, e.Wrap(op, e.ErrTwoFactorChallengeInvalid)
+	}
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, e.ErrUserNotFound) {
+			return nil, e.Wrap(op, e.ErrTwoFactorChallengeInvalid)
+		}
+
 		return nil, e.Wrap(op, err)
 	}
 
-	return toLoginUserResponse(user, session, jwtStruct, refreshToken), nil
+	// 2FA успели отключить, пока пользователь вводил код
+	if !user.IsTwoFactorEnabled() {
+		return nil, e.Wrap(op, e.ErrTwoFactorChallengeInvalid)
+	}
+
+	if err := s.throttle.Acquire(ctx, user.Email, req.Client.IP); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.twoFactor.Verify(ctx, user, req.Code); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if err := s.throttle.Succeeded(ctx, user.Email, req.Client.IP); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	res, err := s.startSession(ctx, user, req.Client, nil)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return res, nil
 }
 
 func (s *UserService) GetUserById(ctx context.Context, id uint) (*UserRes, error) {
@@ -122,6 +200,23 @@ func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*
 	return toUserResponse(user), nil
 }
 
+// GetProfile возвращает публичный профиль вместе со статистикой по опубликованным статьям
+func (s *UserService) GetProfile(ctx context.Context, username string) (*PublicProfileRes, error) {
+	const op = "UserService.GetProfile"
+
+	user, err := s.getUser(ctx, UserFilter{Username: &username})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	stats, err := s.articleRepo.AuthorStats(ctx, user.ID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return toPublicProfileRes(user, stats), nil
+}
+
 func (s *UserService) UpdateUser(ctx context.Context, userId uint, req *UpdateUserReq) (*UserRes, error) {
 	const op = "UserService.UpdateUser"
 
@@ -130,7 +225,7 @@ func (s *UserService) UpdateUser(ctx context.Context, userId uint, req *UpdateUs
 		return nil, e.Wrap(op, err)
 	}
 
-	if req.Username == nil && req.Email == nil {
+	if req.Username == nil && req.Email == nil && !req.changesProfile() {
 		return nil, e.Wrap(op, e.ErrNoDataToUpdate)
 	}
 
@@ -147,7 +242,15 @@ func (s *UserService) UpdateUser(ctx context.Context, userId uint, req *UpdateUs
 			return nil, e.Wrap(op, e.ErrEmailIsSame)
 		}
 
-		user.Email = *req.Email
+		if err := s.userRepo.ExistsByEmailOrUsername(ctx, *req.Email, ""); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	if req.changesProfile() {
+		if err := user.ChangeProfile(req.applyToProfile(user.Profile)); err != nil {
+			return nil, e.Wrap(op, err)
+		}
 	}
 
 	if err := user.Validate(); err != nil {
@@ -159,9 +262,45 @@ func (s *UserService) UpdateUser(ctx context.Context, userId uint, req *UpdateUs
 		return nil, e.Wrap(op, err)
 	}
 
+	// Новый email начнёт действовать только после перехода по ссылке из письма
+	if req.Email != nil {
+		if err := s.verification.SendVerification(ctx, updateUser); err != nil {
+			log.Printf("%s: %v", op, err)
+		}
+	}
+
 	return toUserResponse(updateUser), nil
 }
 
+func (r *UpdateUserReq) changesProfile() bool {
+	return r.DisplayName != nil || r.Bio != nil || r.AvatarURL != nil ||
+		r.Website != nil || r.Location != nil || r.Links != nil
+}
+
+// applyToProfile возвращает копию профиля с изменёнными полями
+func (r *UpdateUserReq) applyToProfile(profile domain.Profile) domain.Profile {
+	if r.DisplayName != nil {
+		profile.DisplayName = *r.DisplayName
+	}
+	if r.Bio != nil {
+		profile.Bio = *r.Bio
+	}
+	if r.AvatarURL != nil {
+		profile.AvatarURL = *r.AvatarURL
+	}
+	if r.Website != nil {
+		profile.Website = *r.Website
+	}
+	if r.Location != nil {
+		profile.Location = *r.Location
+	}
+	if r.Links != nil {
+		profile.Links = *r.Links
+	}
+
+	return profile
+}
+
 func (s *UserService) ChangePassword(ctx context.Context, userId uint, changePassword *ChangePasswordReq) error {
 	const op = "UserService.ChangePassword"
 
@@ -182,28 +321,36 @@ func (s *UserService) ChangePassword(ctx context.Context, userId uint, changePas
 		return e.Wrap(op, e.ErrPasswordIsSame)
 	}
 
+	if err := s.passwords.Validate(changePassword.NewPassword, user.Username, user.Email); err != nil {
+		return e.Wrap(op, err)
+	}
+
 	newPassHash, err := s.hashManager.HashPassword(changePassword.NewPassword)
 	if err != nil {
 		return e.Wrap(op, err)
 	}
 
-	user.PasswordHash = newPassHash
+	if err := s.userRepo.SetPasswordHash(ctx, user.ID, newPassHash); err != nil {
+		return e.Wrap(op, err)
+	}
 
-	if _, err := s.userRepo.Update(ctx, user); err != nil {
+	// Старый пароль мог утечь вместе с refresh токенами, поэтому завершаем все сессии
+	if _, err := s.sessionRepo.RevokeAllByUser(ctx, user.ID, uuid.Nil); err != nil {
 		return e.Wrap(op, err)
 	}
 
 	return nil
 }
 
-func (s *UserService) RefreshSession(ctx context.Context, userRefreshToken string) (*LoginUserRes, error) {
+func (s *UserService) RefreshSession(ctx context.Context, userRefreshToken string, client ClientInfo) (*LoginUserRes, error) {
 	const op = "UserService.RefreshSession"
 
 	oldSession, err := s.verifyRefreshToken(ctx, userRefreshToken)
 	if err != nil {
 		switch {
+		case errors.Is(err, e.ErrSessionRevoked):
+			return nil, e.Wrap(op, s.rejectRevokedSession(ctx, oldSession))
 		case errors.Is(err, e.ErrRefreshTokenInvalid),
-			errors.Is(err, e.ErrSessionRevoked),
 			errors.Is(err, e.ErrSessionExpired):
 			return nil, e.Wrap(op, e.ErrUnauthorized)
 		default:
@@ -211,30 +358,31 @@ func (s *UserService) RefreshSession(ctx context.Context, userRefreshToken strin
 		}
 	}
 
-	if err := s.sessionRepo.RevokeSession(ctx, oldSession.Id); err != nil {
-		return nil, e.Wrap(op, err)
-	}
+	if err := s.sessionRepo.RevokeSession(ctx, oldSession.Id, domain.SessionRotated); err != nil {
+		// Сессию успели отозвать параллельным запросом: ротацией с тем же токеном или выходом
+		if errors.Is(err, e.ErrSessionNotFound) {
+			revoked, err := s.sessionRepo.GetByID(ctx, oldSession.Id)
+			if err != nil {
+				return nil, e.Wrap(op, err)
+			}
+
+			return nil, e.Wrap(op, s.rejectRevokedSession(ctx, revoked))
+		}
 
-	user, err := s.userRepo.GetById(ctx, oldSession.UserId)
-	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	jwtStruct, refreshToken, refreshTokenHash, err := s.generateTokens(user.ID, user.Email, user.Role)
+	user, err := s.userRepo.GetById(ctx, oldSession.UserId)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	newSession, err := s.sessionRepo.Create(ctx, domain.NewSession(
-		user.ID,
-		refreshTokenHash,
-		time.Now().UTC().Add(refreshTokenTTL)),
-	)
+	res, err := s.startSession(ctx, user, client, oldSession)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	return toLoginUserResponse(user, newSession, jwtStruct, refreshToken), nil
+	return res, nil
 }
 
 func (s *UserService) LogoutUser(ctx context.Context, userRefreshToken string) error {
@@ -252,32 +400,92 @@ func (s *UserService) LogoutUser(ctx context.Context, userRefreshToken string) e
 		}
 	}
 
-	if err := s.sessionRepo.RevokeSession(ctx, session.Id); err != nil {
+	if err := s.sessionRepo.RevokeSession(ctx, session.Id, domain.SessionLoggedOut); err != nil {
 		return e.Wrap(op, err)
 	}
 
 	return nil
 }
 
-func (s *UserService) SetAdminRole(ctx context.Context, userId uint) error {
-	const op = "UserService.SetAdminRole"
+func (s *UserService) ListSessions(ctx context.Context, userId uint, currentSessionId uuid.UUID) ([]*SessionRes, error) {
+	const op = "UserService.ListSessions"
 
-	user, err := s.userRepo.GetById(ctx, userId)
+	sessions, err := s.sessionRepo.ListActiveByUser(ctx, userId, time.Now().UTC())
 	if err != nil {
-		return e.Wrap(op, err)
+		return nil, e.Wrap(op, err)
 	}
 
-	if err := user.SetAdminRole(); err != nil {
-		return e.Wrap(op, e.ErrUserAlreadyAdmin)
+	res := make([]*SessionRes, len(sessions))
+	for i, session := range sessions {
+		res[i] = toSessionRes(&session, currentSessionId)
 	}
 
-	if _, err := s.userRepo.Update(ctx, user); err != nil {
+	return res, nil
+}
+
+func (s *UserService) RevokeSession(ctx context.Context, userId uint, sessionId uuid.UUID) error {
+	const op = "UserService.RevokeSession"
+
+	if err := s.sessionRepo.RevokeUserSession(ctx, userId, sessionId); err != nil {
 		return e.Wrap(op, err)
 	}
 
 	return nil
 }
 
+// RevokeOtherSessions завершает все сессии пользователя, кроме текущей
+func (s *UserService) RevokeOtherSessions(ctx context.Context, userId uint, currentSessionId uuid.UUID) (int64, error) {
+	const op = "UserService.RevokeOtherSessions"
+
+	// Без sid в токене нельзя понять, какую сессию оставить
+	if currentSessionId == uuid.Nil {
+		return 0, e.Wrap(op, e.ErrUnauthorized)
+	}
+
+	revoked, err := s.sessionRepo.RevokeAllByUser(ctx, userId, currentSessionId)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return revoked, nil
+}
+
+// AuthenticateJWT проверяет access токен и сверяет его с текущим состоянием пользователя:
+// после блокировки или удаления аккаунта уже выданный токен перестаёт действовать
+func (s *UserService) AuthenticateJWT(ctx context.Context, token string) (*AuthenticatedUser, error) {
+	const op = "UserService.AuthenticateJWT"
+
+	authenticatedUser, err := s.tokenManager.VerifyJWT(token)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	user, err := s.userRepo.GetById(ctx, authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, e.ErrUserNotFound) {
+			return nil, e.Wrap(op, e.ErrTokenInvalid)
+		}
+
+		return nil, e.Wrap(op, err)
+	}
+
+	if user.IsSuspended() {
+		return nil, e.Wrap(op, e.ErrTokenInvalid)
+	}
+
+	// Роль берём из базы: с момента выпуска токена её могли изменить
+	authenticatedUser.Role = user.Role
+
+	return authenticatedUser, nil
+}
+
+// PublicKeys возвращает ключи, по которым другие сервисы проверяют наши access токены
+func (s *UserService) PublicKeys() []JSONWebKey {
+	return s.tokenManager.PublicKeys()
+}
+
+// verifyRefreshToken при ErrSessionRevoked возвращает и саму сессию,
+// чтобы вызывающий мог отозвать её семейство
 func (s *UserService) verifyRefreshToken(ctx context.Context, refreshToken string) (*domain.Session, error) {
 	tokenHash := s.tokenManager.HashRefreshToken(refreshToken)
 	session, err := s.sessionRepo.GetByRefreshTokenHash(ctx, tokenHash)
@@ -290,24 +498,94 @@ func (s *UserService) verifyRefreshToken(ctx context.Context, refreshToken strin
 	}
 
 	if err := session.ValidateState(); err != nil {
+		if errors.Is(err, e.ErrSessionRevoked) {
+			return session, err
+		}
+
 		return nil, err
 	}
 
 	return session, nil
 }
 
-func (s *UserService) generateTokens(userId uint, email string, role domain.Role) (*TokenResponse, string, string, error) {
-	jwtStruct, err := s.tokenManager.NewJWT(userId, email, role)
+// rehashPassword переводит хэш на текущий алгоритм, пока открытый пароль известен.
+// Ошибка не мешает входу: попробуем при следующем
+func (s *UserService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	if !s.hashManager.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	newHash, err := s.hashManager.HashPassword(password)
 	if err != nil {
-		return nil, "", "", err
+		log.Printf("rehash password for user %d: %v", user.ID, err)
+		return
+	}
+
+	if err := s.userRepo.ReplacePasswordHash(ctx, user.ID, user.PasswordHash, newHash); err != nil {
+		log.Printf("rehash password for user %d: %v", user.ID, err)
+		return
+	}
+
+	user.PasswordHash = newHash
+}
+
+// rejectRevokedSession отвечает на refresh токен отозванной сессии. Семейство
+// отзывается, только если токен уже был ротирован
+func (s *UserService) rejectRevokedSession(ctx context.Context, session *domain.Session) error {
+	if !session.WasRotated() {
+		return e.ErrRefreshTokenInvalid
+	}
+
+	if err := s.revokeReusedFamily(ctx, session); err != nil {
+		return err
+	}
+
+	return e.ErrRefreshTokenReused
+}
+
+func (s *UserService) revokeReusedFamily(ctx context.Context, session *domain.Session) error {
+	if err := s.sessionRepo.RevokeFamily(ctx, session.FamilyId); err != nil {
+		return err
+	}
+
+	log.Printf("refresh token reuse: user %d, session family %s revoked", session.UserId, session.FamilyId)
+	return nil
+}
+
+// startSession создаёт сессию с новым refresh токеном и выпускает JWT, привязанный к ней.
+// prev - ротированная сессия, семейство которой продолжает новая, или nil при входе
+func (s *UserService) startSession(ctx context.Context, user *domain.User, client ClientInfo, prev *domain.Session) (*LoginUserRes, error) {
+	if user.IsSuspended() {
+		return nil, e.ErrUserSuspended
 	}
 
 	refreshToken, refreshTokenHash, err := s.tokenManager.NewRefreshToken()
 	if err != nil {
-		return nil, "", "", err
+		return nil, err
+	}
+
+	session := domain.NewSession(
+		user.ID,
+		refreshTokenHash,
+		time.Now().UTC().Add(refreshTokenTTL),
+		client.UserAgent,
+		client.IP,
+	)
+	if prev != nil {
+		session.ContinueFamily(prev)
+	}
+
+	jwtStruct, err := s.tokenManager.NewJWT(user.ID, session.Id, user.Email, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err = s.sessionRepo.Create(ctx, session)
+	if err != nil {
+		return nil, err
 	}
 
-	return jwtStruct, refreshToken, refreshTokenHash, nil
+	return toLoginUserResponse(user, session, jwtStruct, refreshToken), nil
 }
 
 type UserFilter struct {
@@ -345,10 +623,49 @@ func handleUserError(user *domain.User, err error) (*domain.User, error) {
 
 func toUserResponse(user *domain.User) *UserRes {
 	return &UserRes{
+		Id:                  user.ID,
+		Username:            user.Username,
+		Email:               user.Email,
+		EmailVerified:       user.IsEmailVerified(),
+		TwoFactorEnabled:    user.IsTwoFactorEnabled(),
+		Role:                user.Role,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		Profile:             user.Profile,
+	}
+}
+
+func toPublicProfileRes(user *domain.User, stats *domain.AuthorStats) *PublicProfileRes {
+	res := &PublicProfileRes{
 		Id:       user.ID,
 		Username: user.Username,
-		Email:    user.Email,
-		Role:     user.Role,
+		Profile:  user.Profile,
+		JoinedAt: user.CreatedAt,
+		Stats: AuthorStatsRes{
+			ArticleCount:     stats.ArticleCount,
+			FirstPublishedAt: stats.FirstPublishedAt,
+		},
+	}
+
+	if stats.TopCategory != nil {
+		res.Stats.TopCategory = &CategoryRes{
+			CategoryName: stats.TopCategory.Name,
+			CategorySlug: stats.TopCategory.Slug,
+			CategoryId:   stats.TopCategory.ID,
+		}
+	}
+
+	return res
+}
+
+func toSessionRes(session *domain.Session, currentSessionId uuid.UUID) *SessionRes {
+	return &SessionRes{
+		Id:         session.Id,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		IsCurrent:  session.Id == currentSessionId,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
 	}
 }
 
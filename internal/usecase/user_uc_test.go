@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/internal/repository"
+	"my_blog_backend/pkg/e"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jwtTokenManager принимает любой токен за access токен пользователя 1 с ролью user
+type jwtTokenManager struct {
+	TokenManager
+}
+
+func (jwtTokenManager) VerifyJWT(token string) (*AuthenticatedUser, error) {
+	if token != "valid" {
+		return nil, e.ErrTokenInvalid
+	}
+
+	return &AuthenticatedUser{ID: 1, Role: domain.RoleUser}, nil
+}
+
+type authUserRepo struct {
+	repository.UserRepository
+
+	user *domain.User
+}
+
+func (r *authUserRepo) GetById(_ context.Context, id uint) (*domain.User, error) {
+	if r.user == nil || r.user.ID != id {
+		return nil, e.ErrUserNotFound
+	}
+
+	return r.user, nil
+}
+
+func TestUserService_AuthenticateJWT(t *testing.T) {
+	suspendedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		token    string
+		user     *domain.User
+		wantErr  error
+		wantRole domain.Role
+	}{
+		{
+			name:     "active user",
+			token:    "valid",
+			user:     &domain.User{ID: 1, Role: domain.RoleUser},
+			wantRole: domain.RoleUser,
+		},
+		{
+			name:     "role is taken from the database",
+			token:    "valid",
+			user:     &domain.User{ID: 1, Role: domain.RoleAdmin},
+			wantRole: domain.RoleAdmin,
+		},
+		{
+			name:    "suspended user",
+			token:   "valid",
+			user:    &domain.User{ID: 1, Role: domain.RoleUser, SuspendedAt: &suspendedAt},
+			wantErr: e.ErrTokenInvalid,
+		},
+		{
+			name:    "deleted user",
+			token:   "valid",
+			wantErr: e.ErrTokenInvalid,
+		},
+		{
+			name:    "invalid token",
+			token:   "forged",
+			user:    &domain.User{ID: 1, Role: domain.RoleUser},
+			wantErr: e.ErrTokenInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &UserService{
+				userRepo:     &authUserRepo{user: tt.user},
+				tokenManager: jwtTokenManager{},
+			}
+
+			got, err := s.AuthenticateJWT(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("AuthenticateJWT() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("AuthenticateJWT() error = %v", err)
+			}
+			if got.Role != tt.wantRole {
+				t.Errorf("Role = %s, want %s", got.Role, tt.wantRole)
+			}
+		})
+	}
+}
+
+// refreshTokenManager выдаёт refresh токены по счётчику, хэш токена - сам токен
+type refreshTokenManager struct {
+	TokenManager
+
+	issued int
+}
+
+func (m *refreshTokenManager) NewRefreshToken() (string, string, error) {
+	m.issued++
+	token := fmt.Sprintf("refresh-%d", m.issued)
+	return token, token, nil
+}
+
+func (m *refreshTokenManager) HashRefreshToken(token string) string {
+	return token
+}
+
+func (m *refreshTokenManager) NewJWT(uint, uuid.UUID, string, domain.Role) (*TokenResponse, error) {
+	return &TokenResponse{Token: "jwt"}, nil
+}
+
+// refreshSessionRepo повторяет условные UPDATE из postgres
+type refreshSessionRepo struct {
+	repository.SessionRepository
+
+	sessions map[uuid.UUID]*domain.Session
+}
+
+func (r *refreshSessionRepo) Create(_ context.Context, session *domain.Session) (*domain.Session, error) {
+	r.sessions[session.Id] = session
+	return session, nil
+}
+
+func (r *refreshSessionRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, e.ErrSessionNotFound
+	}
+
+	copied := *session
+	return &copied, nil
+}
+
+func (r *refreshSessionRepo) GetByRefreshTokenHash(_ context.Context, hash string) (*domain.Session, error) {
+	for _, session := range r.sessions {
+		if session.RefreshTokenHash == hash {
+			copied := *session
+			return &copied, nil
+		}
+	}
+
+	return nil, e.ErrSessionNotFound
+}
+
+func (r *refreshSessionRepo) RevokeSession(_ context.Context, id uuid.UUID, reason domain.SessionRevokeReason) error {
+	session, ok := r.sessions[id]
+	if !ok || session.IsRevoked {
+		return e.ErrSessionNotFound
+	}
+
+	session.IsRevoked = true
+	session.RevokeReason = reason
+	return nil
+}
+
+func (r *refreshSessionRepo) RevokeFamily(_ context.Context, familyId uuid.UUID) error {
+	for _, session := range r.sessions {
+		if session.FamilyId == familyId && !session.IsRevoked {
+			session.IsRevoked = true
+			session.RevokeReason = domain.SessionFamilyRevoked
+		}
+	}
+
+	return nil
+}
+
+func TestUserService_RefreshSessionRevokedToken(t *testing.T) {
+	tests := []struct {
+		name string
+		// reason - почему отозвана предъявленная сессия, "" - она активна
+		reason           domain.SessionRevokeReason
+		wantErr          error
+		wantFamilyActive bool
+	}{
+		{name: "active session is rotated", wantFamilyActive: true},
+		{name: "rotated token is reused", reason: domain.SessionRotated, wantErr: e.ErrRefreshTokenReused},
+		{name: "token after logout", reason: domain.SessionLoggedOut, wantErr: e.ErrRefreshTokenInvalid, wantFamilyActive: true},
+		{name: "token of a revoked session", reason: domain.SessionRevoked, wantErr: e.ErrRefreshTokenInvalid, wantFamilyActive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiresAt := time.Now().UTC().Add(time.Hour)
+			presented := domain.NewSession(1, "presented", expiresAt, "", "")
+			presented.IsRevoked = tt.reason != ""
+			presented.RevokeReason = tt.reason
+			// Другое устройство в том же семействе
+			sibling := domain.NewSession(1, "sibling", expiresAt, "", "")
+			sibling.ContinueFamily(presented)
+
+			sessions := &refreshSessionRepo{sessions: map[uuid.UUID]*domain.Session{
+				presented.Id: presented,
+				sibling.Id:   sibling,
+			}}
+			s := &UserService{
+				userRepo:     &authUserRepo{user: &domain.User{ID: 1, Role: domain.RoleUser}},
+				sessionRepo:  sessions,
+				tokenManager: &refreshTokenManager{},
+			}
+
+			_, err := s.RefreshSession(context.Background(), "presented", ClientInfo{})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RefreshSession() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("RefreshSession() error = %v", err)
+			}
+
+			if active := !sessions.sessions[sibling.Id].IsRevoked; active != tt.wantFamilyActive {
+				t.Errorf("sibling session active = %v, want %v", active, tt.wantFamilyActive)
+			}
+			if tt.reason == "" && sessions.sessions[presented.Id].RevokeReason != domain.SessionRotated {
+				t.Errorf("RevokeReason = %q, want %q", sessions.sessions[presented.Id].RevokeReason, domain.SessionRotated)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"my_blog_backend/pkg/e"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2Prefix = "$argon2id$"
+
+type Argon2Params struct {
+	// Память в KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params - рекомендация RFC 9106 для систем с ограниченной памятью
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2HashManager хранит хэши в формате PHC:
+// $argon2id$v=19$m=65536,t=3,p=4$<соль>$<хэш>
+type Argon2HashManager struct {
+	params Argon2Params
+}
+
+func NewArgon2HashManager(params Argon2Params) (*Argon2HashManager, error) {
+	if params.Memory < 8*uint32(params.Parallelism) || params.Iterations < 1 || params.Parallelism < 1 {
+		return nil, fmt.Errorf("invalid argon2 params: m=%d, t=%d, p=%d", params.Memory, params.Iterations, params.Parallelism)
+	}
+
+	if params.SaltLength < 8 || params.KeyLength < 16 {
+		return nil, fmt.Errorf("invalid argon2 params: salt length %d, key length %d", params.SaltLength, params.KeyLength)
+	}
+
+	return &Argon2HashManager{params: params}, nil
+}
+
+func (manager *Argon2HashManager) HashPassword(password string) (string, error) {
+	const op = "Argon2HashManager.HashPassword"
+
+	salt := make([]byte, manager.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", e.Wrap(op, err)
+	}
+
+	p := manager.params
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (manager *Argon2HashManager) Compare(password string, hash string) error {
+	const op = "Argon2HashManager.Compare"
+
+	p, salt, key, err := parseArgon2Hash(hash)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return e.Wrap(op, e.ErrMismatchedHashAndPassword)
+	}
+
+	return nil
+}
+
+// NeedsRehash сообщает, что хэш получен с другими параметрами
+func (manager *Argon2HashManager) NeedsRehash(hash string) bool {
+	p, salt, _, err := parseArgon2Hash(hash)
+	if err != nil {
+		return true
+	}
+
+	current := manager.params
+	return p.Memory != current.Memory ||
+		p.Iterations != current.Iterations ||
+		p.Parallelism != current.Parallelism ||
+		p.KeyLength != current.KeyLength ||
+		uint32(len(salt)) != current.SaltLength
+}
+
+func parseArgon2Hash(hash string) (Argon2Params, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", соль, хэш
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2Params{}, nil, nil, e.ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, e.ErrUnknownHashFormat
+	}
+
+	var p Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return Argon2Params{}, nil, nil, e.ErrUnknownHashFormat
+	}
+
+	if p.Iterations < 1 || p.Parallelism < 1 {
+		return Argon2Params{}, nil, nil, e.ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return Argon2Params{}, nil, nil, e.ErrUnknownHashFormat
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Argon2Params{}, nil, nil, e.ErrUnknownHashFormat
+	}
+
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
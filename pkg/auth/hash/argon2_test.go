@@ -0,0 +1,143 @@
+package hash
+
+import (
+	"errors"
+	"fmt"
+	"my_blog_backend/pkg/e"
+	"strings"
+	"testing"
+)
+
+// Минимальные параметры, чтобы тесты не тратили 64 МиБ на каждый хэш
+var testArgon2Params = Argon2Params{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func newTestArgon2(t *testing.T, params Argon2Params) *Argon2HashManager {
+	t.Helper()
+
+	manager, err := NewArgon2HashManager(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return manager
+}
+
+func TestArgon2RoundTrip(t *testing.T) {
+	manager := newTestArgon2(t, testArgon2Params)
+
+	hash, err := manager.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("hash = %q, want PHC string with m=64,t=1,p=1", hash)
+	}
+
+	if err := manager.Compare("correct horse", hash); err != nil {
+		t.Errorf("Compare() with the right password error = %v", err)
+	}
+	if err := manager.Compare("wrong horse", hash); !errors.Is(err, e.ErrMismatchedHashAndPassword) {
+		t.Errorf("Compare() with a wrong password error = %v, want %v", err, e.ErrMismatchedHashAndPassword)
+	}
+
+	// Одинаковые пароли дают разные хэши из-за соли
+	other, err := manager.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == hash {
+		t.Error("two hashes of the same password are equal")
+	}
+
+	if manager.NeedsRehash(hash) {
+		t.Error("NeedsRehash() = true for a hash with current params")
+	}
+}
+
+func TestArgon2CompareMalformedHash(t *testing.T) {
+	manager := newTestArgon2(t, testArgon2Params)
+
+	valid, err := manager.HashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, "$")
+	salt, key := parts[4], parts[5]
+
+	hashes := map[string]string{
+		"empty":              "",
+		"too few parts":      "$argon2id$v=19$m=64,t=1,p=1$" + salt,
+		"argon2i":            fmt.Sprintf("$argon2i$v=19$m=64,t=1,p=1$%s$%s", salt, key),
+		"old version":        fmt.Sprintf("$argon2id$v=16$m=64,t=1,p=1$%s$%s", salt, key),
+		"missing params":     fmt.Sprintf("$argon2id$v=19$m=64$%s$%s", salt, key),
+		"zero iterations":    fmt.Sprintf("$argon2id$v=19$m=64,t=0,p=1$%s$%s", salt, key),
+		"zero parallelism":   fmt.Sprintf("$argon2id$v=19$m=64,t=1,p=0$%s$%s", salt, key),
+		"salt not base64":    fmt.Sprintf("$argon2id$v=19$m=64,t=1,p=1$%s$%s", "!!!", key),
+		"empty key":          fmt.Sprintf("$argon2id$v=19$m=64,t=1,p=1$%s$", salt),
+		"padded base64 salt": fmt.Sprintf("$argon2id$v=19$m=64,t=1,p=1$%s==$%s", salt, key),
+	}
+
+	for name, hash := range hashes {
+		t.Run(name, func(t *testing.T) {
+			if err := manager.Compare("password", hash); !errors.Is(err, e.ErrUnknownHashFormat) {
+				t.Errorf("Compare() error = %v, want %v", err, e.ErrUnknownHashFormat)
+			}
+			if !manager.NeedsRehash(hash) {
+				t.Error("NeedsRehash() = false for a malformed hash")
+			}
+		})
+	}
+}
+
+func TestNewArgon2HashManagerRejectsBadParams(t *testing.T) {
+	tests := map[string]func(*Argon2Params){
+		"zero iterations":         func(p *Argon2Params) { p.Iterations = 0 },
+		"zero parallelism":        func(p *Argon2Params) { p.Parallelism = 0 },
+		"memory below 8 per lane": func(p *Argon2Params) { p.Memory = 8*uint32(p.Parallelism) - 1 },
+		"short salt":              func(p *Argon2Params) { p.SaltLength = 7 },
+		"short key":               func(p *Argon2Params) { p.KeyLength = 15 },
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := testArgon2Params
+			modify(&params)
+			if _, err := NewArgon2HashManager(params); err == nil {
+				t.Errorf("NewArgon2HashManager(%+v) error = nil", params)
+			}
+		})
+	}
+}
+
+func TestArgon2NeedsRehashOnParamChange(t *testing.T) {
+	old := newTestArgon2(t, testArgon2Params)
+	hash, err := old.HashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]func(*Argon2Params){
+		"memory":      func(p *Argon2Params) { p.Memory *= 2 },
+		"iterations":  func(p *Argon2Params) { p.Iterations++ },
+		"parallelism": func(p *Argon2Params) { p.Parallelism++ },
+		"salt length": func(p *Argon2Params) { p.SaltLength++ },
+		"key length":  func(p *Argon2Params) { p.KeyLength++ },
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := testArgon2Params
+			modify(&params)
+			if !newTestArgon2(t, params).NeedsRehash(hash) {
+				t.Errorf("NeedsRehash() = false after %s change", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package hash
+
+import (
+	"my_blog_backend/pkg/e"
+	"strings"
+)
+
+// Dispatcher хэширует новые пароли через Argon2id и проверяет как argon2, так и
+// старые bcrypt хэши. Устаревшие хэши заменяются при входе пользователя
+type Dispatcher struct {
+	argon2 *Argon2HashManager
+	bcrypt *BcryptHashManager
+}
+
+func NewDispatcher(argon2 *Argon2HashManager, bcrypt *BcryptHashManager) *Dispatcher {
+	return &Dispatcher{
+		argon2: argon2,
+		bcrypt: bcrypt,
+	}
+}
+
+func (d *Dispatcher) HashPassword(password string) (string, error) {
+	return d.argon2.HashPassword(password)
+}
+
+func (d *Dispatcher) Compare(password string, hash string) error {
+	switch {
+	case strings.HasPrefix(hash, argon2Prefix):
+		return d.argon2.Compare(password, hash)
+	case isBcryptHash(hash):
+		return d.bcrypt.Compare(password, hash)
+	default:
+		return e.Wrap("Dispatcher.Compare", e.ErrUnknownHashFormat)
+	}
+}
+
+// NeedsRehash истинно для любого хэша не Argon2id и для argon2 с устаревшими параметрами
+func (d *Dispatcher) NeedsRehash(hash string) bool {
+	if !strings.HasPrefix(hash, argon2Prefix) {
+		return true
+	}
+
+	return d.argon2.NeedsRehash(hash)
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
@@ -0,0 +1,69 @@
+package hash
+
+import (
+	"errors"
+	"my_blog_backend/pkg/e"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestDispatcher(t *testing.T) (*Dispatcher, *BcryptHashManager) {
+	t.Helper()
+
+	bcryptManager, err := NewBcryptHashManager(bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewDispatcher(newTestArgon2(t, testArgon2Params), bcryptManager), bcryptManager
+}
+
+func TestDispatcherHashesWithArgon2(t *testing.T) {
+	dispatcher, _ := newTestDispatcher(t)
+
+	hash, err := dispatcher.HashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dispatcher.Compare("password", hash); err != nil {
+		t.Errorf("Compare() error = %v", err)
+	}
+	if dispatcher.NeedsRehash(hash) {
+		t.Error("NeedsRehash() = true for a fresh argon2 hash")
+	}
+}
+
+func TestDispatcherBcryptHashNeedsRehash(t *testing.T) {
+	dispatcher, bcryptManager := newTestDispatcher(t)
+
+	hash, err := bcryptManager.HashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Старый bcrypt хэш по-прежнему принимается, но подлежит замене
+	if err := dispatcher.Compare("password", hash); err != nil {
+		t.Errorf("Compare() error = %v", err)
+	}
+	if err := dispatcher.Compare("wrong", hash); !errors.Is(err, e.ErrMismatchedHashAndPassword) {
+		t.Errorf("Compare() with a wrong password error = %v, want %v", err, e.ErrMismatchedHashAndPassword)
+	}
+	if !dispatcher.NeedsRehash(hash) {
+		t.Error("NeedsRehash() = false for a bcrypt hash")
+	}
+}
+
+func TestDispatcherUnknownHash(t *testing.T) {
+	dispatcher, _ := newTestDispatcher(t)
+
+	for _, hash := range []string{"", "plain", "$1$md5crypt$hash", "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5"} {
+		if err := dispatcher.Compare("password", hash); !errors.Is(err, e.ErrUnknownHashFormat) {
+			t.Errorf("Compare(%q) error = %v, want %v", hash, err, e.ErrUnknownHashFormat)
+		}
+		if !dispatcher.NeedsRehash(hash) {
+			t.Errorf("NeedsRehash(%q) = false", hash)
+		}
+	}
+}
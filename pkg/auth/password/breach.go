@@ -0,0 +1,129 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Длина префикса SHA-1, по которому Have I Been Pwned отдаёт диапазон хэшей
+const rangePrefixLength = 5
+
+//go:embed breached.txt
+var bundledHashes []byte
+
+// Breaches ищет пароль среди утёкших. Сравниваются SHA-1 по схеме k-анонимности
+// Have I Been Pwned: префикс хэша выбирает диапазон, суффикс ищется внутри него
+type Breaches interface {
+	Contains(password string) (bool, error)
+}
+
+// HashList держит список хэшей в памяти, сгруппированный по префиксам
+type HashList struct {
+	ranges map[string]map[string]struct{}
+}
+
+// BundledHashList - встроенный список самых частых паролей
+func BundledHashList() (*HashList, error) {
+	return ParseHashList(bytes.NewReader(bundledHashes))
+}
+
+// ParseHashList читает строки вида HASH или HASH:COUNT. Пустые строки и # пропускаются
+func ParseHashList(r io.Reader) (*HashList, error) {
+	list := &HashList{ranges: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 || !isHex(hash) {
+			return nil, fmt.Errorf("invalid breached password hash on line %d", line)
+		}
+
+		prefix, suffix := hash[:rangePrefixLength], hash[rangePrefixLength:]
+		if list.ranges[prefix] == nil {
+			list.ranges[prefix] = make(map[string]struct{})
+		}
+		list.ranges[prefix][suffix] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (l *HashList) Contains(password string) (bool, error) {
+	prefix, suffix := hashRange(password)
+	_, ok := l.ranges[prefix][suffix]
+	return ok, nil
+}
+
+// RangeDir - каталог в формате загрузчика Have I Been Pwned: файлы <PREFIX>.txt
+// со строками SUFFIX:COUNT. Читается только файл с префиксом пароля
+type RangeDir struct {
+	dir string
+}
+
+func NewRangeDir(dir string) (*RangeDir, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return &RangeDir{dir: dir}, nil
+}
+
+func (d *RangeDir) Contains(password string) (bool, error) {
+	prefix, suffix := hashRange(password)
+
+	file, err := os.Open(filepath.Join(d.dir, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		candidate, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(candidate, suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+func hashRange(password string) (string, string) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	return hash[:rangePrefixLength], hash[rangePrefixLength:]
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,18 @@
+package password
+
+// Checker объединяет оценку стойкости и проверку по утечкам
+type Checker struct {
+	breaches Breaches
+}
+
+func NewChecker(breaches Breaches) *Checker {
+	return &Checker{breaches: breaches}
+}
+
+func (c *Checker) Score(password string, userInputs ...string) int {
+	return Score(password, userInputs...)
+}
+
+func (c *Checker) IsBreached(password string) (bool, error) {
+	return c.breaches.Contains(password)
+}
@@ -0,0 +1,63 @@
+package password
+
+// commonPasswords упорядочены по частоте в публичных утечках: ранг слова
+// определяет число попыток, за которое его подберут
+var commonPasswords = []string{
+	"123456", "password", "12345678", "qwerty", "123456789", "12345", "1234", "111111",
+	"1234567", "dragon", "123123", "baseball", "abc123", "football", "monkey", "letmein",
+	"696969", "shadow", "master", "666666", "qwertyuiop", "123321", "mustang", "1234567890",
+	"michael", "654321", "superman", "1qaz2wsx", "7777777", "121212", "000000", "qazwsx",
+	"123qwe", "killer", "trustno1", "jordan", "jennifer", "zxcvbnm", "asdfgh", "hunter",
+	"buster", "soccer", "harley", "batman", "andrew", "tigger", "sunshine", "iloveyou",
+	"2000", "charlie", "robert", "thomas", "hockey", "ranger", "daniel", "starwars",
+	"klaster", "112233", "george", "computer", "michelle", "jessica", "pepper", "1111",
+	"zxcvbn", "555555", "11111111", "131313", "freedom", "777777", "pass", "maggie",
+	"159753", "aaaaaa", "ginger", "princess", "joshua", "cheese", "amanda", "summer",
+	"love", "ashley", "nicole", "chelsea", "biteme", "matthew", "access", "yankees",
+	"987654321", "dallas", "austin", "thunder", "taylor", "matrix", "welcome", "admin", "login",
+	"passw0rd", "password1", "qwerty123", "1q2w3e4r", "1q2w3e", "zaq12wsx", "secret",
+	"whatever", "hello", "flower", "samsung", "google", "lovely", "solo",
+	"nothing", "blink182", "pokemon", "naruto", "liverpool", "arsenal", "chocolate",
+	"qwe123", "asdf", "asdfasdf", "qwerty1", "default", "changeme", "blog", "test",
+}
+
+// commonWords - частые слова, из которых люди собирают пароли
+var commonWords = []string{
+	"the", "love", "life", "time", "world", "house", "money", "music", "happy", "family",
+	"friend", "summer", "winter", "spring", "autumn", "secret", "dragon", "angel", "magic",
+	"power", "star", "sun", "moon", "fire", "water", "blue", "black", "red", "green",
+	"white", "baby", "girl", "boy", "king", "queen", "prince", "princess", "lucky",
+	"sweet", "super", "hello", "welcome", "letme", "admin", "user", "login", "pass",
+	"word", "test", "guest", "root", "blog", "game", "gamer", "cool", "crazy", "best",
+	"forever", "always", "never", "monday", "friday", "january", "december", "russia",
+	"moscow", "london", "paris", "america", "football", "soccer", "hockey", "tiger",
+	"monkey", "bear", "wolf", "eagle", "horse", "kitty", "puppy", "cat", "dog",
+}
+
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+	"йцукенгшщзхъ",
+	"фывапролджэ",
+	"ячсмитьбю",
+}
+
+var leetSubstitutions = map[rune][]rune{
+	'4': {'a'},
+	'@': {'a'},
+	'8': {'b'},
+	'(': {'c'},
+	'3': {'e'},
+	'6': {'g'},
+	'1': {'i', 'l'},
+	'!': {'i'},
+	'|': {'i', 'l'},
+	'0': {'o'},
+	'$': {'s'},
+	'5': {'s'},
+	'7': {'t'},
+	'+': {'t'},
+	'2': {'z'},
+}
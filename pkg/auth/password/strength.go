@@ -0,0 +1,369 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	minMatchLength = 3
+	// Без этого штрафа пароль из одних известных кусков оценивался бы как их сумма
+	matchPenalty = 0.3
+	// Длинные блоки повторов почти не встречаются, а их разбор стоит дорого:
+	// блок оценивается рекурсивно. Ограничения держат время оценки почти линейным
+	maxRepeatBlock = 16
+	maxRepeatDepth = 2
+)
+
+// Пороги zxcvbn по десятичному логарифму числа попыток
+var scoreThresholds = []float64{3, 6, 8, 10}
+
+type match struct {
+	start, end int
+	guesses    float64 // десятичный логарифм
+}
+
+// Score оценивает стойкость пароля от 0 до 4 по образцу zxcvbn. Пароль разбивается
+// на известные шаблоны: слова из словаря и userInputs, последовательности, повторы,
+// ряды клавиатуры и годы. Остальные символы считаются перебором. Оценка - минимальное
+// число попыток среди всех разбиений
+func Score(password string, userInputs ...string) int {
+	est := newEstimator(userInputs)
+	guesses := est.guesses(password)
+
+	for score, threshold := range scoreThresholds {
+		if guesses < threshold {
+			return score
+		}
+	}
+
+	return len(scoreThresholds)
+}
+
+type estimator struct {
+	ranks      map[string]int
+	maxWordLen int
+	// Оценки уже разобранных блоков для повторов
+	memo map[string]float64
+	// Глубина вложенности разбора блоков повторов
+	depth int
+}
+
+func newEstimator(userInputs []string) *estimator {
+	ranks := make(map[string]int, len(commonPasswords)+len(commonWords)+len(userInputs))
+	maxWordLen := 0
+	addWords := func(words []string, offset int) {
+		for i, word := range words {
+			word = strings.ToLower(word)
+			length := len([]rune(word))
+			if _, ok := ranks[word]; !ok && length >= minMatchLength {
+				ranks[word] = offset + i + 1
+				maxWordLen = max(maxWordLen, length)
+			}
+		}
+	}
+	// Данные пользователя проверяются первыми: их перебирают в первую очередь
+	addWords(userInputs, 0)
+	addWords(commonPasswords, len(userInputs))
+	addWords(commonWords, len(userInputs)+len(commonPasswords))
+
+	return &estimator{
+		ranks:      ranks,
+		maxWordLen: maxWordLen,
+		memo:       make(map[string]float64),
+	}
+}
+
+// guesses возвращает десятичный логарифм числа попыток
+func (est *estimator) guesses(password string) float64 {
+	if cached, ok := est.memo[password]; ok {
+		return cached
+	}
+
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var matches []match
+	matches = append(matches, est.dictionaryMatches(runes, lower)...)
+	matches = append(matches, sequenceMatches(lower)...)
+	matches = append(matches, est.repeatMatches(lower)...)
+	matches = append(matches, keyboardMatches(lower)...)
+	matches = append(matches, yearMatches(lower)...)
+
+	byEnd := make(map[int][]match, len(matches))
+	for _, m := range matches {
+		byEnd[m.end] = append(byEnd[m.end], m)
+	}
+
+	bruteforce := math.Log10(float64(cardinality(runes)))
+
+	// best[i] - минимальная стоимость первых i символов
+	best := make([]float64, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		best[i] = best[i-1] + bruteforce
+		for _, m := range byEnd[i] {
+			cost := best[m.start] + m.guesses
+			if m.start > 0 {
+				cost += matchPenalty
+			}
+			if cost < best[i] {
+				best[i] = cost
+			}
+		}
+	}
+
+	est.memo[password] = best[len(runes)]
+	return best[len(runes)]
+}
+
+func (est *estimator) dictionaryMatches(runes, lower []rune) []match {
+	var matches []match
+	for _, variant := range unleetVariants(lower) {
+		for i := 0; i < len(variant); i++ {
+			for j := i + minMatchLength; j <= min(len(variant), i+est.maxWordLen); j++ {
+				rank, ok := est.ranks[string(variant[i:j])]
+				if !ok {
+					continue
+				}
+
+				guesses := math.Log10(float64(rank)) +
+					uppercaseVariations(runes[i:j]) +
+					leetVariations(lower[i:j], variant[i:j])
+				matches = append(matches, match{start: i, end: j, guesses: guesses})
+			}
+		}
+	}
+
+	return matches
+}
+
+// unleetVariants возвращает пароль без подстановок вида p@ssw0rd -> password.
+// У символов с несколькими вариантами берётся первый и второй по очереди
+func unleetVariants(lower []rune) [][]rune {
+	variants := [][]rune{lower}
+	for k := 0; k < 2; k++ {
+		variant := make([]rune, len(lower))
+		changed := false
+		for i, r := range lower {
+			subs, ok := leetSubstitutions[r]
+			if !ok {
+				variant[i] = r
+				continue
+			}
+
+			variant[i] = subs[min(k, len(subs)-1)]
+			changed = true
+		}
+
+		if changed {
+			variants = append(variants, variant)
+		}
+	}
+
+	return variants
+}
+
+func uppercaseVariations(word []rune) float64 {
+	upper := 0
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 0
+	// Заглавная первая буква или все заглавные - самые частые варианты
+	case upper == len(word) || (upper == 1 && unicode.IsUpper(word[0])):
+		return math.Log10(2)
+	default:
+		return float64(min(upper, len(word)-upper)) * math.Log10(2)
+	}
+}
+
+func leetVariations(original, unleeted []rune) float64 {
+	subs := 0
+	for i := range original {
+		if original[i] != unleeted[i] {
+			subs++
+		}
+	}
+
+	return float64(subs) * math.Log10(2)
+}
+
+// sequenceMatches ищет участки вида abc, 9876, zyx с постоянным шагом 1
+func sequenceMatches(lower []rune) []match {
+	var matches []match
+	for i := 0; i < len(lower)-1; {
+		delta := lower[i+1] - lower[i]
+		if delta != 1 && delta != -1 {
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j+1 < len(lower) && lower[j+1]-lower[j] == delta {
+			j++
+		}
+
+		if length := j - i + 1; length >= minMatchLength {
+			base := 26.0
+			switch {
+			case unicode.IsDigit(lower[i]):
+				base = 10
+			case strings.ContainsRune("az019", lower[i]):
+				// Очевидное начало последовательности
+				base = 4
+			}
+			if delta < 0 {
+				base *= 2
+			}
+
+			matches = append(matches, match{start: i, end: j + 1, guesses: math.Log10(base * float64(length))})
+		}
+
+		i = j
+	}
+
+	return matches
+}
+
+// repeatMatches ищет повторы символа (aaaa) и блока (abcabc). Стоимость повтора -
+// стоимость блока и числа повторений
+func (est *estimator) repeatMatches(lower []rune) []match {
+	if est.depth >= maxRepeatDepth {
+		return nil
+	}
+	est.depth++
+	defer func() { est.depth-- }()
+
+	var matches []match
+	for i := 0; i < len(lower); i++ {
+		for size := 1; size <= maxRepeatBlock && i+2*size <= len(lower); size++ {
+			block := lower[i : i+size]
+			// Повтор, начатый раньше, уже найден целиком
+			if i >= size && string(lower[i-size:i]) == string(block) {
+				continue
+			}
+
+			count := 1
+			for end := i + size; end+size <= len(lower) && string(lower[end:end+size]) == string(block); end += size {
+				count++
+			}
+
+			if count < 2 || count*size < minMatchLength {
+				continue
+			}
+
+			var base float64
+			if size == 1 {
+				base = math.Log10(float64(cardinality(block)))
+			} else {
+				base = est.guesses(string(block))
+			}
+
+			matches = append(matches, match{start: i, end: i + count*size, guesses: base + math.Log10(float64(count))})
+		}
+	}
+
+	return matches
+}
+
+// keyboardMatches ищет отрезки рядов клавиатуры в любом направлении: qwer, ;lkj
+func keyboardMatches(lower []rune) []match {
+	const minLength = 4
+
+	var matches []match
+	for _, row := range keyboardRows {
+		forward := []rune(row)
+		reversed := make([]rune, len(forward))
+		for i, r := range forward {
+			reversed[len(forward)-1-i] = r
+		}
+
+		for _, line := range []string{string(forward), string(reversed)} {
+			for i := 0; i < len(lower); i++ {
+				for j := i + minLength; j <= len(lower); j++ {
+					if !strings.Contains(line, string(lower[i:j])) {
+						break
+					}
+
+					guesses := math.Log10(float64(len(forward)*2) * float64(j-i))
+					matches = append(matches, match{start: i, end: j, guesses: guesses})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+func yearMatches(lower []rune) []match {
+	var matches []match
+	for i := 0; i+4 <= len(lower); i++ {
+		year := string(lower[i : i+4])
+		if (strings.HasPrefix(year, "19") || strings.HasPrefix(year, "20")) && isDigits(year) {
+			matches = append(matches, match{start: i, end: i + 4, guesses: 2})
+		}
+	}
+
+	return matches
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cardinality - размер алфавита, из которого подбирается пароль
+func cardinality(runes []rune) int {
+	var lower, upper, digits, symbols, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digits = true
+		case r < unicode.MaxASCII && unicode.IsPrint(r):
+			symbols = true
+		default:
+			other = true
+		}
+	}
+
+	size := 0
+	if lower {
+		size += 26
+	}
+	if upper {
+		size += 26
+	}
+	if digits {
+		size += 10
+	}
+	if symbols {
+		size += 33
+	}
+	if other {
+		size += 100
+	}
+
+	return max(size, 10)
+}
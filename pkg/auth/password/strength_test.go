@@ -0,0 +1,56 @@
+package password
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		userInputs []string
+		want       int
+	}{
+		{name: "common password", password: "password", want: 0},
+		{name: "leet substitutions", password: "P@ssw0rd", want: 0},
+		{name: "keyboard row", password: "qwertyuiop", want: 0},
+		{name: "repeated character", password: "aaaaaaaaaaaa", want: 0},
+		{name: "repeated block", password: "abcabcabcabc", want: 0},
+		{name: "digit sequence", password: "1234567890", want: 0},
+		{name: "user input with year", password: "ivanpetrov1990", userInputs: []string{"ivanpetrov"}, want: 0},
+		{name: "random symbols", password: "x7#Kq9!mZ2$v", want: 4},
+		{name: "passphrase", password: "correct horse battery staple", want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.password, tt.userInputs...); got != tt.want {
+				t.Errorf("Score(%q) = %d, want %d", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+// Оценка длинных паролей с повторами не должна расти быстрее чем почти линейно
+func TestScoreLongPassword(t *testing.T) {
+	const length = 1024
+
+	passwords := map[string]string{
+		"single character": strings.Repeat("a", length),
+		"short block":      strings.Repeat("ab", length/2),
+		"long block":       strings.Repeat("abcdefghijklmnopq1", length/18+1)[:length],
+		"keyboard":         strings.Repeat("qwerty", length/6+1)[:length],
+	}
+
+	for name, password := range passwords {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			Score(password)
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Score() of %d characters took %s", length, elapsed)
+			}
+		})
+	}
+}
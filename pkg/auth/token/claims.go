@@ -10,12 +10,38 @@ import (
 )
 
 type UserClaims struct {
-	Email string      `json:"email"`
-	Role  domain.Role `json:"role"`
+	Email     string      `json:"email"`
+	Role      domain.Role `json:"role"`
+	SessionID string      `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func NewUserClaims(userId uint, email string, role domain.Role, expiresAt time.Time) (*UserClaims, error) {
+// Access токены и промежуточные токены 2FA подписываются одним ключом, поэтому
+// различаются аудиторией (aud), и каждая проверка принимает только свою
+const (
+	AudienceAccess    = "access"
+	AudienceTwoFactor = "2fa-challenge"
+)
+
+func NewChallengeClaims(userId uint, audience string, expiresAt time.Time) (*UserClaims, error) {
+	const op = "token.NewChallengeClaims"
+	tokenId, err := uuid.NewRandom()
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return &UserClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenId.String(),
+			Subject:   strconv.FormatUint(uint64(userId), 10),
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}, nil
+}
+
+func NewUserClaims(userId uint, sessionId uuid.UUID, email string, role domain.Role, expiresAt time.Time) (*UserClaims, error) {
 	const op = "token.NewUserClaims"
 	tokenId, err := uuid.NewRandom()
 	if err != nil {
@@ -23,11 +49,13 @@ func NewUserClaims(userId uint, email string, role domain.Role, expiresAt time.T
 	}
 
 	return &UserClaims{
-		Email: email,
-		Role:  role,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionId.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        tokenId.String(),
 			Subject:   strconv.FormatUint(uint64(userId), 10),
+			Audience:  jwt.ClaimStrings{AudienceAccess},
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
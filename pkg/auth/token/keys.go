@@ -0,0 +1,239 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"my_blog_backend/internal/usecase"
+	"my_blog_backend/pkg/e"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSAKeyBits = 2048
+
+// Key - ключ подписи JWT. У ключей, оставленных после ротации только для
+// проверки уже выданных токенов, приватной части нет
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private any
+	public  any
+}
+
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// KeySet - ключ, которым подписываются новые токены, и все ключи, которыми
+// токены принимаются
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	methods []string
+}
+
+// NewHMACKeySet - прежняя схема с HS256 на общем секрете. Ключ без kid,
+// в JWKS не публикуется
+func NewHMACKeySet(secret string) *KeySet {
+	key := &Key{
+		Method:  jwt.SigningMethodHS256,
+		private: []byte(secret),
+		public:  []byte(secret),
+	}
+
+	return &KeySet{
+		signing: key,
+		keys:    map[string]*Key{"": key},
+		methods: []string{key.Method.Alg()},
+	}
+}
+
+// LoadKeySet читает из dir ключи вида <kid>.pem: приватные RSA/Ed25519 ключи
+// или публичные ключи, оставленные для проверки после ротации.
+// Подписывает ключ signingKeyID; если он не задан, приватный ключ должен быть один
+func LoadKeySet(dir, signingKeyID string) (*KeySet, error) {
+	const op = "token.LoadKeySet"
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	sort.Strings(paths)
+
+	var keys []*Key
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+
+		key, err := ParseKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, e.Wrap(op, e.Wrap(path, err))
+		}
+
+		keys = append(keys, key)
+	}
+
+	ks, err := NewKeySet(keys, signingKeyID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return ks, nil
+}
+
+func NewKeySet(keys []*Key, signingKeyID string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+	methods := make(map[string]struct{})
+
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key id is empty")
+		}
+
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+
+		ks.keys[key.ID] = key
+		if _, ok := methods[key.Method.Alg()]; !ok {
+			methods[key.Method.Alg()] = struct{}{}
+			ks.methods = append(ks.methods, key.Method.Alg())
+		}
+
+		if !key.CanSign() {
+			continue
+		}
+
+		switch {
+		case signingKeyID != "":
+			if key.ID == signingKeyID {
+				ks.signing = key
+			}
+		case ks.signing != nil:
+			return nil, errors.New("several private keys found, signing key id must be set")
+		default:
+			ks.signing = key
+		}
+	}
+
+	if ks.signing == nil {
+		return nil, fmt.Errorf("private signing key %q not found", signingKeyID)
+	}
+
+	return ks, nil
+}
+
+// ParseKey разбирает PEM с ключом RSA (PKCS#1 или PKCS#8/PKIX) или Ed25519
+func ParseKey(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var (
+		parsed any
+		err    error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	if pub, ok := key.public.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+	}
+
+	return key, nil
+}
+
+// verificationKey выбирает ключ по kid из заголовка. Алгоритм токена обязан
+// совпадать с алгоритмом ключа, иначе публичный ключ можно подсунуть как HMAC секрет
+func (ks *KeySet) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), kid)
+	}
+
+	return key.public, nil
+}
+
+// PublicKeys возвращает асимметричные ключи в формате JWK (RFC 7517)
+func (ks *KeySet) PublicKeys() []usecase.JSONWebKey {
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	res := make([]usecase.JSONWebKey, 0, len(ids))
+	for _, id := range ids {
+		key := ks.keys[id]
+
+		jwk := usecase.JSONWebKey{
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Method.Alg(),
+		}
+
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			// Симметричный ключ публиковать нельзя
+			continue
+		}
+
+		res = append(res, jwk)
+	}
+
+	return res
+}
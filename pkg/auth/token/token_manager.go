@@ -12,31 +12,37 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Время на ввод кода второго фактора после проверки пароля
+const challengeTTL = 5 * time.Minute
+
 type TokenManager struct {
-	secretKey string
-	duration  time.Duration
+	keys *KeySet
+	// refreshSecret - ключ HMAC для хэшей refresh и одноразовых токенов, не связан с подписью JWT
+	refreshSecret string
+	duration      time.Duration
 }
 
-func NewTokenManager(secretKey string, duration time.Duration) *TokenManager {
+func NewTokenManager(keys *KeySet, refreshSecret string, duration time.Duration) *TokenManager {
 	return &TokenManager{
-		secretKey: secretKey,
-		duration:  duration,
+		keys:          keys,
+		refreshSecret: refreshSecret,
+		duration:      duration,
 	}
 }
 
-func (manager *TokenManager) NewJWT(userID uint, email string, role domain.Role) (*usecase.TokenResponse, error) {
+func (manager *TokenManager) NewJWT(userID uint, sessionID uuid.UUID, email string, role domain.Role) (*usecase.TokenResponse, error) {
 	const op = "TokenManager.NewJWT"
 	expiresAt := time.Now().Add(manager.duration)
 
-	claims, err := NewUserClaims(userID, email, role, expiresAt)
+	claims, err := NewUserClaims(userID, sessionID, email, role, expiresAt)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(manager.secretKey))
+	tokenString, err := manager.sign(claims)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
@@ -49,26 +55,89 @@ func (manager *TokenManager) NewJWT(userID uint, email string, role domain.Role)
 
 func (manager *TokenManager) VerifyJWT(tokenString string) (*usecase.AuthenticatedUser, error) {
 	const op = "tokenManager.VerifyJWT"
-	claims := &UserClaims{}
 
-	keyFunc := func(token *jwt.Token) (interface{}, error) {
-		return []byte(manager.secretKey), nil
+	claims, err := manager.parse(tokenString, AudienceAccess)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return claimsToAuthPrincipal(claims)
+}
+
+// NewChallengeToken выпускает короткоживущий токен, подтверждающий, что пароль
+// уже проверен и осталось ввести код второго фактора
+func (manager *TokenManager) NewChallengeToken(userID uint) (*usecase.TokenResponse, error) {
+	const op = "TokenManager.NewChallengeToken"
+	expiresAt := time.Now().Add(challengeTTL)
+
+	claims, err := NewChallengeClaims(userID, AudienceTwoFactor, expiresAt)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	tokenString, err := manager.sign(claims)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return &usecase.TokenResponse{
+		Token:     tokenString,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (manager *TokenManager) VerifyChallengeToken(tokenString string) (uint, error) {
+	const op = "TokenManager.VerifyChallengeToken"
+
+	claims, err := manager.parse(tokenString, AudienceTwoFactor)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	userId, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return 0, e.Wrap(op, e.ErrTokenInvalid)
+	}
+
+	return uint(userId), nil
+}
+
+func (manager *TokenManager) PublicKeys() []usecase.JSONWebKey {
+	return manager.keys.PublicKeys()
+}
+
+func (manager *TokenManager) sign(claims *UserClaims) (string, error) {
+	key := manager.keys.signing
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
 	}
 
+	return token.SignedString(key.private)
+}
+
+// parse проверяет подпись ключом, выбранным по kid из заголовка (алгоритм токена должен
+// совпадать с алгоритмом этого ключа), срок действия и аудиторию audience:
+// токен без aud или выпущенный для другой цели отклоняется
+func (manager *TokenManager) parse(tokenString string, audience string) (*UserClaims, error) {
+	claims := &UserClaims{}
+
 	token, err := jwt.ParseWithClaims(
 		tokenString,
 		claims,
-		keyFunc, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		manager.keys.verificationKey,
+		jwt.WithValidMethods(manager.keys.methods),
+		jwt.WithAudience(audience),
 	)
 	if err != nil {
-		return nil, e.Wrap(op, e.ErrParseFailed)
+		return nil, e.ErrParseFailed
 	}
 
 	if !token.Valid {
-		return nil, e.Wrap(op, e.ErrTokenInvalid)
+		return nil, e.ErrTokenInvalid
 	}
 
-	return claimsToAuthPrincipal(claims)
+	return claims, nil
 }
 
 func (manager *TokenManager) NewRefreshToken() (string, string, error) {
@@ -84,7 +153,7 @@ func (manager *TokenManager) NewRefreshToken() (string, string, error) {
 }
 
 func (manager *TokenManager) HashRefreshToken(token string) string {
-	mac := hmac.New(sha256.New, []byte(manager.secretKey))
+	mac := hmac.New(sha256.New, []byte(manager.refreshSecret))
 	mac.Write([]byte(token))
 	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
 }
@@ -96,10 +165,17 @@ func claimsToAuthPrincipal(claims *UserClaims) (*usecase.AuthenticatedUser, erro
 		return nil, e.Wrap(op, err)
 	}
 
+	// Токены, выпущенные до появления sid, остаются валидными, но без привязки к сессии
+	sessionId, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		sessionId = uuid.Nil
+	}
+
 	authPrincipal := &usecase.AuthenticatedUser{
-		ID:    uint(userId),
-		Email: claims.Email,
-		Role:  claims.Role,
+		ID:        uint(userId),
+		SessionID: sessionId,
+		Email:     claims.Email,
+		Role:      claims.Role,
 	}
 
 	return authPrincipal, nil
@@ -0,0 +1,106 @@
+package token
+
+import (
+	"errors"
+	"my_blog_backend/internal/domain"
+	"my_blog_backend/pkg/e"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func newTestManager() *TokenManager {
+	return NewTokenManager(NewHMACKeySet("access-secret"), "refresh-secret", time.Minute)
+}
+
+// signClaims подписывает произвольные claims тем же ключом, что и менеджер
+func signClaims(t *testing.T, m *TokenManager, claims *UserClaims) string {
+	t.Helper()
+
+	token, err := m.sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestVerifyJWTAcceptsOnlyAccessTokens(t *testing.T) {
+	m := newTestManager()
+
+	access, err := m.NewJWT(7, uuid.New(), "user@example.com", domain.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := m.NewChallengeToken(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noAudience := &UserClaims{
+		Email: "user@example.com",
+		Role:  domain.RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "7",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	otherAudience := *noAudience
+	otherAudience.Audience = jwt.ClaimStrings{"something-else"}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"access token", access.Token, false},
+		{"2fa challenge token", challenge.Token, true},
+		{"token without audience", signClaims(t, m, noAudience), true},
+		{"token for another audience", signClaims(t, m, &otherAudience), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := m.VerifyJWT(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("VerifyJWT() accepted %s as user %d", tt.name, user.ID)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("VerifyJWT() error = %v", err)
+			}
+			if user.ID != 7 {
+				t.Errorf("user ID = %d, want 7", user.ID)
+			}
+		})
+	}
+}
+
+func TestVerifyChallengeTokenRejectsAccessTokens(t *testing.T) {
+	m := newTestManager()
+
+	challenge, err := m.NewChallengeToken(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID, err := m.VerifyChallengeToken(challenge.Token)
+	if err != nil {
+		t.Fatalf("VerifyChallengeToken() error = %v", err)
+	}
+	if userID != 7 {
+		t.Errorf("user ID = %d, want 7", userID)
+	}
+
+	access, err := m.NewJWT(7, uuid.New(), "user@example.com", domain.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.VerifyChallengeToken(access.Token); !errors.Is(err, e.ErrParseFailed) {
+		t.Errorf("VerifyChallengeToken(access) error = %v, want %v", err, e.ErrParseFailed)
+	}
+}
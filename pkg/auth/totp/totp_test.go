@@ -0,0 +1,87 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// Секрет из приложения B RFC 6238 для SHA-1: ASCII "12345678901234567890"
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeRFC6238(t *testing.T) {
+	// Векторы RFC 6238 содержат 8 цифр, шестизначный код - их последние 6
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code(%d) error = %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name   string
+		offset int64 // сдвиг шага, для которого выдан код
+		skew   int
+		wantOK bool
+	}{
+		{name: "current step", offset: 0, skew: 1, wantOK: true},
+		{name: "previous step", offset: -1, skew: 1, wantOK: true},
+		{name: "next step", offset: 1, skew: 1, wantOK: true},
+		{name: "two steps behind", offset: -2, skew: 1},
+		{name: "two steps ahead", offset: 2, skew: 1},
+		{name: "previous step without skew", offset: -1, skew: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeTime := now.Add(time.Duration(tt.offset) * Period)
+			code, err := Code(rfcSecret, codeTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			step, ok := Validate(rfcSecret, code, now, tt.skew)
+			if ok != tt.wantOK {
+				t.Fatalf("Validate() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("Validate() step = %d, want %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformed(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		if _, ok := Validate(rfcSecret, code, now, 1); ok {
+			t.Errorf("Validate(%q) ok = true", code)
+		}
+	}
+
+	code, _ := Code(rfcSecret, now)
+	if _, ok := Validate("not base32!", code, now, 1); ok {
+		t.Error("Validate() with a malformed secret ok = true")
+	}
+}
@@ -0,0 +1,310 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DefaultContext = 3
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+type op struct {
+	kind opKind
+	line string
+	// Номера строк (с нуля) в старом и новом тексте
+	oldIdx int
+	newIdx int
+}
+
+// Unified строит построчный diff в формате unified diff.
+// Для одинаковых текстов возвращает пустую строку
+func Unified(oldText, newText, oldName, newName string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+
+	if context < 0 {
+		context = DefaultContext
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks(ops, context) {
+		writeHunk(&b, ops[h[0]:h[1]])
+	}
+
+	return b.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines строит кратчайший скрипт правки алгоритмом Майерса в линейной по памяти
+// версии (поиск middle snake с делением пополам), поэтому память не растёт как n*m
+func diffLines(a, b []string) []op {
+	d := &differ{
+		a:   a,
+		b:   b,
+		ops: make([]op, 0, len(a)+len(b)),
+	}
+	d.ai, d.bi = internLines(a, b)
+	d.compare(0, len(a), 0, len(b))
+
+	return d.ops
+}
+
+// internLines заменяет строки номерами, чтобы сравнивать их за O(1)
+func internLines(a, b []string) ([]int, []int) {
+	ids := make(map[string]int, len(a))
+	intern := func(lines []string) []int {
+		res := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+			res[i] = id
+		}
+
+		return res
+	}
+
+	return intern(a), intern(b)
+}
+
+type differ struct {
+	a, b   []string
+	ai, bi []int
+	ops    []op
+}
+
+// compare добавляет в ops скрипт правки a[aLo:aHi] в b[bLo:bHi]
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.ai[aLo] == d.bi[bLo] {
+		d.ops = append(d.ops, op{kind: opEqual, line: d.a[aLo], oldIdx: aLo, newIdx: bLo})
+		aLo++
+		bLo++
+	}
+
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.ai[aHi-suffix-1] == d.bi[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		d.insert(aLo, bLo, bHi)
+	case bLo == bHi:
+		d.delete(aLo, aHi, bLo)
+	default:
+		if x, y, ok := d.bisect(aLo, aHi, bLo, bHi); ok {
+			d.compare(aLo, x, bLo, y)
+			d.compare(x, aHi, y, bHi)
+		} else {
+			d.delete(aLo, aHi, bLo)
+			d.insert(aHi, bLo, bHi)
+		}
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.ops = append(d.ops, op{kind: opEqual, line: d.a[aHi+i], oldIdx: aHi + i, newIdx: bHi + i})
+	}
+}
+
+func (d *differ) delete(aLo, aHi, bPos int) {
+	for i := aLo; i < aHi; i++ {
+		d.ops = append(d.ops, op{kind: opDelete, line: d.a[i], oldIdx: i, newIdx: bPos})
+	}
+}
+
+func (d *differ) insert(aPos, bLo, bHi int) {
+	for j := bLo; j < bHi; j++ {
+		d.ops = append(d.ops, op{kind: opInsert, line: d.b[j], oldIdx: aPos, newIdx: j})
+	}
+}
+
+// bisect ищет middle snake, идя по диагоналям одновременно с начала и с конца,
+// и возвращает точку, в которой задачу можно разделить на две независимые
+func (d *differ) bisect(aLo, aHi, bLo, bHi int) (int, int, bool) {
+	n, m := aHi-aLo, bHi-bLo
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	size := 2*maxD + 2
+
+	forward := make([]int, size)
+	backward := make([]int, size)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	// При нечётной delta пути встречаются на шаге вперёд, при чётной - на шаге назад
+	oddDelta := delta%2 != 0
+	k1start, k1end, k2start, k2end := 0, 0, 0, 0
+
+	for step := 0; step < maxD; step++ {
+		for k1 := -step + k1start; k1 <= step-k1end; k1 += 2 {
+			k1Offset := offset + k1
+			var x1 int
+			if k1 == -step || (k1 != step && forward[k1Offset-1] < forward[k1Offset+1]) {
+				x1 = forward[k1Offset+1]
+			} else {
+				x1 = forward[k1Offset-1] + 1
+			}
+
+			y1 := x1 - k1
+			for x1 < n && y1 < m && d.ai[aLo+x1] == d.bi[bLo+y1] {
+				x1++
+				y1++
+			}
+			forward[k1Offset] = x1
+
+			switch {
+			case x1 > n:
+				k1end += 2
+			case y1 > m:
+				k1start += 2
+			case oddDelta:
+				k2Offset := offset + delta - k1
+				if k2Offset >= 0 && k2Offset < size && backward[k2Offset] != -1 {
+					if x1 >= n-backward[k2Offset] {
+						return aLo + x1, bLo + y1, true
+					}
+				}
+			}
+		}
+
+		for k2 := -step + k2start; k2 <= step-k2end; k2 += 2 {
+			k2Offset := offset + k2
+			var x2 int
+			if k2 == -step || (k2 != step && backward[k2Offset-1] < backward[k2Offset+1]) {
+				x2 = backward[k2Offset+1]
+			} else {
+				x2 = backward[k2Offset-1] + 1
+			}
+
+			y2 := x2 - k2
+			for x2 < n && y2 < m && d.ai[aHi-x2-1] == d.bi[bHi-y2-1] {
+				x2++
+				y2++
+			}
+			backward[k2Offset] = x2
+
+			switch {
+			case x2 > n:
+				k2end += 2
+			case y2 > m:
+				k2start += 2
+			case !oddDelta:
+				k1Offset := offset + delta - k2
+				if k1Offset >= 0 && k1Offset < size && forward[k1Offset] != -1 {
+					x1 := forward[k1Offset]
+					y1 := offset + x1 - k1Offset
+					if x1 >= n-x2 {
+						return aLo + x1, bLo + y1, true
+					}
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+// Группирует изменения в ханки [start, end) с context строками контекста вокруг
+func hunks(ops []op, context int) [][2]int {
+	var res [][2]int
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == opEqual {
+			continue
+		}
+
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+
+			// Ханк заканчивается, если дальше идёт больше 2*context одинаковых строк подряд
+			run := end
+			for run < len(ops) && ops[run].kind == opEqual {
+				run++
+			}
+
+			if run == len(ops) || run-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+
+			end = run
+		}
+
+		if len(res) > 0 && res[len(res)-1][1] >= start {
+			res[len(res)-1][1] = end
+		} else {
+			res = append(res, [2]int{start, end})
+		}
+
+		i = end - 1
+	}
+
+	return res
+}
+
+func writeHunk(b *strings.Builder, ops []op) {
+	oldStart, newStart := ops[0].oldIdx, ops[0].newIdx
+	oldCount, newCount := 0, 0
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			oldCount++
+			newCount++
+		case opDelete:
+			oldCount++
+		case opInsert:
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, o := range ops {
+		b.WriteByte(byte(o.kind))
+		b.WriteString(o.line)
+		b.WriteByte('\n')
+	}
+}
+
+// По соглашению unified diff пустой диапазон указывает на строку перед ним
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
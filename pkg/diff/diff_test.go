@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Проверяет, что скрипт правки восстанавливает оба текста и номера строк согласованы
+func checkOps(t *testing.T, a, b []string) int {
+	t.Helper()
+
+	var oldLines, newLines []string
+	changes := 0
+	for _, o := range diffLines(a, b) {
+		switch o.kind {
+		case opEqual:
+			if o.oldIdx != len(oldLines) || o.newIdx != len(newLines) {
+				t.Fatalf("equal op has indices %d/%d, want %d/%d", o.oldIdx, o.newIdx, len(oldLines), len(newLines))
+			}
+			oldLines = append(oldLines, o.line)
+			newLines = append(newLines, o.line)
+		case opDelete:
+			if o.oldIdx != len(oldLines) || o.newIdx != len(newLines) {
+				t.Fatalf("delete op has indices %d/%d, want %d/%d", o.oldIdx, o.newIdx, len(oldLines), len(newLines))
+			}
+			oldLines = append(oldLines, o.line)
+			changes++
+		case opInsert:
+			if o.oldIdx != len(oldLines) || o.newIdx != len(newLines) {
+				t.Fatalf("insert op has indices %d/%d, want %d/%d", o.oldIdx, o.newIdx, len(oldLines), len(newLines))
+			}
+			newLines = append(newLines, o.line)
+			changes++
+		}
+	}
+
+	if strings.Join(oldLines, "\n") != strings.Join(a, "\n") {
+		t.Fatalf("old text is not restored")
+	}
+	if strings.Join(newLines, "\n") != strings.Join(b, "\n") {
+		t.Fatalf("new text is not restored")
+	}
+
+	return changes
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		changes int
+	}{
+		{"both empty", "", "", 0},
+		{"insert into empty", "", "a\nb", 2},
+		{"delete all", "a\nb", "", 2},
+		{"equal", "a\nb\nc", "a\nb\nc", 0},
+		{"replace middle", "a\nb\nc", "a\nx\nc", 2},
+		{"insert middle", "a\nc", "a\nb\nc", 1},
+		{"shuffled", "a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkOps(t, splitLines(tt.a), splitLines(tt.b))
+			if got != tt.changes {
+				t.Errorf("changes = %d, want %d", got, tt.changes)
+			}
+		})
+	}
+}
+
+func TestDiffLinesRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	gen := func() []string {
+		lines := make([]string, rnd.Intn(40))
+		for i := range lines {
+			lines[i] = strconv.Itoa(rnd.Intn(5))
+		}
+
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		checkOps(t, gen(), gen())
+	}
+}
+
+func TestUnified(t *testing.T) {
+	got := Unified("a\nb\nc\n", "a\nx\nc\n", "old", "new", 1)
+	want := "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"
+	if got != want {
+		t.Errorf("Unified() = %q, want %q", got, want)
+	}
+}
+
+// Полностью разные большие тексты не должны требовать памяти порядка n*m
+func TestDiffLinesLarge(t *testing.T) {
+	const n = 5000
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := range a {
+		a[i] = "old " + strconv.Itoa(i)
+		b[i] = "new " + strconv.Itoa(i)
+	}
+
+	if got := checkOps(t, a, b); got != 2*n {
+		t.Errorf("changes = %d, want %d", got, 2*n)
+	}
+}
@@ -3,17 +3,40 @@ package e
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 var (
 	// users
-	ErrUserNotFound        = errors.New("user not found")
-	ErrUserDuplicate       = errors.New("user with such email or username already exists")
-	ErrUsernameIsForbidden = errors.New("username is forbidden")
-	ErrPasswordIsSame      = errors.New("password is same")
-	ErrUsernameIsSame      = errors.New("username is same")
-	ErrUserAlreadyAdmin    = errors.New("user is already admin")
-	ErrEmailIsSame         = errors.New("email is same")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrUserDuplicate        = errors.New("user with such email or username already exists")
+	ErrUsernameIsForbidden  = errors.New("username is forbidden")
+	ErrPasswordIsSame       = errors.New("password is same")
+	ErrUsernameIsSame       = errors.New("username is same")
+	ErrEmailIsSame          = errors.New("email is same")
+	ErrUserSuspended        = errors.New("user is suspended")
+	ErrUserAlreadySuspended = errors.New("user is already suspended")
+	ErrUserNotSuspended     = errors.New("user is not suspended")
+	ErrUserHasArticles      = errors.New("user has articles")
+	// Пользователь, которому передаются статьи удаляемого, не найден или совпадает с ним
+	ErrReassignTargetInvalid = errors.New("reassign target is invalid")
+	ErrDeleteUserModeInvalid = errors.New("delete user mode is invalid")
+	// Удаление аккаунта самим пользователем
+	ErrAccountDeletionScheduled    = errors.New("account deletion is already scheduled")
+	ErrAccountDeletionNotScheduled = errors.New("account deletion is not scheduled")
+	ErrArticleDisposalInvalid      = errors.New("article disposal is invalid")
+	// profile
+	ErrProfileFieldTooLong   = errors.New("profile field is too long")
+	ErrProfileURLInvalid     = errors.New("profile url is invalid")
+	ErrProfileTooManyLinks   = errors.New("profile has too many links")
+	ErrProfileLinkLabelEmpty = errors.New("profile link label is empty")
+	// follows
+	ErrCannotFollowSelf  = errors.New("user cannot follow themselves")
+	ErrAlreadyFollowing  = errors.New("user is already followed")
+	ErrNotFollowing      = errors.New("user is not followed")
+	ErrAlreadySubscribed = errors.New("category is already subscribed")
+	ErrNotSubscribed     = errors.New("category is not subscribed")
 	// username
 	ErrUsernameInvalidChars = errors.New("username contains invalid characters")
 	ErrUsernameHasSpaces    = errors.New("username contains spaces")
@@ -21,11 +44,13 @@ var (
 	ErrEmailIsExists        = errors.New("the user's email address already exists.")
 	// role
 	ErrInvalidRole = errors.New("invalid role")
+	ErrRoleIsSame  = errors.New("role is same")
 	// email
 	ErrEmailInvalidFormat = errors.New("email format is invalid")
 	ErrEmailHasSpaces     = errors.New("email contains spaces")
 	// password
 	ErrPasswordHasSpaces = errors.New("password contains spaces")
+	ErrPasswordRejected  = errors.New("password does not meet the policy")
 
 	// categories
 	ErrCategoryIsExists     = errors.New("category with such name already exists")
@@ -33,18 +58,37 @@ var (
 	ErrCategoryNotFound     = errors.New("category not found")
 	ErrCategoryInUse        = errors.New("category is already in use")
 
+	// tags
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagInvalid  = errors.New("tag is invalid")
+	ErrTooManyTags = errors.New("too many tags")
+
 	// articles
-	ErrTitleHasHTML            = errors.New("title has html")
-	ErrContentHasScript        = errors.New("content has script")
-	ErrArticleNotFound         = errors.New("article not found")
-	ErrArticleNameIsExists     = errors.New("the name of the article is not changed")
-	ErrArticleContentIsExists  = errors.New("the content of the article is not changed")
-	ErrArticleCategoryIsExists = errors.New("the category of the article is not changed")
-	ErrArticleDataIsInvalid    = errors.New("title or content of the article is invalid")
-	ErrUserNotAuthor           = errors.New("user is not author")
-	ErrArticleDuplicate        = errors.New("article is duplicate")
+	ErrTitleInvalid                   = errors.New("title is invalid")
+	ErrContentInvalid                 = errors.New("content is invalid")
+	ErrArticleNotFound                = errors.New("article not found")
+	ErrArticleNameIsExists            = errors.New("the name of the article is not changed")
+	ErrArticleContentIsExists         = errors.New("the content of the article is not changed")
+	ErrArticleCategoryIsExists        = errors.New("the category of the article is not changed")
+	ErrArticleDataIsInvalid           = errors.New("title or content of the article is invalid")
+	ErrUserNotAuthor                  = errors.New("user is not author")
+	ErrArticleDuplicate               = errors.New("article is duplicate")
+	ErrArticleInvalidStatusTransition = errors.New("article status transition is not allowed")
+	ErrPublishAtInPast                = errors.New("publish time must be in the future")
+	ErrArticleNotScheduled            = errors.New("article is not scheduled")
+
+	// revisions
+	ErrRevisionNotFound = errors.New("revision not found")
+
+	// comments
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrCommentInvalid       = errors.New("comment is invalid")
+	ErrCommentDeleted       = errors.New("comment is deleted")
+	ErrCommentContentIsSame = errors.New("the content of the comment is not changed")
+	ErrUserNotCommentAuthor = errors.New("user is not author of the comment")
 
 	ErrMismatchedHashAndPassword = errors.New("password does not match hash")
+	ErrUnknownHashFormat         = errors.New("unknown password hash format")
 
 	// Sessions
 	ErrSessionRevoked            = errors.New("session revoked")
@@ -52,6 +96,30 @@ var (
 	ErrRefreshTokenHashDuplicate = errors.New("refresh token hash already exists")
 	ErrSessionNotFound           = errors.New("session not found")
 	ErrRefreshTokenInvalid       = errors.New("refresh token is invalid")
+	ErrRefreshTokenReused        = errors.New("refresh token reuse detected")
+
+	// Password reset
+	ErrPasswordResetTokenInvalid = errors.New("password reset token is invalid or expired")
+
+	// Email verification
+	ErrEmailVerificationTokenInvalid = errors.New("email verification token is invalid or expired")
+	ErrEmailAlreadyVerified          = errors.New("email is already verified")
+	ErrEmailNotVerified              = errors.New("email is not verified")
+
+	// Two-factor authentication
+	ErrTwoFactorCodeInvalid      = errors.New("two-factor code is invalid")
+	ErrTwoFactorChallengeInvalid = errors.New("two-factor challenge is invalid or expired")
+	ErrTwoFactorAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled      = errors.New("two-factor enrollment is not started")
+
+	// Personal access tokens
+	ErrAccessTokenNotFound     = errors.New("access token not found")
+	ErrAccessTokenInvalid      = errors.New("access token is invalid or expired")
+	ErrAccessTokenNameInvalid  = errors.New("access token name is invalid")
+	ErrAccessTokenScopeInvalid = errors.New("access token scope is invalid")
+	ErrAccessTokenTTLInvalid   = errors.New("access token expiry is invalid")
+	ErrTooManyAccessTokens     = errors.New("too many access tokens")
 
 	// Общие ошибки
 	ErrPermissionDenied   = errors.New("permission denied")
@@ -61,8 +129,38 @@ var (
 	ErrInternalServer     = errors.New("internal server error")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrNoDataToUpdate     = errors.New("no data to update")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrTooManyAttempts    = errors.New("too many attempts")
 )
 
+// RetryError - отказ, который можно повторить через RetryAfter.
+// errors.Is(err, ErrTooManyAttempts) для него истинно
+type RetryError struct {
+	RetryAfter time.Duration
+}
+
+func (err *RetryError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts, err.RetryAfter)
+}
+
+func (err *RetryError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// PasswordPolicyError перечисляет коды нарушенных правил пароля.
+// errors.Is(err, ErrPasswordRejected) для него истинно
+type PasswordPolicyError struct {
+	Reasons []string
+}
+
+func (err *PasswordPolicyError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPasswordRejected, strings.Join(err.Reasons, ", "))
+}
+
+func (err *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordRejected
+}
+
 func Wrap(msg string, err error) error {
 	return fmt.Errorf("%s: %w", msg, err)
 }
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"my_blog_backend/internal/usecase"
+	"my_blog_backend/pkg/e"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer складывает письма в каталог в виде .eml файлов. Нужен для локальной разработки
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	const op = "mailer.NewFileMailer"
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return &FileMailer{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, mail *usecase.Mail) error {
+	const op = "FileMailer.Send"
+
+	if err := ctx.Err(); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	now := time.Now()
+	msg, err := build(m.from, mail, now)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	recipient := strings.NewReplacer("/", "_", "\\", "_", "@", "_at_").Replace(mail.To)
+	name := fmt.Sprintf("%d-%s.eml", now.UnixNano(), recipient)
+	if err := os.WriteFile(filepath.Join(m.dir, name), msg, 0o640); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
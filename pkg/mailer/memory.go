@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"context"
+	"my_blog_backend/internal/usecase"
+	"sync"
+)
+
+// MemoryMailer хранит отправленные письма в памяти. Используется в тестах
+type MemoryMailer struct {
+	mu    sync.Mutex
+	mails []usecase.Mail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(_ context.Context, mail *usecase.Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mails = append(m.mails, *mail)
+	return nil
+}
+
+// Mails возвращает копию отправленных писем
+func (m *MemoryMailer) Mails() []usecase.Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mails := make([]usecase.Mail, len(m.mails))
+	copy(mails, m.mails)
+	return mails
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"my_blog_backend/internal/usecase"
+	"strings"
+	"time"
+)
+
+var errHeaderInjection = errors.New("mail header contains line break")
+
+// build собирает письмо в формате RFC 5322 с текстовым телом в UTF-8
+func build(from string, mail *usecase.Mail, now time.Time) ([]byte, error) {
+	for _, header := range []string{from, mail.To, mail.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, errHeaderInjection
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("To: " + mail.To + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", mail.Subject) + "\r\n")
+	buf.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(mail.Body, "\r\n", "\n"), "\n", "\r\n"))
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"context"
+	"my_blog_backend/internal/usecase"
+	"my_blog_backend/pkg/e"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer создаёт отправщик через SMTP. Без имени пользователя письма
+// отправляются без аутентификации (например, через локальный relay)
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+	}
+
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, mail *usecase.Mail) error {
+	const op = "SMTPMailer.Send"
+
+	if err := ctx.Err(); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	msg, err := build(m.from, mail, time.Now())
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{mail.To}, msg); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// Теги, которые не должны попадать в вывод ни при каком исходнике
+var forbiddenTags = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"object":   true,
+	"embed":    true,
+	"form":     true,
+	"base":     true,
+	"meta":     true,
+	"link":     true,
+	"svg":      true,
+	"math":     true,
+	"frame":    true,
+	"frameset": true,
+}
+
+// Атрибуты, в которых может оказаться URL
+var urlAttrs = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"xlink:href": true,
+	"background": true,
+	"poster":     true,
+}
+
+// assertSafe разбирает HTML так же, как браузер, и проверяет, что в нём нет исполняемой разметки
+func assertSafe(t *testing.T, source, out string) {
+	t.Helper()
+
+	z := html.NewTokenizer(strings.NewReader(out))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		tok := z.Token()
+		if forbiddenTags[tok.Data] {
+			t.Fatalf("source %q rendered forbidden tag <%s>: %q", source, tok.Data, out)
+		}
+
+		for _, attr := range tok.Attr {
+			key := strings.ToLower(attr.Key)
+			if strings.HasPrefix(key, "on") {
+				t.Fatalf("source %q rendered event handler %s: %q", source, attr.Key, out)
+			}
+			if key == "style" || key == "srcdoc" {
+				t.Fatalf("source %q rendered attribute %s: %q", source, attr.Key, out)
+			}
+			if urlAttrs[key] && !safeURL(attr.Val) {
+				t.Fatalf("source %q rendered unsafe URL %s=%q: %q", source, attr.Key, attr.Val, out)
+			}
+		}
+	}
+}
+
+// safeURL повторяет то, как браузер определяет схему: управляющие символы и пробелы выбрасываются
+func safeURL(raw string) bool {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(raw))
+
+	colon := strings.IndexByte(cleaned, ':')
+	if colon < 0 {
+		return true
+	}
+	// Двоеточие после пути, запроса или фрагмента не задаёт схему
+	if slash := strings.IndexAny(cleaned, "/?#"); slash >= 0 && slash < colon {
+		return true
+	}
+
+	switch cleaned[:colon] {
+	case "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
+
+func TestRenderStripsExecutableMarkup(t *testing.T) {
+	r := New()
+
+	tests := []struct {
+		name   string
+		source string
+		// Подстрока, которой не должно быть в выводе (в нижнем регистре)
+		absent string
+	}{
+		{"script tag", "<script>alert(1)</script>", "<script"},
+		{"script tag upper case", "<SCRIPT>alert(1)</SCRIPT>", "<script"},
+		{"script inside paragraph", "text <script>alert(1)</script> text", "<script"},
+		{"javascript link", "[click](javascript:alert(1))", "javascript:"},
+		{"javascript link mixed case", "[click](JaVaScRiPt:alert(1))", "javascript:"},
+		{"javascript autolink", "<javascript:alert(1)>", "href"},
+		{"javascript image", "![x](javascript:alert(1))", "javascript:"},
+		{"data link", "[click](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)", "data:"},
+		{"vbscript link", "[click](vbscript:msgbox(1))", "vbscript:"},
+		{"onerror attribute", `<img src="x" onerror="alert(1)">`, "onerror"},
+		{"onclick attribute", `<a href="https://example.com" onclick="alert(1)">x</a>`, "onclick"},
+		{"raw iframe", `<iframe src="https://example.com"></iframe>`, "<iframe"},
+		{"raw style", "<style>body{display:none}</style>", "<style"},
+		{"raw svg", `<svg onload="alert(1)"></svg>`, "<svg"},
+		{"raw html block", "<div>\n<img src=x onerror=alert(1)>\n</div>", "onerror"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := r.Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if strings.Contains(strings.ToLower(out), tt.absent) {
+				t.Errorf("Render(%q) = %q, must not contain %q", tt.source, out, tt.absent)
+			}
+			assertSafe(t, tt.source, out)
+		})
+	}
+}
+
+func TestRenderKeepsMarkdown(t *testing.T) {
+	r := New()
+
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"emphasis", "*hi*", "<em>hi</em>"},
+		{"heading", "# Title", "<h1>Title</h1>"},
+		{"https link", "[x](https://example.com)", `<a href="https://example.com" rel="nofollow noreferrer">x</a>`},
+		{"code language", "```go\nx := 1\n```", `<code class="language-go">`},
+		{"escaped text", "1 < 2", "1 &lt; 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := r.Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("Render(%q) = %q, want it to contain %q", tt.source, out, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzRender(f *testing.F) {
+	seeds := []string{
+		"<script>alert(1)</script>",
+		"<ScRiPt>alert(1)</sCrIpT>",
+		"[x](javascript:alert(1))",
+		"[x](java\tscript:alert(1))",
+		"[x](&#106;avascript:alert(1))",
+		"![x](javascript:alert(1))",
+		"<javascript:alert(1)>",
+		`<img src=x onerror=alert(1)>`,
+		`<a href="javascript:alert(1)">x</a>`,
+		"<iframe srcdoc='<script>alert(1)</script>'></iframe>",
+		"[x](data:text/html,<script>alert(1)</script>)",
+		"- [ ] task\n- [x] done",
+		"```html\n<script>alert(1)</script>\n```",
+		"| a | b |\n|---|---|\n| <script> | x |",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	r := New()
+	f.Fuzz(func(t *testing.T, source string) {
+		out, err := r.Render(source)
+		if err != nil {
+			return
+		}
+
+		assertSafe(t, source, out)
+	})
+}